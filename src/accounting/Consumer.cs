@@ -2,6 +2,7 @@
 // SPDX-License-Identifier: Apache-2.0
 
 using Confluent.Kafka;
+using Google.Protobuf;
 using Microsoft.Extensions.Logging;
 using Oteldemo;
 using Microsoft.EntityFrameworkCore;
@@ -34,6 +35,7 @@ internal class Consumer : IDisposable
     private bool _isListening;
     private string? _connectionString;
     private static readonly ActivitySource MyActivitySource = new("Accounting.Consumer");
+    private static readonly JsonParser OrderJsonParser = new(JsonParser.Settings.Default.WithIgnoreUnknownFields(true));
 
     public Consumer(ILogger<Consumer> logger)
     {
@@ -108,7 +110,7 @@ internal class Consumer : IDisposable
             OrderResult order;
             using (var parseActivity = MyActivitySource.StartActivity("parse-order", ActivityKind.Internal))
             {
-                order = OrderResult.Parser.ParseFrom(message.Value);
+                order = ParseOrder(message, parseActivity);
                 parseActivity?.SetTag("order.id", order.OrderId);
                 parseActivity?.SetTag("order.item_count", order.Items.Count);
             }
@@ -184,6 +186,30 @@ internal class Consumer : IDisposable
         }
     }
 
+    // Order events are protobuf, or JSON if their content-type header says
+    // so. Events published before checkout added the header are protobuf.
+    private static OrderResult ParseOrder(Message<string, byte[]> message, Activity? activity)
+    {
+        var contentType = GetHeader(message.Headers, "content-type");
+        activity?.SetTag("messaging.message.id", GetHeader(message.Headers, "ce_id"));
+        activity?.SetTag("app.order.schema_version", GetHeader(message.Headers, "ce_schemaversion"));
+
+        if (contentType != null && contentType.StartsWith("application/json", StringComparison.OrdinalIgnoreCase))
+        {
+            return OrderJsonParser.Parse<OrderResult>(Encoding.UTF8.GetString(message.Value));
+        }
+        return OrderResult.Parser.ParseFrom(message.Value);
+    }
+
+    private static string? GetHeader(Headers? headers, string key)
+    {
+        if (headers == null || !headers.TryGetLastBytes(key, out var value))
+        {
+            return null;
+        }
+        return Encoding.UTF8.GetString(value);
+    }
+
     private ActivityContext ExtractTraceContextFromKafkaHeaders(Headers headers)
     {
         try
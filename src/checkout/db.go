@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/XSAM/otelsql"
+	_ "github.com/lib/pq" // Register postgres driver
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/idempotency"
+)
+
+// openDatabase opens the instrumented Postgres connection pool configured by
+// DB_CONNECTION_STRING.
+func openDatabase() (*sql.DB, error) {
+	connStr := os.Getenv("DB_CONNECTION_STRING")
+	if connStr == "" {
+		return nil, fmt.Errorf("environment variable %q not set", "DB_CONNECTION_STRING")
+	}
+
+	db, err := otelsql.Open("postgres", connStr,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := otelsql.RegisterDBStatsMetrics(db, otelsql.WithAttributes(semconv.DBSystemPostgreSQL)); err != nil {
+		logger.Warn(fmt.Sprintf("failed to register DB stats metrics: %v", err))
+	}
+
+	db.SetMaxOpenConns(10)
+	db.SetMaxIdleConns(2)
+	db.SetConnMaxLifetime(5 * time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return db, nil
+}
+
+// database returns the shared Postgres connection, opening it on first use.
+func (cs *checkout) database() (*sql.DB, error) {
+	if cs.db != nil {
+		return cs.db, nil
+	}
+	db, err := openDatabase()
+	if err != nil {
+		return nil, err
+	}
+	cs.db = db
+	return db, nil
+}
+
+// defaultIdempotencyLease is well above the worst case of an order with the
+// default resilience policy: each of its ten or so calls in sequence may
+// take three attempts of 5s, plus backoff.
+const defaultIdempotencyLease = 10 * time.Minute
+
+// createIdempotencyStore builds the store selected by
+// CHECKOUT_IDEMPOTENCY_STORE ("memory" or "postgres"). It falls back to the
+// in-memory store if the database is not reachable.
+func (cs *checkout) createIdempotencyStore() idempotency.Store {
+	ttl := 24 * time.Hour
+	if v := os.Getenv("CHECKOUT_IDEMPOTENCY_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			logger.Warn(fmt.Sprintf("invalid CHECKOUT_IDEMPOTENCY_TTL %q, using %s", v, ttl))
+		} else {
+			ttl = d
+		}
+	}
+
+	// The lease of a reservation must outlast the slowest order, including
+	// the retries of each dependency call, or a retried request could place
+	// the order a second time.
+	lease := defaultIdempotencyLease
+	if v := os.Getenv("CHECKOUT_IDEMPOTENCY_LEASE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			logger.Warn(fmt.Sprintf("invalid CHECKOUT_IDEMPOTENCY_LEASE %q, using %s", v, lease))
+		} else {
+			lease = d
+		}
+	}
+
+	switch kind := os.Getenv("CHECKOUT_IDEMPOTENCY_STORE"); kind {
+	case "", "memory":
+	case "postgres":
+		db, err := cs.database()
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to open idempotency database, using in-memory store: %v", err))
+			break
+		}
+		return idempotency.NewPostgresStore(db, ttl, lease, logger)
+	default:
+		logger.Warn(fmt.Sprintf("unknown CHECKOUT_IDEMPOTENCY_STORE %q, using in-memory store", kind))
+	}
+	return idempotency.NewMemoryStore(ttl)
+}
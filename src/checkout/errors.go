@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/pricing"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/protohttp"
+)
+
+// The stages of placing an order that can fail.
+const (
+	stageCart     = "cart"
+	stageCatalog  = "catalog"
+	stageCurrency = "currency"
+	stageQuote    = "quote"
+	stagePricing  = "pricing"
+	stagePayment  = "payment"
+	stageShipping = "shipping"
+	stageEvents   = "events"
+)
+
+// errorDomain is the domain of the ErrorInfo details of checkout errors.
+const errorDomain = "checkout.opentelemetry-demo"
+
+// stageError is a failure of a stage of placing an order, usually a call to
+// another service.
+type stageError struct {
+	stage string
+	err   error
+}
+
+func newStageError(stage string, err error) error {
+	return &stageError{stage: stage, err: err}
+}
+
+func (e *stageError) Error() string { return e.err.Error() }
+
+func (e *stageError) Unwrap() error { return e.err }
+
+// code returns the status code of the failure: the code of the service that
+// failed for client errors and outages, and Internal for anything else.
+func (e *stageError) code() codes.Code {
+	switch {
+	case errors.Is(e.err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(e.err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(e.err, pricing.ErrInvalidCoupon):
+		return codes.InvalidArgument
+	}
+
+	var httpErr *protohttp.StatusError
+	if errors.As(e.err, &httpErr) {
+		return httpCode(httpErr.StatusCode)
+	}
+	var urlErr *url.Error
+	if errors.As(e.err, &urlErr) {
+		// The service could not be reached.
+		return codes.Unavailable
+	}
+
+	if st, ok := status.FromError(e.err); ok {
+		switch c := st.Code(); c {
+		case codes.InvalidArgument, codes.NotFound, codes.FailedPrecondition, codes.OutOfRange,
+			codes.PermissionDenied, codes.Unauthenticated,
+			codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Canceled:
+			return c
+		}
+	}
+	return codes.Internal
+}
+
+// upstreamCode returns the status code returned by the service that failed,
+// if it is a gRPC service.
+func (e *stageError) upstreamCode() (codes.Code, bool) {
+	st, ok := status.FromError(e.err)
+	if !ok {
+		return codes.Unknown, false
+	}
+	return st.Code(), true
+}
+
+func (e *stageError) errorInfo() *errdetails.ErrorInfo {
+	info := &errdetails.ErrorInfo{
+		Reason:   strings.ToUpper(e.stage) + "_FAILED",
+		Domain:   errorDomain,
+		Metadata: map[string]string{"stage": e.stage},
+	}
+	if c, ok := e.upstreamCode(); ok {
+		info.Metadata["upstream_code"] = c.String()
+	}
+	var httpErr *protohttp.StatusError
+	if errors.As(e.err, &httpErr) {
+		info.Metadata["upstream_http_status"] = fmt.Sprint(httpErr.StatusCode)
+	}
+	return info
+}
+
+// uncompensatedError is a failure to place an order whose completed stages
+// could not all be rolled back, e.g. whose charge was not refunded. It keeps
+// the status of the failure.
+type uncompensatedError struct {
+	err error
+}
+
+func (e *uncompensatedError) Error() string { return e.err.Error() }
+
+func (e *uncompensatedError) Unwrap() error { return e.err }
+
+func (e *uncompensatedError) GRPCStatus() *status.Status { return status.Convert(e.err) }
+
+// abortedOrderStatus returns the status of err, the failure of a stage, once
+// saga has been aborted, marking it if a compensation failed.
+func abortedOrderStatus(ctx context.Context, saga *orderSaga, stage string, err error) error {
+	if compensationErr := saga.abort(ctx, stage, err); compensationErr != nil {
+		return &uncompensatedError{err: orderStatus(err)}
+	}
+	return orderStatus(err)
+}
+
+// failedStage returns the stage err is a failure of, if any.
+func failedStage(err error) (string, bool) {
+	var se *stageError
+	if !errors.As(err, &se) {
+		return "", false
+	}
+	return se.stage, true
+}
+
+// orderStatus returns err, a failure to place an order, as a status error.
+// Stage failures get the code of the stage and an ErrorInfo naming it, other
+// errors are Internal.
+func orderStatus(err error) error {
+	var se *stageError
+	if !errors.As(err, &se) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	st := status.New(se.code(), err.Error())
+	if detailed, detailsErr := st.WithDetails(se.errorInfo()); detailsErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// httpCode maps an HTTP status to a status code as gRPC-gateway does the
+// reverse.
+func httpCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	return codes.Internal
+}
@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/pricing"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/protohttp"
+)
+
+func TestOrderStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		want     codes.Code
+		upstream string
+	}{
+		{"product not found", newStageError(stageCatalog, status.Error(codes.NotFound, "no such product")), codes.NotFound, "NotFound"},
+		{"payment unavailable", newStageError(stagePayment, fmt.Errorf("could not charge the card: %w", status.Error(codes.Unavailable, "down"))), codes.Unavailable, "Unavailable"},
+		{"payment declined", newStageError(stagePayment, status.Error(codes.Unknown, "card declined")), codes.Internal, "Unknown"},
+		{"cart deadline", newStageError(stageCart, fmt.Errorf("failed: %w", context.DeadlineExceeded)), codes.DeadlineExceeded, ""},
+		{"quote unreachable", newStageError(stageQuote, &url.Error{Op: "Post", URL: "http://shipping", Err: errors.New("connection refused")}), codes.Unavailable, ""},
+		{"shipping 503", newStageError(stageShipping, &protohttp.StatusError{Service: "shipping", Path: "/ship-order", StatusCode: 503}), codes.Unavailable, ""},
+		{"shipping 500", newStageError(stageShipping, &protohttp.StatusError{Service: "shipping", Path: "/ship-order", StatusCode: 500}), codes.Internal, ""},
+		{"invalid coupon", newStageError(stagePricing, fmt.Errorf("%w: unknown code", pricing.ErrInvalidCoupon)), codes.InvalidArgument, ""},
+		{"currency internal", newStageError(stageCurrency, status.Error(codes.Internal, "boom")), codes.Internal, "Internal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Wrapping the stage error keeps its stage.
+			err := orderStatus(fmt.Errorf("prepare: %w", tt.err))
+
+			st := status.Convert(err)
+			if st.Code() != tt.want {
+				t.Errorf("code = %v, want %v", st.Code(), tt.want)
+			}
+			if st.Message() != "prepare: "+tt.err.Error() {
+				t.Errorf("message = %q", st.Message())
+			}
+			details := st.Details()
+			if len(details) != 1 {
+				t.Fatalf("details = %v, want an ErrorInfo", details)
+			}
+			info, ok := details[0].(*errdetails.ErrorInfo)
+			if !ok {
+				t.Fatalf("details[0] = %T, want an ErrorInfo", details[0])
+			}
+			stage, _ := failedStage(tt.err)
+			if info.GetDomain() != errorDomain || info.GetMetadata()["stage"] != stage {
+				t.Errorf("ErrorInfo = %v, want stage %q", info, stage)
+			}
+			if got := info.GetMetadata()["upstream_code"]; got != tt.upstream {
+				t.Errorf("upstream_code = %q, want %q", got, tt.upstream)
+			}
+		})
+	}
+}
+
+func TestOrderStatus_otherErrors(t *testing.T) {
+	st := status.Convert(orderStatus(errors.New("boom")))
+	if st.Code() != codes.Internal || len(st.Details()) != 0 {
+		t.Errorf("orderStatus() = %v, want Internal without details", st)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/kafka"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/outbox"
+)
+
+// Values of CHECKOUT_ORDER_EVENTS_MODE.
+const (
+	// orderEventsDirect hands order events to the Kafka producer in the
+	// order path, without waiting for the broker.
+	orderEventsDirect = "direct"
+
+	// orderEventsOutbox records order events in an outbox, from which a
+	// relay publishes them with acknowledgements and retries.
+	orderEventsOutbox = "outbox"
+)
+
+// createOutboxStore opens the outbox selected by CHECKOUT_OUTBOX_STORE:
+// "file" (the default) keeps a log in CHECKOUT_OUTBOX_DIR, "postgres" uses
+// the checkout_outbox table. The directory must be set rather than defaulting
+// to a temporary one, since events would not outlive the container.
+func (cs *checkout) createOutboxStore() (outbox.Store, error) {
+	switch kind := os.Getenv("CHECKOUT_OUTBOX_STORE"); kind {
+	case "", "file":
+		dir := os.Getenv("CHECKOUT_OUTBOX_DIR")
+		if dir == "" {
+			return nil, fmt.Errorf("environment variable %q not set", "CHECKOUT_OUTBOX_DIR")
+		}
+		return outbox.OpenFileStore(dir)
+	case "postgres":
+		db, err := cs.database()
+		if err != nil {
+			return nil, err
+		}
+		return outbox.NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown CHECKOUT_OUTBOX_STORE %q", kind)
+	}
+}
+
+// loadKafkaConfig loads the config of the order events producer from the
+// file at CHECKOUT_KAFKA_CONFIG, if set, and the environment. An invalid
+// config is an error rather than falling back to the defaults, which could
+// drop the TLS and SASL settings or the delivery profile.
+func loadKafkaConfig() (*kafka.ProducerConfig, error) {
+	config, err := kafka.LoadProducerConfig(os.Getenv("CHECKOUT_KAFKA_CONFIG"), os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// setupOutbox creates the outbox and its relay, publishing with the brokers
+// of cs.kafkaConfig. The relay is started by the caller.
+func (cs *checkout) setupOutbox() error {
+	store, err := cs.createOutboxStore()
+	if err != nil {
+		return fmt.Errorf("failed to open outbox: %w", err)
+	}
+	producer, err := kafka.CreateSyncProducer(*cs.kafkaConfig, logger)
+	if err != nil {
+		store.Close()
+		return fmt.Errorf("failed to create outbox producer: %w", err)
+	}
+	relay, err := outbox.NewRelay(store, producer, cs.kafkaConfig.Topic, logger)
+	if err != nil {
+		producer.Close()
+		store.Close()
+		return err
+	}
+
+	cs.outbox = store
+	cs.outboxProducer = producer
+	cs.outboxRelay = relay
+	return nil
+}
+
+// closeOutbox releases the outbox once its relay has stopped.
+func (cs *checkout) closeOutbox() {
+	if err := cs.outboxProducer.Close(); err != nil {
+		logger.Warn(fmt.Sprintf("failed to close outbox producer: %v", err))
+	}
+	if err := cs.outbox.Close(); err != nil {
+		logger.Warn(fmt.Sprintf("failed to close outbox: %v", err))
+	}
+}
+
+// appendToOutbox records the order event for the relay, to be published
+// with key. The order fails if the event cannot be recorded, rather than
+// losing it to a direct send the outbox was chosen to avoid.
+func (cs *checkout) appendToOutbox(ctx context.Context, key string, result *pb.OrderResult) error {
+	span := trace.SpanFromContext(ctx)
+
+	orderEvent, err := cs.newOrderEvent(result)
+	if err != nil {
+		return err
+	}
+
+	// The relay continues the order's trace from these headers, and
+	// publishes the event's attributes with it.
+	headers := propagation.MapCarrier(orderEvent.Headers())
+	otel.GetTextMapPropagator().Inject(ctx, headers)
+
+	event := &outbox.Event{OrderID: result.OrderId, Key: key, Payload: orderEvent.Data, Headers: headers}
+	if err := cs.outbox.Append(ctx, event); err != nil {
+		return fmt.Errorf("failed to append order event to outbox: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int64("app.outbox.event.id", event.ID))
+	cs.outboxRelay.Notify()
+	return nil
+}
+
+// newOrderEvent returns the event of the placed order result, encoded as
+// the producer is configured to.
+func (cs *checkout) newOrderEvent(result *pb.OrderResult) (kafka.Event, error) {
+	return kafka.NewEvent(kafka.OrderPlacedType, kafka.OrderSchemaVersion, result.OrderId, result, cs.kafkaConfig.Encoding)
+}
+
+// runOutboxReplay implements the outbox-replay command, which publishes
+// already delivered order events again, e.g. after a consumer lost data. A
+// file outbox is locked by the running service, so checkout must be stopped
+// first; a postgres outbox can be replayed while it runs.
+func runOutboxReplay(args []string) error {
+	fs := flag.NewFlagSet("outbox-replay", flag.ContinueOnError)
+	since := fs.Duration("since", time.Hour, "replay events created within this duration")
+	orderID := fs.String("order", "", "only replay the events of this order")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	kafkaConfig, err := kafka.LoadProducerConfig(os.Getenv("CHECKOUT_KAFKA_CONFIG"), os.Getenv)
+	if err != nil {
+		return err
+	}
+	if len(kafkaConfig.Brokers) == 0 {
+		return fmt.Errorf("environment variable %q not set", "KAFKA_ADDR")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	cs := &checkout{kafkaConfig: &kafkaConfig}
+	if err := cs.setupOutbox(); err != nil {
+		if errors.Is(err, outbox.ErrLogInUse) {
+			return fmt.Errorf("%w: stop checkout before replaying a file outbox", err)
+		}
+		return err
+	}
+	defer cs.closeOutbox()
+	if cs.db != nil {
+		defer cs.db.Close()
+	}
+
+	requeued, err := cs.outbox.Requeue(ctx, outbox.Filter{Since: time.Now().Add(-*since), OrderID: *orderID})
+	if err != nil {
+		return err
+	}
+	published, err := cs.outboxRelay.Drain(ctx)
+	fmt.Printf("requeued %d order events, published %d\n", requeued, published)
+	return err
+}
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Dependency is a downstream service probed by the Checker. Its status is
+// published on the health server under Name.
+type Dependency struct {
+	Name  string
+	Probe Probe
+
+	// Critical dependencies take the whole service out of SERVING when
+	// they are unreachable.
+	Critical bool
+}
+
+// Checker periodically probes dependencies and publishes their statuses,
+// plus the aggregated status of the service itself, through a health.Server.
+// Clients can read them with Check or stream changes with Watch.
+type Checker struct {
+	server   *health.Server
+	services []string
+	deps     []Dependency
+	interval time.Duration
+	timeout  time.Duration
+	logger   *slog.Logger
+
+	mu     sync.Mutex
+	up     map[string]bool
+	probed bool
+}
+
+// NewChecker creates a Checker. services are the names under which the
+// aggregated status is published, typically "" and the gRPC service name.
+// All statuses start as NOT_SERVING until the first probe round completes.
+func NewChecker(server *health.Server, services []string, interval, timeout time.Duration, logger *slog.Logger, deps ...Dependency) (*Checker, error) {
+	c := &Checker{
+		server:   server,
+		services: services,
+		deps:     deps,
+		interval: interval,
+		timeout:  timeout,
+		logger:   logger,
+		up:       make(map[string]bool, len(deps)),
+	}
+
+	for _, name := range services {
+		server.SetServingStatus(name, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	for _, d := range deps {
+		server.SetServingStatus(d.Name, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+
+	meter := otel.Meter("checkout")
+	_, err := meter.Int64ObservableGauge(
+		"app.checkout.dependency.up",
+		metric.WithDescription("Whether a downstream dependency of checkout is reachable (1) or not (0)."),
+		metric.WithInt64Callback(c.observe),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dependency gauge: %w", err)
+	}
+	return c, nil
+}
+
+// Run probes all dependencies immediately and then every interval until ctx
+// is done. On return all statuses are set to NOT_SERVING, so Watch clients
+// learn about the shutdown.
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.CheckOnce(ctx)
+		select {
+		case <-ctx.Done():
+			c.server.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckOnce probes all dependencies concurrently and publishes the results.
+func (c *Checker) CheckOnce(ctx context.Context) {
+	results := make([]error, len(c.deps))
+
+	var wg sync.WaitGroup
+	for i, d := range c.deps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pctx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+			results[i] = d.Probe.Probe(pctx)
+		}()
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	serving := true
+	for i, d := range c.deps {
+		up := results[i] == nil
+		if !up && d.Critical {
+			serving = false
+		}
+
+		if was, ok := c.up[d.Name]; !c.probed || !ok || was != up {
+			if up {
+				c.logger.Info(fmt.Sprintf("dependency %q is reachable", d.Name))
+			} else {
+				c.logger.Warn(fmt.Sprintf("dependency %q is unreachable: %v", d.Name, results[i]))
+			}
+		}
+		c.up[d.Name] = up
+		c.server.SetServingStatus(d.Name, servingStatus(up))
+	}
+	c.probed = true
+
+	for _, name := range c.services {
+		c.server.SetServingStatus(name, servingStatus(serving))
+	}
+}
+
+func (c *Checker) observe(_ context.Context, o metric.Int64Observer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, up := range c.up {
+		var v int64
+		if up {
+			v = 1
+		}
+		o.Observe(v, metric.WithAttributes(attribute.String("app.dependency.name", name)))
+	}
+	return nil
+}
+
+func servingStatus(up bool) healthpb.HealthCheckResponse_ServingStatus {
+	if up {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func status(t *testing.T, s *health.Server, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check(%q) err = %v", service, err)
+	}
+	return resp.GetStatus()
+}
+
+func TestCheckerPublishesStatuses(t *testing.T) {
+	var paymentErr, emailErr error
+	payment := ProbeFunc(func(context.Context) error { return paymentErr })
+	email := ProbeFunc(func(context.Context) error { return emailErr })
+
+	s := health.NewServer()
+	c, err := NewChecker(s, []string{"", "oteldemo.CheckoutService"}, time.Second, time.Second,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Dependency{Name: "payment", Probe: payment, Critical: true},
+		Dependency{Name: "email", Probe: email},
+	)
+	if err != nil {
+		t.Fatalf("NewChecker() err = %v", err)
+	}
+
+	if got := status(t, s, ""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("status before first probe = %v, want NOT_SERVING", got)
+	}
+
+	tests := []struct {
+		name        string
+		paymentErr  error
+		emailErr    error
+		wantOverall healthpb.HealthCheckResponse_ServingStatus
+		wantEmail   healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{"all up", nil, nil, healthpb.HealthCheckResponse_SERVING, healthpb.HealthCheckResponse_SERVING},
+		{"non-critical down", nil, errors.New("down"), healthpb.HealthCheckResponse_SERVING, healthpb.HealthCheckResponse_NOT_SERVING},
+		{"critical down", errors.New("down"), nil, healthpb.HealthCheckResponse_NOT_SERVING, healthpb.HealthCheckResponse_SERVING},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paymentErr, emailErr = tt.paymentErr, tt.emailErr
+			c.CheckOnce(context.Background())
+
+			for _, svc := range []string{"", "oteldemo.CheckoutService"} {
+				if got := status(t, s, svc); got != tt.wantOverall {
+					t.Errorf("status(%q) = %v, want %v", svc, got, tt.wantOverall)
+				}
+			}
+			if got := status(t, s, "email"); got != tt.wantEmail {
+				t.Errorf("status(email) = %v, want %v", got, tt.wantEmail)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/IBM/sarama"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// Probe checks whether a single downstream dependency is reachable.
+type Probe interface {
+	Probe(ctx context.Context) error
+}
+
+// ProbeFunc adapts a function to the Probe interface.
+type ProbeFunc func(ctx context.Context) error
+
+func (f ProbeFunc) Probe(ctx context.Context) error { return f(ctx) }
+
+// GRPCConnProbe reports a gRPC client connection as healthy once it is
+// READY. Idle connections are asked to connect, so a dependency that was
+// never called is still probed.
+func GRPCConnProbe(conn *grpc.ClientConn) Probe {
+	return ProbeFunc(func(ctx context.Context) error {
+		state := conn.GetState()
+		if state == connectivity.Idle {
+			conn.Connect()
+		}
+		for state != connectivity.Ready {
+			switch state {
+			case connectivity.TransientFailure, connectivity.Shutdown:
+				return fmt.Errorf("connection to %s is %s", conn.Target(), state)
+			}
+			if !conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("connection to %s still %s: %w", conn.Target(), state, ctx.Err())
+			}
+			state = conn.GetState()
+		}
+		return nil
+	})
+}
+
+// HTTPProbe reports an HTTP service as healthy if it answers a GET on url
+// with any non-5xx status.
+func HTTPProbe(client *http.Client, url string) Probe {
+	return ProbeFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("GET %s failed: %w", url, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("GET %s: unexpected status %d", url, resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// KafkaProbe reports Kafka as healthy if broker metadata for topic can be
+// refreshed. The client is created on first use and recreated after a
+// failure, so the probe recovers from a broker that was down at startup.
+type KafkaProbe struct {
+	topic     string
+	newClient func() (sarama.Client, error)
+
+	mu     sync.Mutex
+	client sarama.Client
+}
+
+func NewKafkaProbe(topic string, newClient func() (sarama.Client, error)) *KafkaProbe {
+	return &KafkaProbe{topic: topic, newClient: newClient}
+}
+
+func (p *KafkaProbe) Probe(ctx context.Context) error {
+	// sarama calls are not context aware, so run them in the background and
+	// give up waiting when ctx expires.
+	errCh := make(chan error, 1)
+	go func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if p.client == nil {
+			client, err := p.newClient()
+			if err != nil {
+				errCh <- fmt.Errorf("failed to create kafka client: %w", err)
+				return
+			}
+			p.client = client
+		}
+		if err := p.client.RefreshMetadata(p.topic); err != nil {
+			p.client.Close()
+			p.client = nil
+			errCh <- fmt.Errorf("failed to refresh kafka metadata: %w", err)
+			return
+		}
+		errCh <- nil
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("kafka metadata request timed out: %w", ctx.Err())
+	}
+}
+
+// Close releases the probe's Kafka client.
+func (p *KafkaProbe) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.client == nil {
+		return nil
+	}
+	err := p.client.Close()
+	p.client = nil
+	return err
+}
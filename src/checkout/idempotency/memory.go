@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+type entry struct {
+	result  *pb.OrderResult
+	failed  bool
+	expires time.Time
+}
+
+// done reports whether the order of e has an outcome.
+func (e *entry) done() bool { return e.result != nil || e.failed }
+
+// MemoryStore is a Store that keeps keys in process memory. Completed and
+// failed keys are forgotten after ttl.
+type MemoryStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]*entry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// sweepInterval bounds how often Begin scans for expired keys.
+const sweepInterval = time.Minute
+
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		ttl:     ttl,
+		entries: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Begin(_ context.Context, key string) (*pb.OrderResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) > sweepInterval {
+		s.evictExpired(now)
+		s.lastSweep = now
+	}
+
+	if e, ok := s.entries[key]; ok && !now.After(e.expires) {
+		if e.failed {
+			return nil, ErrFailed
+		}
+		if e.result == nil {
+			return nil, ErrInFlight
+		}
+		return proto.Clone(e.result).(*pb.OrderResult), nil
+	}
+	s.entries[key] = &entry{expires: now.Add(s.ttl)}
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key string, result *pb.OrderResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || e.done() {
+		return ErrNotOwned
+	}
+	e.result = proto.Clone(result).(*pb.OrderResult)
+	e.expires = s.now().Add(s.ttl)
+	return nil
+}
+
+func (s *MemoryStore) Abandon(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || e.done() {
+		return ErrNotOwned
+	}
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *MemoryStore) Fail(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || e.done() {
+		return ErrNotOwned
+	}
+	e.failed = true
+	e.expires = s.now().Add(s.ttl)
+	return nil
+}
+
+func (s *MemoryStore) evictExpired(now time.Time) {
+	for k, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+func TestMemoryStoreReplay(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(time.Hour)
+
+	if got, err := s.Begin(ctx, "k"); got != nil || err != nil {
+		t.Fatalf("Begin() on new key = (%v, %v), want (nil, nil)", got, err)
+	}
+	if _, err := s.Begin(ctx, "k"); !errors.Is(err, ErrInFlight) {
+		t.Fatalf("Begin() on in-flight key err = %v, want %v", err, ErrInFlight)
+	}
+	if err := s.Complete(ctx, "k", &pb.OrderResult{OrderId: "order-1"}); err != nil {
+		t.Fatalf("Complete() err = %v", err)
+	}
+	got, err := s.Begin(ctx, "k")
+	if err != nil {
+		t.Fatalf("Begin() on completed key err = %v", err)
+	}
+	if got.GetOrderId() != "order-1" {
+		t.Errorf("Begin() on completed key = %q, want %q", got.GetOrderId(), "order-1")
+	}
+}
+
+func TestMemoryStoreAbandon(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(time.Hour)
+
+	if _, err := s.Begin(ctx, "k"); err != nil {
+		t.Fatalf("Begin() err = %v", err)
+	}
+	if err := s.Abandon(ctx, "k"); err != nil {
+		t.Fatalf("Abandon() err = %v", err)
+	}
+	if got, err := s.Begin(ctx, "k"); got != nil || err != nil {
+		t.Errorf("Begin() after Abandon() = (%v, %v), want (nil, nil)", got, err)
+	}
+	if err := s.Abandon(ctx, "other"); !errors.Is(err, ErrNotOwned) {
+		t.Errorf("Abandon() on unknown key err = %v, want %v", err, ErrNotOwned)
+	}
+}
+
+func TestMemoryStoreFail(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(time.Hour)
+
+	if _, err := s.Begin(ctx, "k"); err != nil {
+		t.Fatalf("Begin() err = %v", err)
+	}
+	if err := s.Fail(ctx, "k"); err != nil {
+		t.Fatalf("Fail() err = %v", err)
+	}
+	if _, err := s.Begin(ctx, "k"); !errors.Is(err, ErrFailed) {
+		t.Errorf("Begin() on failed key err = %v, want %v", err, ErrFailed)
+	}
+	if err := s.Abandon(ctx, "k"); !errors.Is(err, ErrNotOwned) {
+		t.Errorf("Abandon() on failed key err = %v, want %v", err, ErrNotOwned)
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(0, 0)
+	s := NewMemoryStore(time.Minute)
+	s.now = func() time.Time { return now }
+
+	if _, err := s.Begin(ctx, "k"); err != nil {
+		t.Fatalf("Begin() err = %v", err)
+	}
+	if err := s.Complete(ctx, "k", &pb.OrderResult{OrderId: "order-1"}); err != nil {
+		t.Fatalf("Complete() err = %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if got, err := s.Begin(ctx, "k"); got != nil || err != nil {
+		t.Errorf("Begin() after ttl = (%v, %v), want (nil, nil)", got, err)
+	}
+}
+
+func TestKeyFromRequest(t *testing.T) {
+	md := metadata.Pairs(MetadataKey, "from-metadata")
+	tests := []struct {
+		name string
+		ctx  context.Context
+		req  *pb.PlaceOrderRequest
+		want string
+	}{
+		{"none", context.Background(), &pb.PlaceOrderRequest{UserId: "u"}, ""},
+		{"field", context.Background(), &pb.PlaceOrderRequest{UserId: "u", IdempotencyKey: "abc"}, "u:abc"},
+		{"metadata", metadata.NewIncomingContext(context.Background(), md), &pb.PlaceOrderRequest{UserId: "u"}, "u:from-metadata"},
+		{"field wins", metadata.NewIncomingContext(context.Background(), md), &pb.PlaceOrderRequest{UserId: "u", IdempotencyKey: "abc"}, "u:abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KeyFromRequest(tt.ctx, tt.req); got != tt.want {
+				t.Errorf("KeyFromRequest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+const (
+	statusInFlight  = "in_flight"
+	statusCompleted = "completed"
+	statusAbandoned = "abandoned"
+	statusFailed    = "failed"
+)
+
+// PostgresStore is a Store backed by the checkout_idempotency table, so
+// replays are detected across checkout replicas and restarts. Keys are
+// forgotten ttl after they were reserved, like those of a MemoryStore.
+type PostgresStore struct {
+	db  *sql.DB
+	ttl time.Duration
+	// lease is how long an in-flight reservation is honoured before another
+	// request may take it over, e.g. after the owning replica crashed. It
+	// must be longer than any order takes, or the order could be placed
+	// twice.
+	lease  time.Duration
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresStore(db *sql.DB, ttl, lease time.Duration, logger *slog.Logger) *PostgresStore {
+	return &PostgresStore{db: db, ttl: ttl, lease: lease, logger: logger}
+}
+
+func (s *PostgresStore) Begin(ctx context.Context, key string) (*pb.OrderResult, error) {
+	s.sweep(ctx)
+
+	reserve := `INSERT INTO checkout_idempotency (idempotency_key, status)
+	            VALUES ($1, $2)
+	            ON CONFLICT (idempotency_key) DO UPDATE
+	              SET status = EXCLUDED.status, order_result = NULL, created_at = NOW(), updated_at = NOW()
+	              WHERE checkout_idempotency.status = $3
+	                 OR (checkout_idempotency.status = $2
+	                     AND checkout_idempotency.updated_at < NOW() - make_interval(secs => $4))
+	                 OR (checkout_idempotency.status IN ($5, $7)
+	                     AND checkout_idempotency.created_at < NOW() - make_interval(secs => $6))
+	            RETURNING idempotency_key`
+
+	var reserved string
+	err := s.db.QueryRowContext(ctx, reserve, key, statusInFlight, statusAbandoned, s.lease.Seconds(),
+		statusCompleted, s.ttl.Seconds(), statusFailed).Scan(&reserved)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	var status string
+	var data []byte
+	err = s.db.QueryRowContext(ctx,
+		`SELECT status, order_result FROM checkout_idempotency WHERE idempotency_key = $1`, key,
+	).Scan(&status, &data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up idempotency key: %w", err)
+	}
+	switch status {
+	case statusCompleted:
+	case statusFailed:
+		return nil, ErrFailed
+	default:
+		return nil, ErrInFlight
+	}
+
+	var result pb.OrderResult
+	if err := proto.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal stored order result: %w", err)
+	}
+	return &result, nil
+}
+
+// sweep deletes the expired keys, and the reservations left by crashed
+// replicas, at most once per sweepInterval. It is best effort: if it fails,
+// it is retried after the next interval, and Begin takes expired keys over
+// meanwhile.
+func (s *PostgresStore) sweep(ctx context.Context) {
+	s.mu.Lock()
+	if time.Since(s.lastSweep) <= sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = time.Now()
+	s.mu.Unlock()
+
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM checkout_idempotency
+		 WHERE created_at < NOW() - make_interval(secs => $2)
+		   AND (status <> $1 OR updated_at < NOW() - make_interval(secs => $3))`,
+		statusInFlight, s.ttl.Seconds(), s.lease.Seconds(),
+	)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("failed to delete expired idempotency keys: %v", err))
+	}
+}
+
+func (s *PostgresStore) Complete(ctx context.Context, key string, result *pb.OrderResult) error {
+	data, err := proto.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal order result: %w", err)
+	}
+	return s.transition(ctx, key, statusCompleted, data)
+}
+
+func (s *PostgresStore) Abandon(ctx context.Context, key string) error {
+	return s.transition(ctx, key, statusAbandoned, nil)
+}
+
+func (s *PostgresStore) Fail(ctx context.Context, key string) error {
+	return s.transition(ctx, key, statusFailed, nil)
+}
+
+func (s *PostgresStore) transition(ctx context.Context, key, status string, data []byte) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE checkout_idempotency
+		 SET status = $2, order_result = $3, updated_at = NOW()
+		 WHERE idempotency_key = $1 AND status = $4`,
+		key, status, data, statusInFlight,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update idempotency key: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotOwned
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+const (
+	testTTL   = 24 * time.Hour
+	testLease = 10 * time.Minute
+)
+
+func newMockPostgresStore(t *testing.T) (*PostgresStore, sqlmock.Sqlmock, *bytes.Buffer) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() err = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	var logs bytes.Buffer
+	return NewPostgresStore(db, testTTL, testLease, slog.New(slog.NewTextHandler(&logs, nil))), mock, &logs
+}
+
+func expectSweep(mock sqlmock.Sqlmock) *sqlmock.ExpectedExec {
+	return mock.ExpectExec(`DELETE FROM checkout_idempotency`).
+		WithArgs(statusInFlight, testTTL.Seconds(), testLease.Seconds())
+}
+
+func expectReserve(mock sqlmock.Sqlmock, key string) *sqlmock.ExpectedQuery {
+	return mock.ExpectQuery(`INSERT INTO checkout_idempotency`).
+		WithArgs(key, statusInFlight, statusAbandoned, testLease.Seconds(), statusCompleted, testTTL.Seconds(), statusFailed)
+}
+
+func expectLookup(mock sqlmock.Sqlmock, key, status string, result []byte) {
+	mock.ExpectQuery(`SELECT status, order_result FROM checkout_idempotency`).
+		WithArgs(key).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "order_result"}).AddRow(status, result))
+}
+
+func TestPostgresStoreReplay(t *testing.T) {
+	ctx := context.Background()
+	s, mock, _ := newMockPostgresStore(t)
+	data, err := proto.Marshal(&pb.OrderResult{OrderId: "order-1"})
+	if err != nil {
+		t.Fatalf("proto.Marshal() err = %v", err)
+	}
+
+	expectSweep(mock).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectReserve(mock, "k").WillReturnError(sql.ErrNoRows)
+	expectLookup(mock, "k", statusCompleted, data)
+
+	got, err := s.Begin(ctx, "k")
+	if err != nil {
+		t.Fatalf("Begin() on completed key err = %v", err)
+	}
+	if got.GetOrderId() != "order-1" {
+		t.Errorf("Begin() on completed key = %q, want %q", got.GetOrderId(), "order-1")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPostgresStoreFailed(t *testing.T) {
+	ctx := context.Background()
+	s, mock, _ := newMockPostgresStore(t)
+
+	expectSweep(mock).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectReserve(mock, "k").WillReturnError(sql.ErrNoRows)
+	expectLookup(mock, "k", statusFailed, nil)
+
+	if _, err := s.Begin(ctx, "k"); !errors.Is(err, ErrFailed) {
+		t.Errorf("Begin() on failed key err = %v, want %v", err, ErrFailed)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// The expiry of keys is decided by Postgres, so these tests check that the
+// store hands it the configured ttl and lease, and acts on its answer.
+
+func TestPostgresStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	s, mock, _ := newMockPostgresStore(t)
+
+	// The completed row is older than the ttl, so the reservation takes it
+	// over and the order is placed again.
+	expectSweep(mock).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectReserve(mock, "k").WillReturnRows(sqlmock.NewRows([]string{"idempotency_key"}).AddRow("k"))
+
+	if got, err := s.Begin(ctx, "k"); got != nil || err != nil {
+		t.Errorf("Begin() after ttl = (%v, %v), want (nil, nil)", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPostgresStoreLease(t *testing.T) {
+	ctx := context.Background()
+	s, mock, _ := newMockPostgresStore(t)
+
+	// Within the lease, the owner of the reservation is still placing the
+	// order.
+	expectSweep(mock).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectReserve(mock, "k").WillReturnError(sql.ErrNoRows)
+	expectLookup(mock, "k", statusInFlight, nil)
+	if _, err := s.Begin(ctx, "k"); !errors.Is(err, ErrInFlight) {
+		t.Fatalf("Begin() on in-flight key err = %v, want %v", err, ErrInFlight)
+	}
+
+	// After the lease, e.g. because the owner crashed, the reservation is
+	// taken over.
+	expectReserve(mock, "k").WillReturnRows(sqlmock.NewRows([]string{"idempotency_key"}).AddRow("k"))
+	if got, err := s.Begin(ctx, "k"); got != nil || err != nil {
+		t.Errorf("Begin() after lease = (%v, %v), want (nil, nil)", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPostgresStoreSweepFailure(t *testing.T) {
+	ctx := context.Background()
+	s, mock, logs := newMockPostgresStore(t)
+
+	expectSweep(mock).WillReturnError(errors.New("permission denied for table checkout_idempotency"))
+	expectReserve(mock, "k1").WillReturnRows(sqlmock.NewRows([]string{"idempotency_key"}).AddRow("k1"))
+	if _, err := s.Begin(ctx, "k1"); err != nil {
+		t.Fatalf("Begin() err = %v", err)
+	}
+	if !strings.Contains(logs.String(), "permission denied") {
+		t.Errorf("sweep failure was not logged, got %q", logs.String())
+	}
+
+	// A failed sweep is not retried before the next interval.
+	expectReserve(mock, "k2").WillReturnRows(sqlmock.NewRows([]string{"idempotency_key"}).AddRow("k2"))
+	if _, err := s.Begin(ctx, "k2"); err != nil {
+		t.Fatalf("Begin() err = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
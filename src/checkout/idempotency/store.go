@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package idempotency
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/metadata"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+// MetadataKey is the gRPC metadata key clients can use instead of the
+// idempotency_key field of PlaceOrderRequest.
+const MetadataKey = "idempotency-key"
+
+var (
+	ErrInFlight = errors.New("a request with the same idempotency key is still in flight")
+	ErrFailed   = errors.New("the order with the same idempotency key failed and was not rolled back")
+	ErrNotOwned = errors.New("idempotency key is not reserved")
+)
+
+// Store keeps track of in-flight and completed orders by idempotency key.
+type Store interface {
+	// Begin reserves key for a new order. If an order was already completed
+	// for key, its result is returned and no reservation is made. If another
+	// request currently holds the reservation, ErrInFlight is returned, and
+	// if the order failed without being rolled back, ErrFailed.
+	Begin(ctx context.Context, key string) (*pb.OrderResult, error)
+
+	// Complete stores the result of the order placed under a reservation.
+	Complete(ctx context.Context, key string, result *pb.OrderResult) error
+
+	// Abandon releases a reservation without a result, so the client can
+	// retry after a failed order.
+	Abandon(ctx context.Context, key string) error
+
+	// Fail records that the order placed under a reservation failed and
+	// could not be rolled back, e.g. its charge was not refunded. The key is
+	// kept like a completed one, so a retry is not charged again.
+	Fail(ctx context.Context, key string) error
+}
+
+// KeyFromRequest returns the idempotency key of req, scoped to its user. The
+// request field takes precedence over the gRPC metadata. An empty string is
+// returned if the client did not supply a key.
+func KeyFromRequest(ctx context.Context, req *pb.PlaceOrderRequest) string {
+	key := req.GetIdempotencyKey()
+	if key == "" {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get(MetadataKey); len(v) > 0 {
+				key = v[0]
+			}
+		}
+	}
+	if key == "" {
+		return ""
+	}
+	return req.GetUserId() + ":" + key
+}
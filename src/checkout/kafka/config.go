@@ -0,0 +1,387 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// The delivery profiles of producers.
+const (
+	// ProfileFireAndForget does not wait for the broker to acknowledge
+	// messages, so they are lost if it fails.
+	ProfileFireAndForget = "fire-and-forget"
+
+	// ProfileAtLeastOnce waits for all in-sync replicas and retries, so a
+	// retried message can be written twice.
+	ProfileAtLeastOnce = "at-least-once"
+
+	// ProfileExactlyOnceIdempotent is ProfileAtLeastOnce with an idempotent
+	// producer, so retries neither duplicate nor reorder messages.
+	ProfileExactlyOnceIdempotent = "exactly-once-idempotent"
+)
+
+// Values of Delivery.Acks.
+const (
+	AcksNone   = "none"
+	AcksLeader = "leader"
+	AcksAll    = "all"
+)
+
+var requiredAcks = map[string]sarama.RequiredAcks{
+	AcksNone:   sarama.NoResponse,
+	AcksLeader: sarama.WaitForLocal,
+	AcksAll:    sarama.WaitForAll,
+}
+
+// Delivery configures how messages are batched and acknowledged.
+type Delivery struct {
+	// Acks is "none", "leader" or "all", the replicas that must store a
+	// message before it is acknowledged.
+	Acks string `json:"acks"`
+
+	// Retries is the number of times a failed send is retried, waiting
+	// RetryBackoff in between.
+	Retries      int      `json:"retries"`
+	RetryBackoff Duration `json:"retry_backoff"`
+
+	// Idempotent makes the broker discard duplicates of retried messages. It
+	// requires all acks, a retry and MaxOpenRequests of 1.
+	Idempotent      bool `json:"idempotent"`
+	MaxOpenRequests int  `json:"max_open_requests"`
+
+	// Compression is "none", "gzip", "snappy", "lz4" or "zstd".
+	Compression sarama.CompressionCodec `json:"compression"`
+
+	// Linger is how long messages are held to be sent in a batch, which is
+	// sent earlier when it reaches BatchSize bytes. Zero sends messages as
+	// soon as possible.
+	Linger    Duration `json:"linger"`
+	BatchSize int      `json:"batch_size"`
+}
+
+// Profiles are the delivery settings of the delivery profiles.
+var Profiles = map[string]Delivery{
+	// Sarama has an issue in a single broker kafka if the kafka broker is
+	// restarted. Not waiting for acknowledgements prevents that issue from
+	// manifesting itself, but may swallow failed messages.
+	ProfileFireAndForget: {
+		Acks:            AcksNone,
+		MaxOpenRequests: 5,
+		Compression:     sarama.CompressionNone,
+	},
+	ProfileAtLeastOnce: {
+		Acks:            AcksAll,
+		Retries:         5,
+		RetryBackoff:    Duration(250 * time.Millisecond),
+		MaxOpenRequests: 5,
+		Compression:     sarama.CompressionLZ4,
+		Linger:          Duration(5 * time.Millisecond),
+		BatchSize:       16 << 10,
+	},
+	ProfileExactlyOnceIdempotent: {
+		Acks:            AcksAll,
+		Retries:         5,
+		RetryBackoff:    Duration(250 * time.Millisecond),
+		Idempotent:      true,
+		MaxOpenRequests: 1,
+		Compression:     sarama.CompressionLZ4,
+		Linger:          Duration(5 * time.Millisecond),
+		BatchSize:       16 << 10,
+	},
+}
+
+// DefaultProfile is the profile of producers that are not configured.
+const DefaultProfile = ProfileFireAndForget
+
+// DefaultPartitionKey keeps the events of each user in order, as fraud
+// detection needs.
+const DefaultPartitionKey = KeyUserID
+
+// ProducerConfig configures the producer of order events.
+type ProducerConfig struct {
+	Brokers []string `json:"brokers"`
+	Topic   string   `json:"topic"`
+
+	// Version is the Kafka protocol version, e.g. "3.0.0".
+	Version string `json:"version"`
+
+	// Profile names the delivery profile the delivery settings default to.
+	Profile string `json:"profile"`
+
+	// PartitionKey is the field of orders their events are keyed by, e.g.
+	// "user_id", and PartitionHash the hash of keys that picks their
+	// partition, e.g. "murmur2".
+	PartitionKey  string `json:"partition_key"`
+	PartitionHash string `json:"partition_hash"`
+
+	// Encoding is "protobuf" or "json", the encoding of the data of order
+	// events. Their content-type header tells consumers which it is.
+	Encoding string `json:"encoding"`
+
+	TLS  TLSConfig  `json:"tls"`
+	SASL SASLConfig `json:"sasl"`
+
+	Delivery
+}
+
+// String formats c without the SASL password.
+func (c ProducerConfig) String() string {
+	if c.SASL.Password != "" {
+		c.SASL.Password = "REDACTED"
+	}
+	type plain ProducerConfig
+	return fmt.Sprintf("%+v", plain(c))
+}
+
+// LoadProducerConfig reads the config file at path, e.g.:
+//
+//	{
+//	  "brokers": ["kafka-1:9092", "kafka-2:9092"],
+//	  "profile": "at-least-once",
+//	  "compression": "zstd"
+//	}
+//
+// Settings not in the file are those of the profile, or the defaults. The
+// environment overrides the file: KAFKA_ADDR is a comma-separated list of
+// brokers, and CHECKOUT_KAFKA_<FIELD> sets the other fields, e.g.
+// CHECKOUT_KAFKA_PROFILE=exactly-once-idempotent,
+// CHECKOUT_KAFKA_LINGER=10ms or CHECKOUT_KAFKA_SASL_MECHANISM=SCRAM-SHA-512.
+// An empty path is the same as an empty file.
+func LoadProducerConfig(path string, getenv func(string) string) (ProducerConfig, error) {
+	var data []byte
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return ProducerConfig{}, fmt.Errorf("failed to read kafka producer config: %w", err)
+		}
+	}
+
+	// The profile sets the defaults of the other fields, so it is read
+	// first.
+	var head struct {
+		Profile string `json:"profile"`
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &head); err != nil {
+			return ProducerConfig{}, fmt.Errorf("failed to parse kafka producer config: %w", err)
+		}
+	}
+	if v := getenv("CHECKOUT_KAFKA_PROFILE"); v != "" {
+		head.Profile = v
+	}
+	if head.Profile == "" {
+		head.Profile = DefaultProfile
+	}
+	delivery, ok := Profiles[head.Profile]
+	if !ok {
+		return ProducerConfig{}, fmt.Errorf("unknown kafka producer profile %q", head.Profile)
+	}
+
+	c := ProducerConfig{
+		Topic:         Topic,
+		Version:       ProtocolVersion.String(),
+		PartitionKey:  DefaultPartitionKey,
+		PartitionHash: HashFNV1a,
+		Encoding:      DefaultEncoding,
+		Delivery:      delivery,
+	}
+	if len(data) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&c); err != nil {
+			return ProducerConfig{}, fmt.Errorf("failed to parse kafka producer config: %w", err)
+		}
+	}
+	c.Profile = head.Profile
+
+	if err := c.applyEnv(getenv); err != nil {
+		return ProducerConfig{}, err
+	}
+	if _, err := c.Sarama(); err != nil {
+		return ProducerConfig{}, fmt.Errorf("invalid kafka producer config: %w", err)
+	}
+	return c, nil
+}
+
+// ParseBrokers splits a comma-separated list of broker addresses.
+func ParseBrokers(s string) []string {
+	var brokers []string
+	for _, b := range strings.Split(s, ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			brokers = append(brokers, b)
+		}
+	}
+	return brokers
+}
+
+func (c *ProducerConfig) applyEnv(getenv func(string) string) error {
+	if v := getenv("KAFKA_ADDR"); v != "" {
+		c.Brokers = ParseBrokers(v)
+	}
+
+	strs := map[string]*string{
+		"TOPIC":           &c.Topic,
+		"VERSION":         &c.Version,
+		"PARTITION_KEY":   &c.PartitionKey,
+		"PARTITION_HASH":  &c.PartitionHash,
+		"ENCODING":        &c.Encoding,
+		"ACKS":            &c.Acks,
+		"TLS_CA_FILE":     &c.TLS.CAFile,
+		"TLS_CERT_FILE":   &c.TLS.CertFile,
+		"TLS_KEY_FILE":    &c.TLS.KeyFile,
+		"TLS_SERVER_NAME": &c.TLS.ServerName,
+		"SASL_MECHANISM":  &c.SASL.Mechanism,
+		"SASL_USERNAME":   &c.SASL.Username,
+		"SASL_PASSWORD":   &c.SASL.Password,
+	}
+	for field, s := range strs {
+		if v := getenv("CHECKOUT_KAFKA_" + field); v != "" {
+			*s = v
+		}
+	}
+
+	durations := map[string]*Duration{
+		"RETRY_BACKOFF": &c.RetryBackoff,
+		"LINGER":        &c.Linger,
+	}
+	for field, d := range durations {
+		if v := getenv("CHECKOUT_KAFKA_" + field); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("invalid CHECKOUT_KAFKA_%s: %w", field, err)
+			}
+			*d = Duration(parsed)
+		}
+	}
+
+	ints := map[string]*int{
+		"RETRIES":           &c.Retries,
+		"MAX_OPEN_REQUESTS": &c.MaxOpenRequests,
+		"BATCH_SIZE":        &c.BatchSize,
+	}
+	for field, n := range ints {
+		if v := getenv("CHECKOUT_KAFKA_" + field); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("invalid CHECKOUT_KAFKA_%s: %w", field, err)
+			}
+			*n = parsed
+		}
+	}
+
+	bools := map[string]*bool{
+		"IDEMPOTENT":               &c.Idempotent,
+		"TLS_ENABLED":              &c.TLS.Enabled,
+		"TLS_INSECURE_SKIP_VERIFY": &c.TLS.InsecureSkipVerify,
+		"SASL_ALLOW_CLEARTEXT":     &c.SASL.AllowCleartext,
+	}
+	for field, b := range bools {
+		if v := getenv("CHECKOUT_KAFKA_" + field); v != "" {
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid CHECKOUT_KAFKA_%s: %w", field, err)
+			}
+			*b = parsed
+		}
+	}
+	if v := getenv("CHECKOUT_KAFKA_COMPRESSION"); v != "" {
+		if err := c.Compression.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("invalid CHECKOUT_KAFKA_COMPRESSION: %w", err)
+		}
+	}
+	return nil
+}
+
+// Sarama returns the Sarama config of a producer with c.
+func (c ProducerConfig) Sarama() (*sarama.Config, error) {
+	acks, ok := requiredAcks[c.Acks]
+	if !ok {
+		return nil, fmt.Errorf("unknown acks %q", c.Acks)
+	}
+	partitioner, err := c.partitioner()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case contentTypes[c.Encoding] == "":
+		return nil, fmt.Errorf("unknown event encoding %q", c.Encoding)
+	case c.Topic == "":
+		return nil, errors.New("topic must be set")
+	case c.Retries < 0 || c.BatchSize < 0:
+		return nil, errors.New("retries and batch_size must not be negative")
+	case c.RetryBackoff < 0 || c.Linger < 0:
+		return nil, errors.New("durations must not be negative")
+	case c.BatchSize > 0 && c.Linger == 0:
+		// Otherwise a batch smaller than the size would never be sent.
+		return nil, errors.New("batch_size requires a linger")
+	}
+
+	saramaConfig, err := c.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	saramaConfig.Producer.RequiredAcks = acks
+	saramaConfig.Producer.Retry.Max = c.Retries
+	saramaConfig.Producer.Retry.Backoff = time.Duration(c.RetryBackoff)
+	saramaConfig.Producer.Idempotent = c.Idempotent
+	saramaConfig.Net.MaxOpenRequests = c.MaxOpenRequests
+	saramaConfig.Producer.Compression = c.Compression
+	saramaConfig.Producer.Flush.Frequency = time.Duration(c.Linger)
+	saramaConfig.Producer.Flush.Bytes = c.BatchSize
+	saramaConfig.Producer.Partitioner = partitioner
+
+	// So we can know the partition and offset of messages.
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Return.Errors = true
+
+	if err := saramaConfig.Validate(); err != nil {
+		return nil, err
+	}
+	return saramaConfig, nil
+}
+
+// clientConfig returns the Sarama config of connections to the brokers.
+func (c ProducerConfig) clientConfig() (*sarama.Config, error) {
+	version, err := sarama.ParseKafkaVersion(c.Version)
+	if err != nil {
+		return nil, err
+	}
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = version
+	if err := c.applySecurity(saramaConfig); err != nil {
+		return nil, err
+	}
+	return saramaConfig, nil
+}
+
+// Duration is a time.Duration written in JSON as a string such as "250ms".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid duration %s: %w", data, err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) String() string { return time.Duration(d).String() }
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package kafka
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kafka.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadProducerConfig(t *testing.T) {
+	path := writeConfig(t, `{
+		"brokers": ["kafka-1:9092"],
+		"profile": "at-least-once",
+		"compression": "zstd",
+		"batch_size": 65536
+	}`)
+	env := map[string]string{
+		"KAFKA_ADDR":                    "kafka-1:9092, kafka-2:9092,",
+		"CHECKOUT_KAFKA_LINGER":         "20ms",
+		"CHECKOUT_KAFKA_RETRIES":        "10",
+		"CHECKOUT_KAFKA_PARTITION_HASH": "murmur2",
+		"CHECKOUT_KAFKA_ENCODING":       "json",
+	}
+	c, err := LoadProducerConfig(path, func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("LoadProducerConfig(): unexpected err=\"%v\"", err)
+	}
+
+	want := ProducerConfig{
+		Brokers:       []string{"kafka-1:9092", "kafka-2:9092"},
+		Topic:         "orders",
+		Version:       "3.0.0",
+		Profile:       ProfileAtLeastOnce,
+		PartitionKey:  KeyUserID,
+		PartitionHash: HashMurmur2,
+		Encoding:      EncodingJSON,
+		Delivery: Delivery{
+			Acks:            AcksAll,
+			Retries:         10,
+			RetryBackoff:    Duration(250 * time.Millisecond),
+			MaxOpenRequests: 5,
+			Compression:     sarama.CompressionZSTD,
+			Linger:          Duration(20 * time.Millisecond),
+			BatchSize:       65536,
+		},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("LoadProducerConfig() = %+v, want %+v", c, want)
+	}
+}
+
+func TestLoadProducerConfig_profiles(t *testing.T) {
+	tests := []struct {
+		profile     string
+		acks        sarama.RequiredAcks
+		idempotent  bool
+		openReqs    int
+		compression sarama.CompressionCodec
+	}{
+		{"", sarama.NoResponse, false, 5, sarama.CompressionNone},
+		{ProfileFireAndForget, sarama.NoResponse, false, 5, sarama.CompressionNone},
+		{ProfileAtLeastOnce, sarama.WaitForAll, false, 5, sarama.CompressionLZ4},
+		{ProfileExactlyOnceIdempotent, sarama.WaitForAll, true, 1, sarama.CompressionLZ4},
+	}
+	for _, tt := range tests {
+		env := map[string]string{"KAFKA_ADDR": "kafka:9092", "CHECKOUT_KAFKA_PROFILE": tt.profile}
+		c, err := LoadProducerConfig("", func(k string) string { return env[k] })
+		if err != nil {
+			t.Fatalf("LoadProducerConfig(%q): unexpected err=\"%v\"", tt.profile, err)
+		}
+		sc, err := c.Sarama()
+		if err != nil {
+			t.Fatalf("Sarama(%q): unexpected err=\"%v\"", tt.profile, err)
+		}
+		if sc.Producer.RequiredAcks != tt.acks || sc.Producer.Idempotent != tt.idempotent ||
+			sc.Net.MaxOpenRequests != tt.openReqs || sc.Producer.Compression != tt.compression {
+			t.Errorf("profile %q: acks=%v idempotent=%v max open requests=%d compression=%v", tt.profile,
+				sc.Producer.RequiredAcks, sc.Producer.Idempotent, sc.Net.MaxOpenRequests, sc.Producer.Compression)
+		}
+		if sc.Version != sarama.V3_0_0_0 || !sc.Producer.Return.Successes {
+			t.Errorf("profile %q: version=%v return successes=%v", tt.profile, sc.Version, sc.Producer.Return.Successes)
+		}
+	}
+}
+
+func TestLoadProducerConfig_invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		wantErr string
+	}{
+		{"unknown profile", "", map[string]string{"CHECKOUT_KAFKA_PROFILE": "at-most-twice"}, "unknown kafka producer profile"},
+		{"unknown field", `{"lingr": "5ms"}`, nil, "unknown field"},
+		{"unknown acks", "", map[string]string{"CHECKOUT_KAFKA_ACKS": "some"}, "unknown acks"},
+		{"unknown compression", `{"compression": "brotli"}`, nil, "compression codec"},
+		{"batch without linger", `{"batch_size": 1024}`, nil, "batch_size requires a linger"},
+		{"idempotent without all acks", `{"profile": "exactly-once-idempotent", "acks": "leader"}`, nil, "Idempotent"},
+		{"unknown encoding", `{"encoding": "avro"}`, nil, "unknown event encoding"},
+		{"bad version", "", map[string]string{"CHECKOUT_KAFKA_VERSION": "three"}, "version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ""
+			if tt.file != "" {
+				path = writeConfig(t, tt.file)
+			}
+			_, err := LoadProducerConfig(path, func(k string) string { return tt.env[k] })
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadProducerConfig(): err=\"%v\", want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseBrokers(t *testing.T) {
+	got := ParseBrokers(" kafka-1:9092,,kafka-2:9092 ")
+	if want := []string{"kafka-1:9092", "kafka-2:9092"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseBrokers() = %v, want %v", got, want)
+	}
+	if got := ParseBrokers(""); len(got) != 0 {
+		t.Errorf("ParseBrokers(\"\") = %v, want none", got)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Producer is the producer of order events. It reads the delivery reports
+// of all its messages, so the producer never blocks on a full Successes or
+// Errors channel, and tracks the deliveries of the messages given to Send.
+type Producer struct {
+	sarama.AsyncProducer
+
+	logger *slog.Logger
+
+	// acked tells whether the broker acknowledges messages, and so whether
+	// delivered messages have an offset.
+	acked bool
+
+	duration metric.Float64Histogram
+	failures metric.Int64Counter
+
+	// mu is held for reading while messages are handed to the producer, so
+	// none is after it is closed.
+	mu     sync.RWMutex
+	closed bool
+
+	wg sync.WaitGroup
+}
+
+// delivery is the Metadata of a message sent with Send.
+type delivery struct {
+	span  trace.Span
+	start time.Time
+
+	// mu is held by Send until it has annotated the span, which must not be
+	// ended before.
+	mu sync.Mutex
+}
+
+func newProducer(producer sarama.AsyncProducer, saramaConfig *sarama.Config, logger *slog.Logger) (*Producer, error) {
+	p := &Producer{
+		AsyncProducer: producer,
+		logger:        logger,
+		acked:         saramaConfig.Producer.RequiredAcks != sarama.NoResponse,
+	}
+
+	meter := otel.Meter("checkout")
+	var err error
+	p.duration, err = meter.Float64Histogram(
+		"messaging.publish.duration",
+		metric.WithDescription("Time from handing an order event to the Kafka producer to its acknowledgement or failure."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create publish duration histogram: %w", err)
+	}
+	p.failures, err = meter.Int64Counter(
+		"app.checkout.kafka.delivery_failures",
+		metric.WithDescription("Number of order events that could not be delivered to Kafka."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create delivery failure counter: %w", err)
+	}
+
+	// Successes and Errors are only closed once the producer is closed, and
+	// must be read until then.
+	p.wg.Add(2)
+	go p.readSuccesses()
+	go p.readErrors()
+	return p, nil
+}
+
+// Send hands msg to the producer without waiting for the broker, or fails
+// if ctx is done first. span is the producer span of msg: it is ended once
+// the broker acknowledges msg, with its partition and offset, or once msg
+// fails.
+func (p *Producer) Send(ctx context.Context, msg *sarama.ProducerMessage, span trace.Span) error {
+	d := &delivery{span: span, start: time.Now()}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		p.fail(msg, d, sarama.ErrShuttingDown)
+		return sarama.ErrShuttingDown
+	}
+
+	msg.Metadata = d
+	select {
+	case p.Input() <- msg:
+		span.SetAttributes(
+			attribute.Bool("messaging.kafka.producer.sent", true),
+			attribute.Int("messaging.kafka.producer.enqueue_ms", int(time.Since(d.start).Milliseconds())),
+		)
+		return nil
+	case <-ctx.Done():
+		span.SetAttributes(attribute.Bool("messaging.kafka.producer.sent", false))
+		p.fail(msg, d, ctx.Err())
+		return ctx.Err()
+	}
+}
+
+// Close flushes the messages in flight, waits for their delivery reports and
+// shuts the producer down.
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	closed := p.closed
+	p.closed = true
+	p.mu.Unlock()
+	if closed {
+		return nil
+	}
+
+	p.AsyncClose()
+	p.wg.Wait()
+	return nil
+}
+
+func (p *Producer) readSuccesses() {
+	defer p.wg.Done()
+	for msg := range p.Successes() {
+		d, ok := msg.Metadata.(*delivery)
+		if !ok {
+			continue
+		}
+		p.duration.Record(context.Background(), time.Since(d.start).Seconds(), metric.WithAttributes(publishAttributes(msg)...))
+
+		d.mu.Lock()
+		d.span.SetAttributes(semconv.MessagingKafkaDestinationPartition(int(msg.Partition)))
+		if p.acked {
+			d.span.SetAttributes(semconv.MessagingKafkaMessageOffset(int(msg.Offset)))
+		}
+		d.span.End()
+		d.mu.Unlock()
+	}
+}
+
+func (p *Producer) readErrors() {
+	defer p.wg.Done()
+	for err := range p.Errors() {
+		// We will log to STDOUT if we're not able to produce messages.
+		p.logger.Error(fmt.Sprintf("Failed to write message: %+v", err))
+		d, ok := err.Msg.Metadata.(*delivery)
+		if !ok {
+			p.fail(err.Msg, nil, err.Err)
+			continue
+		}
+		d.mu.Lock()
+		p.fail(err.Msg, d, err.Err)
+		d.mu.Unlock()
+	}
+}
+
+// fail records the failure of msg, and ends its span if it is tracked. The
+// caller holds d.mu.
+func (p *Producer) fail(msg *sarama.ProducerMessage, d *delivery, err error) {
+	attrs := metric.WithAttributes(append(publishAttributes(msg), semconv.ErrorTypeKey.String(errorType(err)))...)
+	p.failures.Add(context.Background(), 1, attrs)
+	if d == nil {
+		return
+	}
+	p.duration.Record(context.Background(), time.Since(d.start).Seconds(), attrs)
+	d.span.RecordError(err)
+	d.span.SetStatus(otelcodes.Error, err.Error())
+	d.span.End()
+}
+
+func publishAttributes(msg *sarama.ProducerMessage) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.MessagingSystemKafka,
+		semconv.MessagingOperationPublish,
+		semconv.MessagingDestinationName(msg.Topic),
+	}
+}
+
+// errorType returns the error.type of a delivery failure: the code of a
+// Kafka error, "timeout" or "canceled" for messages not handed to the
+// producer in time, and "_OTHER" for anything else.
+func errorType(err error) string {
+	var kerr sarama.KError
+	switch {
+	case errors.As(err, &kerr):
+		return fmt.Sprintf("kafka_error_%d", int16(kerr))
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	}
+	return "_OTHER"
+}
@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestProducer_Send(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	recorder := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	config := mocks.NewTestConfig()
+	config.Producer.Return.Successes = true
+	mock := mocks.NewAsyncProducer(t, config)
+	p, err := newProducer(mock, config, slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectInputAndSucceed()
+	mock.ExpectInputAndFail(sarama.ErrNotEnoughReplicas)
+	for _, name := range []string{"delivered", "failed"} {
+		_, span := tracer.Start(context.Background(), name)
+		if err := p.Send(context.Background(), &sarama.ProducerMessage{Topic: "orders"}, span); err != nil {
+			t.Fatalf("Send(): unexpected err=\"%v\"", err)
+		}
+	}
+	// An untracked message only counts when it fails.
+	mock.ExpectInputAndFail(sarama.ErrNotEnoughReplicas)
+	p.Input() <- &sarama.ProducerMessage{Topic: "orders"}
+
+	// Close waits for the delivery reports, which end the spans.
+	p.Close()
+	_, span := tracer.Start(context.Background(), "closed")
+	if err := p.Send(context.Background(), &sarama.ProducerMessage{Topic: "orders"}, span); !errors.Is(err, sarama.ErrShuttingDown) {
+		t.Errorf("Send() after Close(): err=\"%v\", want %v", err, sarama.ErrShuttingDown)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	if len(spans) != 3 {
+		t.Fatalf("ended spans = %v, want delivered, failed and closed", spans)
+	}
+	attrs := map[string]bool{}
+	for _, kv := range spans["delivered"].Attributes() {
+		attrs[string(kv.Key)] = true
+	}
+	if !attrs["messaging.kafka.destination.partition"] || !attrs["messaging.kafka.message.offset"] || !attrs["messaging.kafka.producer.sent"] {
+		t.Errorf("delivered span attributes = %v", spans["delivered"].Attributes())
+	}
+	if spans["delivered"].Status().Code == otelcodes.Error {
+		t.Errorf("delivered span status = %v", spans["delivered"].Status())
+	}
+	if spans["failed"].Status().Code != otelcodes.Error || spans["closed"].Status().Code != otelcodes.Error {
+		t.Errorf("failed span status = %v, closed span status = %v, want errors", spans["failed"].Status(), spans["closed"].Status())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	var durations, failures uint64
+	failureTypes := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				if m.Name == "messaging.publish.duration" {
+					for _, dp := range data.DataPoints {
+						durations += dp.Count
+					}
+				}
+			case metricdata.Sum[int64]:
+				if m.Name == "app.checkout.kafka.delivery_failures" {
+					for _, dp := range data.DataPoints {
+						failures += uint64(dp.Value)
+						v, _ := dp.Attributes.Value("error.type")
+						failureTypes[v.AsString()] += dp.Value
+					}
+				}
+			}
+		}
+	}
+	if durations != 3 {
+		t.Errorf("publish durations = %d, want 3", durations)
+	}
+	wantType := fmt.Sprintf("kafka_error_%d", int16(sarama.ErrNotEnoughReplicas))
+	if failures != 3 || failureTypes[wantType] != 2 {
+		t.Errorf("delivery failures = %v, want 2 %s and 1 other", failureTypes, wantType)
+	}
+}
+
+func TestErrorType(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{sarama.ErrNotLeaderForPartition, "kafka_error_6"},
+		{fmt.Errorf("send: %w", context.DeadlineExceeded), "timeout"},
+		{context.Canceled, "canceled"},
+		{sarama.ErrOutOfBrokers, "_OTHER"},
+	}
+	for _, tt := range tests {
+		if got := errorType(tt.err); got != tt.want {
+			t.Errorf("errorType(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
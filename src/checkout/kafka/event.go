@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package kafka
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Values of ProducerConfig.Encoding.
+const (
+	EncodingProtobuf = "protobuf"
+	EncodingJSON     = "json"
+)
+
+// DefaultEncoding is the encoding consumers that do not read the
+// content-type header expect.
+const DefaultEncoding = EncodingProtobuf
+
+var contentTypes = map[string]string{
+	EncodingProtobuf: "application/protobuf",
+	EncodingJSON:     "application/json",
+}
+
+// The CloudEvents attributes of order events.
+const (
+	// OrderPlacedType is the type of the events of placed orders, whose
+	// data is an oteldemo.OrderResult.
+	OrderPlacedType = "oteldemo.checkout.order.placed"
+
+	// OrderSchemaVersion is the version of the OrderResult schema. It is
+	// increased when a change is not compatible with older consumers.
+	OrderSchemaVersion = "1"
+
+	// Source is the source of the events checkout produces.
+	Source = "/checkout"
+
+	specVersion = "1.0"
+)
+
+// Event is an event in the binary mode of the CloudEvents Kafka binding: its
+// attributes are the headers of the message, and its data the value.
+type Event struct {
+	ID      string
+	Type    string
+	Source  string
+	Subject string
+	Time    time.Time
+
+	// SchemaVersion is the version of the schema of Data, in the
+	// schemaversion extension attribute.
+	SchemaVersion string
+
+	ContentType string
+	Data        []byte
+}
+
+// NewEvent returns an event of type eventType about subject, with data
+// encoded as protobuf or JSON.
+func NewEvent(eventType, schemaVersion, subject string, data proto.Message, encoding string) (Event, error) {
+	contentType, ok := contentTypes[encoding]
+	if !ok {
+		return Event{}, fmt.Errorf("unknown event encoding %q", encoding)
+	}
+	var (
+		value []byte
+		err   error
+	)
+	if encoding == EncodingJSON {
+		value, err = protojson.MarshalOptions{UseProtoNames: true}.Marshal(data)
+	} else {
+		value, err = proto.Marshal(data)
+	}
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+	return Event{
+		ID:            uuid.NewString(),
+		Type:          eventType,
+		Source:        Source,
+		Subject:       subject,
+		Time:          time.Now().UTC(),
+		SchemaVersion: schemaVersion,
+		ContentType:   contentType,
+		Data:          value,
+	}, nil
+}
+
+// Headers returns the Kafka headers of the attributes of e.
+func (e Event) Headers() map[string]string {
+	headers := map[string]string{
+		"ce_specversion":   specVersion,
+		"ce_id":            e.ID,
+		"ce_type":          e.Type,
+		"ce_source":        e.Source,
+		"ce_time":          e.Time.Format(time.RFC3339Nano),
+		"ce_schemaversion": e.SchemaVersion,
+		"content-type":     e.ContentType,
+	}
+	if e.Subject != "" {
+		headers["ce_subject"] = e.Subject
+	}
+	return headers
+}
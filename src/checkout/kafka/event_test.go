@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNewEvent(t *testing.T) {
+	data := wrapperspb.String("order-1")
+	tests := []struct {
+		encoding    string
+		contentType string
+		decode      func([]byte, proto.Message) error
+	}{
+		{EncodingProtobuf, "application/protobuf", proto.Unmarshal},
+		{EncodingJSON, "application/json", protojson.Unmarshal},
+	}
+	for _, tt := range tests {
+		e, err := NewEvent(OrderPlacedType, OrderSchemaVersion, "order-1", data, tt.encoding)
+		if err != nil {
+			t.Fatalf("NewEvent(%q): unexpected err=\"%v\"", tt.encoding, err)
+		}
+		got := &wrapperspb.StringValue{}
+		if err := tt.decode(e.Data, got); err != nil || got.Value != data.Value {
+			t.Errorf("NewEvent(%q) data = %q, decoded %v (err=%v)", tt.encoding, e.Data, got, err)
+		}
+
+		headers := e.Headers()
+		want := map[string]string{
+			"ce_specversion":   "1.0",
+			"ce_type":          OrderPlacedType,
+			"ce_source":        Source,
+			"ce_subject":       "order-1",
+			"ce_schemaversion": OrderSchemaVersion,
+			"content-type":     tt.contentType,
+		}
+		for k, v := range want {
+			if headers[k] != v {
+				t.Errorf("NewEvent(%q) header %s = %q, want %q", tt.encoding, k, headers[k], v)
+			}
+		}
+		if headers["ce_id"] == "" {
+			t.Errorf("NewEvent(%q) has no ce_id", tt.encoding)
+		}
+		if _, err := time.Parse(time.RFC3339, headers["ce_time"]); err != nil {
+			t.Errorf("NewEvent(%q) ce_time = %q: %v", tt.encoding, headers["ce_time"], err)
+		}
+	}
+
+	if _, err := NewEvent(OrderPlacedType, OrderSchemaVersion, "order-1", data, "avro"); err == nil {
+		t.Error("NewEvent() with an unknown encoding: expected an error")
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package kafka
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"github.com/IBM/sarama"
+)
+
+// Values of ProducerConfig.PartitionKey, what order events are keyed by.
+// Events with the same key go to the same partition, and are consumed in
+// order.
+const (
+	KeyOrderID         = "order_id"
+	KeyUserID          = "user_id"
+	KeyShippingCountry = "shipping_country"
+
+	// KeyNone spreads events randomly over the partitions.
+	KeyNone = "none"
+)
+
+// Values of ProducerConfig.PartitionHash, how keys are mapped to partitions.
+const (
+	// HashFNV1a is the FNV-1a hash of Sarama's default partitioner.
+	HashFNV1a = "fnv1a"
+
+	// HashMurmur2 is the hash of the Java client's default partitioner, so
+	// its producers put the same keys in the same partitions.
+	HashMurmur2 = "murmur2"
+
+	// HashCRC32 is the hash of librdkafka's consistent partitioner.
+	HashCRC32 = "crc32"
+)
+
+// Order holds the fields of an order that events can be keyed by.
+type Order struct {
+	ID              string
+	UserID          string
+	ShippingCountry string
+}
+
+// Key returns the message key of the events of o, or "" to leave them
+// unkeyed.
+func (c ProducerConfig) Key(o Order) string {
+	switch c.PartitionKey {
+	case KeyOrderID:
+		return o.ID
+	case KeyUserID:
+		return o.UserID
+	case KeyShippingCountry:
+		return o.ShippingCountry
+	}
+	return ""
+}
+
+func (c ProducerConfig) partitioner() (sarama.PartitionerConstructor, error) {
+	switch c.PartitionKey {
+	case KeyOrderID, KeyUserID, KeyShippingCountry, KeyNone:
+	default:
+		return nil, fmt.Errorf("unknown partition_key %q", c.PartitionKey)
+	}
+
+	// Unkeyed messages are partitioned randomly by all of them.
+	switch c.PartitionHash {
+	case HashFNV1a:
+		return sarama.NewHashPartitioner, nil
+	case HashMurmur2:
+		return sarama.NewCustomHashPartitioner(newMurmur2), nil
+	case HashCRC32:
+		return sarama.NewConsistentCRCHashPartitioner, nil
+	}
+	return nil, fmt.Errorf("unknown partition_hash %q", c.PartitionHash)
+}
+
+// murmur2 is the 32-bit MurmurHash2 of the Java client, made positive as the
+// client does before taking it modulo the number of partitions.
+type murmur2 struct {
+	data []byte
+}
+
+func newMurmur2() hash.Hash32 { return new(murmur2) }
+
+func (m *murmur2) Write(p []byte) (int, error) {
+	m.data = append(m.data, p...)
+	return len(p), nil
+}
+
+func (m *murmur2) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint32(b, m.Sum32())
+}
+
+func (m *murmur2) Reset()         { m.data = m.data[:0] }
+func (m *murmur2) Size() int      { return 4 }
+func (m *murmur2) BlockSize() int { return 4 }
+
+func (m *murmur2) Sum32() uint32 {
+	const (
+		seed = 0x9747b28c
+		mul  = 0x5bd1e995
+		r    = 24
+	)
+	data := m.data
+	h := uint32(seed) ^ uint32(len(data))
+	for ; len(data) >= 4; data = data[4:] {
+		k := binary.LittleEndian.Uint32(data)
+		k *= mul
+		k ^= k >> r
+		k *= mul
+		h *= mul
+		h ^= k
+	}
+	switch len(data) {
+	case 3:
+		h ^= uint32(data[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(data[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(data[0])
+		h *= mul
+	}
+	h ^= h >> 13
+	h *= mul
+	h ^= h >> 15
+	return h & 0x7fffffff
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package kafka
+
+import (
+	"testing"
+
+	"github.com/IBM/sarama"
+)
+
+func TestMurmur2(t *testing.T) {
+	// The values of the Java client's tests, made positive.
+	tests := map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	}
+	for key, want := range tests {
+		h := newMurmur2()
+		h.Write([]byte(key))
+		if got := h.Sum32(); got != uint32(want&0x7fffffff) {
+			t.Errorf("murmur2(%q) = %d, want %d", key, got, want&0x7fffffff)
+		}
+	}
+}
+
+func TestProducerConfig_Key(t *testing.T) {
+	o := Order{ID: "order-1", UserID: "user-1", ShippingCountry: "DE"}
+	tests := map[string]string{
+		KeyOrderID:         "order-1",
+		KeyUserID:          "user-1",
+		KeyShippingCountry: "DE",
+		KeyNone:            "",
+	}
+	for strategy, want := range tests {
+		if got := (ProducerConfig{PartitionKey: strategy}).Key(o); got != want {
+			t.Errorf("Key() with %s = %q, want %q", strategy, got, want)
+		}
+	}
+}
+
+func TestProducerConfig_partitioner(t *testing.T) {
+	for _, hash := range []string{HashFNV1a, HashMurmur2, HashCRC32} {
+		c := ProducerConfig{PartitionKey: KeyUserID, PartitionHash: hash}
+		newPartitioner, err := c.partitioner()
+		if err != nil {
+			t.Fatalf("partitioner() with %s: unexpected err=\"%v\"", hash, err)
+		}
+		p := newPartitioner("orders")
+
+		// The same key always goes to the same partition.
+		msg := &sarama.ProducerMessage{Key: sarama.StringEncoder("user-1")}
+		first, err := p.Partition(msg, 12)
+		if err != nil || first < 0 || first >= 12 {
+			t.Fatalf("Partition() with %s = %d, err=\"%v\"", hash, first, err)
+		}
+		for i := 0; i < 5; i++ {
+			if got, _ := p.Partition(msg, 12); got != first {
+				t.Errorf("Partition() with %s = %d, then %d", hash, first, got)
+			}
+		}
+	}
+
+	// The Java client puts "foobar" in partition 6 of 12.
+	c := ProducerConfig{PartitionKey: KeyUserID, PartitionHash: HashMurmur2}
+	newPartitioner, _ := c.partitioner()
+	if got, _ := newPartitioner("orders").Partition(&sarama.ProducerMessage{Key: sarama.StringEncoder("foobar")}, 12); got != 6 {
+		t.Errorf("Partition(foobar) with murmur2 = %d", got)
+	}
+
+	for _, c := range []ProducerConfig{
+		{PartitionKey: "session_id", PartitionHash: HashFNV1a},
+		{PartitionKey: KeyUserID, PartitionHash: "sha256"},
+	} {
+		if _, err := c.partitioner(); err == nil {
+			t.Errorf("partitioner() with %+v: expected an error", c)
+		}
+	}
+}
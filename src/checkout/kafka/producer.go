@@ -9,6 +9,7 @@ import (
 	"github.com/IBM/sarama"
 )
 
+// The topic and protocol version of producers that are not configured.
 var (
 	Topic           = "orders"
 	ProtocolVersion = sarama.V3_0_0_0
@@ -28,34 +29,56 @@ func (l *saramaLogger) Print(v ...interface{}) {
 	l.logger.Info(fmt.Sprint(v...))
 }
 
-func CreateKafkaProducer(brokers []string, logger *slog.Logger) (sarama.AsyncProducer, error) {
+// CreateKafkaProducer creates the producer of order events, with the
+// delivery settings of cfg.
+func CreateKafkaProducer(cfg ProducerConfig, logger *slog.Logger) (*Producer, error) {
 	// Set the logger for sarama to use.
 	sarama.Logger = &saramaLogger{logger: logger}
 
-	saramaConfig := sarama.NewConfig()
-	saramaConfig.Producer.Return.Successes = true
-	saramaConfig.Producer.Return.Errors = true
+	saramaConfig, err := cfg.Sarama()
+	if err != nil {
+		return nil, err
+	}
 
-	// Sarama has an issue in a single broker kafka if the kafka broker is restarted.
-	// This setting is to prevent that issue from manifesting itself, but may swallow failed messages.
-	saramaConfig.Producer.RequiredAcks = sarama.NoResponse
+	producer, err := sarama.NewAsyncProducer(cfg.Brokers, saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+	p, err := newProducer(producer, saramaConfig, logger)
+	if err != nil {
+		producer.Close()
+		return nil, err
+	}
+	return p, nil
+}
 
-	saramaConfig.Version = ProtocolVersion
+// CreateSyncProducer creates a producer for the outbox relay. Whatever the
+// profile of cfg, it waits for all in-sync replicas to acknowledge each
+// message and retries transient failures without duplicating messages, so a
+// message is only reported sent once it is stored. The compression and
+// batching of cfg are kept.
+func CreateSyncProducer(cfg ProducerConfig, logger *slog.Logger) (sarama.SyncProducer, error) {
+	sarama.Logger = &saramaLogger{logger: logger}
 
-	// So we can know the partition and offset of messages.
-	saramaConfig.Producer.Return.Successes = true
+	durable := Profiles[ProfileExactlyOnceIdempotent]
+	durable.Compression = cfg.Compression
+	durable.Linger = cfg.Linger
+	durable.BatchSize = cfg.BatchSize
+	cfg.Delivery = durable
 
-	producer, err := sarama.NewAsyncProducer(brokers, saramaConfig)
+	saramaConfig, err := cfg.Sarama()
 	if err != nil {
 		return nil, err
 	}
+	return sarama.NewSyncProducer(cfg.Brokers, saramaConfig)
+}
 
-	// We will log to STDOUT if we're not able to produce messages.
-	go func() {
-		for err := range producer.Errors() {
-			logger.Error(fmt.Sprintf("Failed to write message: %+v", err))
-
-		}
-	}()
-	return producer, nil
+// NewClient creates a client for cluster metadata requests, e.g. health
+// probes, that connects to the brokers as the producer does.
+func NewClient(cfg ProducerConfig) (sarama.Client, error) {
+	saramaConfig, err := cfg.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return sarama.NewClient(cfg.Brokers, saramaConfig)
 }
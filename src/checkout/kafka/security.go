@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/IBM/sarama"
+	"github.com/xdg-go/scram"
+)
+
+// Values of SASLConfig.Mechanism.
+const (
+	SASLPlain       = sarama.SASLTypePlaintext
+	SASLSCRAMSHA256 = sarama.SASLTypeSCRAMSHA256
+	SASLSCRAMSHA512 = sarama.SASLTypeSCRAMSHA512
+)
+
+// TLSConfig configures TLS connections to the brokers.
+type TLSConfig struct {
+	// Enabled enables TLS. It is implied by CAFile and CertFile.
+	Enabled bool `json:"enabled"`
+
+	// CAFile is a PEM file of the CAs to verify the brokers with, instead of
+	// the system's.
+	CAFile string `json:"ca_file"`
+
+	// CertFile and KeyFile are the PEM files of a client certificate, for
+	// brokers that authenticate clients with TLS.
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+
+	// ServerName is the name of the brokers' certificates, if it is not
+	// their host name.
+	ServerName string `json:"server_name"`
+
+	InsecureSkipVerify bool `json:"insecure_skip_verify"`
+}
+
+func (c TLSConfig) config() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TLS CA file: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in TLS CA file %s", c.CAFile)
+		}
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, errors.New("TLS cert_file and key_file must be set together")
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// SASLConfig configures SASL authentication to the brokers.
+type SASLConfig struct {
+	// Mechanism is "PLAIN", "SCRAM-SHA-256" or "SCRAM-SHA-512". SASL is
+	// disabled if it is empty.
+	Mechanism string `json:"mechanism"`
+
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// AllowCleartext allows PLAIN without TLS, which sends the password in
+	// cleartext, e.g. to a broker on a trusted network.
+	AllowCleartext bool `json:"allow_cleartext"`
+}
+
+// applySecurity sets the TLS and SASL settings of c in saramaConfig.
+func (c ProducerConfig) applySecurity(saramaConfig *sarama.Config) error {
+	tlsEnabled := c.TLS.Enabled || c.TLS.CAFile != "" || c.TLS.CertFile != ""
+	if c.SASL.Mechanism == SASLPlain && !tlsEnabled && !c.SASL.AllowCleartext {
+		return errors.New("SASL PLAIN without TLS sends the password in cleartext: enable TLS, or set sasl.allow_cleartext")
+	}
+	if tlsEnabled {
+		tlsConfig, err := c.TLS.config()
+		if err != nil {
+			return err
+		}
+		saramaConfig.Net.TLS.Enable = true
+		saramaConfig.Net.TLS.Config = tlsConfig
+	}
+
+	if c.SASL.Mechanism == "" {
+		return nil
+	}
+	saramaConfig.Net.SASL.Enable = true
+	saramaConfig.Net.SASL.Handshake = true
+	saramaConfig.Net.SASL.Version = sarama.SASLHandshakeV1
+	saramaConfig.Net.SASL.User = c.SASL.Username
+	saramaConfig.Net.SASL.Password = c.SASL.Password
+	switch c.SASL.Mechanism {
+	case SASLPlain:
+		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	case SASLSCRAMSHA256:
+		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+		saramaConfig.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+			return &scramClient{hash: scram.SHA256}
+		}
+	case SASLSCRAMSHA512:
+		saramaConfig.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+		saramaConfig.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+			return &scramClient{hash: scram.SHA512}
+		}
+	default:
+		return fmt.Errorf("unknown SASL mechanism %q", c.SASL.Mechanism)
+	}
+	return nil
+}
+
+// scramClient is a sarama.SCRAMClient.
+type scramClient struct {
+	hash         scram.HashGeneratorFcn
+	conversation *scram.ClientConversation
+}
+
+func (c *scramClient) Begin(username, password, authzID string) error {
+	client, err := c.hash.NewClient(username, password, authzID)
+	if err != nil {
+		return err
+	}
+	c.conversation = client.NewConversation()
+	return nil
+}
+
+func (c *scramClient) Step(challenge string) (string, error) {
+	return c.conversation.Step(challenge)
+}
+
+func (c *scramClient) Done() bool {
+	return c.conversation.Done()
+}
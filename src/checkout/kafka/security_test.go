@@ -0,0 +1,240 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package kafka
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// newMockBroker starts a broker answering metadata requests with
+// itself, and the SASL requests with handlers.
+func newMockBroker(t *testing.T, listener net.Listener, handlers map[string]sarama.MockResponse) *sarama.MockBroker {
+	t.Helper()
+	var b *sarama.MockBroker
+	if listener != nil {
+		b = sarama.NewMockBrokerListener(t, 1, listener)
+	} else {
+		b = sarama.NewMockBroker(t, 1)
+	}
+	all := map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest":    sarama.NewMockMetadataResponse(t).SetBroker(b.Addr(), b.BrokerID()),
+	}
+	for k, v := range handlers {
+		all[k] = v
+	}
+	b.SetHandlerByMap(all)
+	t.Cleanup(b.Close)
+	return b
+}
+
+// requests returns the requests of type T the broker received.
+func requests[T any](b *sarama.MockBroker) []T {
+	var found []T
+	for _, rr := range b.History() {
+		if req, ok := rr.Request.(T); ok {
+			found = append(found, req)
+		}
+	}
+	return found
+}
+
+func testConfig(brokers ...string) ProducerConfig {
+	return ProducerConfig{Brokers: brokers, Topic: Topic, Version: ProtocolVersion.String()}
+}
+
+func TestNewClient_SASLPlain(t *testing.T) {
+	b := newMockBroker(t, nil, map[string]sarama.MockResponse{
+		"SaslHandshakeRequest":    sarama.NewMockSaslHandshakeResponse(t).SetEnabledMechanisms([]string{SASLPlain}),
+		"SaslAuthenticateRequest": sarama.NewMockSaslAuthenticateResponse(t),
+	})
+
+	cfg := testConfig(b.Addr())
+	cfg.SASL = SASLConfig{Mechanism: SASLPlain, Username: "checkout", Password: "secret"}
+	if _, err := NewClient(cfg); err == nil || !strings.Contains(err.Error(), "cleartext") {
+		t.Fatalf("NewClient() with PLAIN without TLS: err=\"%v\", want a cleartext error", err)
+	}
+
+	cfg.SASL.AllowCleartext = true
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient(): unexpected err=\"%v\"", err)
+	}
+	client.Close()
+
+	handshakes := requests[*sarama.SaslHandshakeRequest](b)
+	if len(handshakes) == 0 || handshakes[0].Mechanism != SASLPlain {
+		t.Fatalf("handshakes = %+v, want %s", handshakes, SASLPlain)
+	}
+	auths := requests[*sarama.SaslAuthenticateRequest](b)
+	if len(auths) == 0 || string(auths[0].SaslAuthBytes) != "\x00checkout\x00secret" {
+		t.Errorf("authentications = %+v, want the PLAIN credentials", auths)
+	}
+}
+
+func TestNewClient_SASLSCRAM(t *testing.T) {
+	for _, mechanism := range []string{SASLSCRAMSHA256, SASLSCRAMSHA512} {
+		t.Run(mechanism, func(t *testing.T) {
+			// The broker rejects the client's first message, which is enough
+			// to see the conversation start.
+			b := newMockBroker(t, nil, map[string]sarama.MockResponse{
+				"SaslHandshakeRequest":    sarama.NewMockSaslHandshakeResponse(t).SetEnabledMechanisms([]string{mechanism}),
+				"SaslAuthenticateRequest": sarama.NewMockSaslAuthenticateResponse(t).SetError(sarama.ErrSASLAuthenticationFailed),
+			})
+
+			cfg := testConfig(b.Addr())
+			cfg.SASL = SASLConfig{Mechanism: mechanism, Username: "checkout", Password: "secret"}
+			if client, err := NewClient(cfg); err == nil {
+				client.Close()
+				t.Fatal("NewClient(): expected the authentication to fail")
+			}
+
+			handshakes := requests[*sarama.SaslHandshakeRequest](b)
+			if len(handshakes) == 0 || handshakes[0].Mechanism != mechanism {
+				t.Fatalf("handshakes = %+v, want %s", handshakes, mechanism)
+			}
+			auths := requests[*sarama.SaslAuthenticateRequest](b)
+			if len(auths) == 0 || !strings.HasPrefix(string(auths[0].SaslAuthBytes), "n,,n=checkout,r=") {
+				t.Errorf("authentications = %+v, want a SCRAM client-first message", auths)
+			}
+		})
+	}
+}
+
+func TestNewClient_TLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := newCertificate(t, nil, nil, "ca")
+	serverCert, serverKey := newCertificate(t, ca, caKey, "server")
+	clientCert, clientKey := newCertificate(t, ca, caKey, "client")
+	writePEM(t, filepath.Join(dir, "ca.pem"), ca, nil)
+	writePEM(t, filepath.Join(dir, "client.pem"), clientCert, nil)
+	writePEM(t, filepath.Join(dir, "client-key.pem"), nil, clientKey)
+
+	// The broker requires a client certificate signed by the CA.
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{serverCert.Raw}, PrivateKey: serverKey}},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := newMockBroker(t, listener, nil)
+
+	cfg := testConfig(b.Addr())
+	cfg.TLS = TLSConfig{
+		CAFile:   filepath.Join(dir, "ca.pem"),
+		CertFile: filepath.Join(dir, "client.pem"),
+		KeyFile:  filepath.Join(dir, "client-key.pem"),
+	}
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient(): unexpected err=\"%v\"", err)
+	}
+	client.Close()
+	if len(requests[*sarama.MetadataRequest](b)) == 0 {
+		t.Error("expected a metadata request over TLS")
+	}
+
+	if _, err := (ProducerConfig{TLS: TLSConfig{CertFile: cfg.TLS.CertFile}}).clientConfig(); err == nil {
+		t.Error("clientConfig() with a cert_file without key_file: expected an error")
+	}
+}
+
+func TestLoadProducerConfig_security(t *testing.T) {
+	env := map[string]string{
+		"KAFKA_ADDR":                    "kafka:9093",
+		"CHECKOUT_KAFKA_TLS_ENABLED":    "true",
+		"CHECKOUT_KAFKA_SASL_MECHANISM": SASLSCRAMSHA512,
+		"CHECKOUT_KAFKA_SASL_USERNAME":  "checkout",
+		"CHECKOUT_KAFKA_SASL_PASSWORD":  "secret",
+	}
+	c, err := LoadProducerConfig("", func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("LoadProducerConfig(): unexpected err=\"%v\"", err)
+	}
+	sc, err := c.Sarama()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sc.Net.TLS.Enable || !sc.Net.SASL.Enable || sc.Net.SASL.Mechanism != sarama.SASLTypeSCRAMSHA512 ||
+		sc.Net.SASL.User != "checkout" || sc.Net.SASL.Password != "secret" || sc.Net.SASL.SCRAMClientGeneratorFunc == nil {
+		t.Errorf("Sarama() = TLS %+v, SASL %+v", sc.Net.TLS, sc.Net.SASL)
+	}
+	if s := c.String(); strings.Contains(s, "secret") || !strings.Contains(s, "checkout") {
+		t.Errorf("String() = %s, want the password redacted", s)
+	}
+
+	env["CHECKOUT_KAFKA_SASL_MECHANISM"] = "GSSAPI"
+	if _, err := LoadProducerConfig("", func(k string) string { return env[k] }); err == nil || !strings.Contains(err.Error(), "SASL mechanism") {
+		t.Errorf("LoadProducerConfig() with GSSAPI: err=\"%v\", want an unknown mechanism", err)
+	}
+}
+
+// newCertificate returns a certificate for 127.0.0.1 signed by parent, or a
+// CA if parent is nil.
+func newCertificate(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, name string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func writePEM(t *testing.T, path string, cert *x509.Certificate, key *ecdsa.PrivateKey) {
+	t.Helper()
+	var block *pem.Block
+	if cert != nil {
+		block = &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}
+	} else {
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
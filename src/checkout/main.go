@@ -3,17 +3,15 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"database/sql"
+	"errors"
 	"fmt"
-	"io"
 	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
 	"sync"
 	"syscall"
 	"time"
@@ -31,10 +29,8 @@ import (
 
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/contrib/instrumentation/runtime"
 	"go.opentelemetry.io/otel"
-	otelcodes "go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
@@ -45,17 +41,21 @@ import (
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/proto"
 
 	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/healthcheck"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/idempotency"
 	"github.com/open-telemetry/opentelemetry-demo/src/checkout/kafka"
 	"github.com/open-telemetry/opentelemetry-demo/src/checkout/money"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/outbox"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/pricing"
 )
 
 //go:generate go install google.golang.org/protobuf/cmd/protoc-gen-go
@@ -139,18 +139,41 @@ type checkout struct {
 	shippingSvcAddr       string
 	emailSvcAddr          string
 	paymentSvcAddr        string
-	kafkaBrokerSvcAddr    string
 	pb.UnimplementedCheckoutServiceServer
-	KafkaProducerClient     sarama.AsyncProducer
+	KafkaProducerClient     *kafka.Producer
 	shippingSvcClient       pb.ShippingServiceClient
 	productCatalogSvcClient pb.ProductCatalogServiceClient
 	cartSvcClient           pb.CartServiceClient
 	currencySvcClient       pb.CurrencyServiceClient
 	emailSvcClient          pb.EmailServiceClient
 	paymentSvcClient        pb.PaymentServiceClient
+	idempotencyStore        idempotency.Store
+	db                      *sql.DB
+	outbox                  outbox.Store
+	outboxProducer          sarama.SyncProducer
+	outboxRelay             *outbox.Relay
+	pricingRules            *pricing.Rules
+	currencies              currencyCache
+
+	// kafkaConfig is a pointer so that logging the service config does not
+	// print the SASL password.
+	kafkaConfig *kafka.ProducerConfig
 }
 
+// displayLocale is the locale amounts are formatted in for customers and
+// logs.
+const displayLocale = "en-US"
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "outbox-replay" {
+		logger = slog.Default()
+		if err := runOutboxReplay(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var port string
 	mustMapEnv(&port, "CHECKOUT_PORT")
 
@@ -198,43 +221,112 @@ func main() {
 
 	svc := new(checkout)
 
+	// Dependencies probed by the health checker. Email is not critical as a
+	// failed confirmation email does not fail the order.
+	var deps []healthcheck.Dependency
+	probeClient := &http.Client{}
+
+	// Every dependency has a deadline, retry and circuit breaker policy.
+	resilienceConfig := loadResilienceConfig()
+	shippingDep := newDependency(resilienceConfig, "shipping")
+	productCatalogDep := newDependency(resilienceConfig, "product-catalog")
+	cartDep := newDependency(resilienceConfig, "cart")
+	currencyDep := newDependency(resilienceConfig, "currency")
+	emailDep := newDependency(resilienceConfig, "email")
+	paymentDep := newDependency(resilienceConfig, "payment")
+
+	// Shipping and email are called over HTTP/JSON unless their transport is
+	// set to gRPC.
 	mustMapEnv(&svc.shippingSvcAddr, "SHIPPING_ADDR")
-	c := mustCreateClient(svc.shippingSvcAddr)
-	svc.shippingSvcClient = pb.NewShippingServiceClient(c)
-	defer c.Close()
+	if transport("shipping") == transportGRPC {
+		c := mustCreateClient(svc.shippingSvcAddr, grpcOption(shippingDep))
+		svc.shippingSvcClient = pb.NewShippingServiceClient(c)
+		defer c.Close()
+		deps = append(deps, healthcheck.Dependency{Name: "shipping", Probe: healthcheck.GRPCConnProbe(c), Critical: true})
+	} else {
+		svc.shippingSvcClient = &httpShippingClient{newProtoHTTPClient(svc.shippingSvcAddr, shippingDep)}
+		deps = append(deps, healthcheck.Dependency{Name: "shipping", Probe: healthcheck.HTTPProbe(probeClient, svc.shippingSvcAddr), Critical: true})
+	}
+
+	mustMapEnv(&svc.emailSvcAddr, "EMAIL_ADDR")
+	if transport("email") == transportGRPC {
+		c := mustCreateClient(svc.emailSvcAddr, grpcOption(emailDep))
+		svc.emailSvcClient = pb.NewEmailServiceClient(c)
+		defer c.Close()
+		deps = append(deps, healthcheck.Dependency{Name: "email", Probe: healthcheck.GRPCConnProbe(c)})
+	} else {
+		svc.emailSvcClient = &httpEmailClient{newProtoHTTPClient(svc.emailSvcAddr, emailDep)}
+		deps = append(deps, healthcheck.Dependency{Name: "email", Probe: healthcheck.HTTPProbe(probeClient, svc.emailSvcAddr)})
+	}
 
 	mustMapEnv(&svc.productCatalogSvcAddr, "PRODUCT_CATALOG_ADDR")
-	c = mustCreateClient(svc.productCatalogSvcAddr)
+	c := mustCreateClient(svc.productCatalogSvcAddr, grpcOption(productCatalogDep))
 	svc.productCatalogSvcClient = pb.NewProductCatalogServiceClient(c)
 	defer c.Close()
+	deps = append(deps, healthcheck.Dependency{Name: "product-catalog", Probe: healthcheck.GRPCConnProbe(c), Critical: true})
 
 	mustMapEnv(&svc.cartSvcAddr, "CART_ADDR")
-	c = mustCreateClient(svc.cartSvcAddr)
+	c = mustCreateClient(svc.cartSvcAddr, grpcOption(cartDep))
 	svc.cartSvcClient = pb.NewCartServiceClient(c)
 	defer c.Close()
+	deps = append(deps, healthcheck.Dependency{Name: "cart", Probe: healthcheck.GRPCConnProbe(c), Critical: true})
 
 	mustMapEnv(&svc.currencySvcAddr, "CURRENCY_ADDR")
-	c = mustCreateClient(svc.currencySvcAddr)
+	c = mustCreateClient(svc.currencySvcAddr, grpcOption(currencyDep))
 	svc.currencySvcClient = pb.NewCurrencyServiceClient(c)
 	defer c.Close()
-
-	mustMapEnv(&svc.emailSvcAddr, "EMAIL_ADDR")
-	c = mustCreateClient(svc.emailSvcAddr)
-	svc.emailSvcClient = pb.NewEmailServiceClient(c)
-	defer c.Close()
+	deps = append(deps, healthcheck.Dependency{Name: "currency", Probe: healthcheck.GRPCConnProbe(c), Critical: true})
 
 	mustMapEnv(&svc.paymentSvcAddr, "PAYMENT_ADDR")
-	c = mustCreateClient(svc.paymentSvcAddr)
+	c = mustCreateClient(svc.paymentSvcAddr, grpcOption(paymentDep))
 	svc.paymentSvcClient = pb.NewPaymentServiceClient(c)
 	defer c.Close()
+	deps = append(deps, healthcheck.Dependency{Name: "payment", Probe: healthcheck.GRPCConnProbe(c), Critical: true})
 
-	svc.kafkaBrokerSvcAddr = os.Getenv("KAFKA_ADDR")
+	svc.kafkaConfig, err = loadKafkaConfig()
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
 
-	if svc.kafkaBrokerSvcAddr != "" {
-		svc.KafkaProducerClient, err = kafka.CreateKafkaProducer([]string{svc.kafkaBrokerSvcAddr}, logger)
+	if len(svc.kafkaConfig.Brokers) > 0 {
+		logger.Info(fmt.Sprintf("kafka producer config: %v", svc.kafkaConfig))
+		svc.KafkaProducerClient, err = kafka.CreateKafkaProducer(*svc.kafkaConfig, logger)
 		if err != nil {
 			logger.Error(err.Error())
+		} else {
+			// Ends the producer spans of the events still in flight.
+			defer svc.KafkaProducerClient.Close()
 		}
+
+		switch mode := os.Getenv("CHECKOUT_ORDER_EVENTS_MODE"); mode {
+		case "", orderEventsDirect:
+		case orderEventsOutbox:
+			// Sending directly instead would silently give up the delivery
+			// guarantees the outbox was chosen for.
+			if err := svc.setupOutbox(); err != nil {
+				logger.Error(err.Error())
+				os.Exit(1)
+			}
+			defer svc.closeOutbox()
+		default:
+			logger.Warn(fmt.Sprintf("unknown CHECKOUT_ORDER_EVENTS_MODE %q, sending order events directly", mode))
+		}
+
+		kafkaProbe := healthcheck.NewKafkaProbe(svc.kafkaConfig.Topic, func() (sarama.Client, error) {
+			return kafka.NewClient(*svc.kafkaConfig)
+		})
+		defer kafkaProbe.Close()
+		// Order events are sent asynchronously or buffered in the outbox, so
+		// orders can still be placed while Kafka is unreachable.
+		deps = append(deps, healthcheck.Dependency{Name: "kafka", Probe: kafkaProbe})
+	}
+
+	svc.pricingRules = loadPricingRules()
+
+	svc.idempotencyStore = svc.createIdempotencyStore()
+	if svc.db != nil {
+		defer svc.db.Close()
 	}
 
 	logger.Info(fmt.Sprintf("service config: %+v", svc))
@@ -249,15 +341,45 @@ func main() {
 	)
 	pb.RegisterCheckoutServiceServer(srv, svc)
 
-	healthcheck := health.NewServer()
-	healthpb.RegisterHealthServer(srv, healthcheck)
-	logger.Info(fmt.Sprintf("starting to listen on tcp: %q", lis.Addr().String()))
-	err = srv.Serve(lis)
-	logger.Error(err.Error())
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthServer)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGKILL)
 	defer cancel()
 
+	healthInterval := 10 * time.Second
+	if v := os.Getenv("CHECKOUT_HEALTH_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			logger.Warn(fmt.Sprintf("invalid CHECKOUT_HEALTH_INTERVAL %q, using %s", v, healthInterval))
+		} else {
+			healthInterval = d
+		}
+	}
+	checker, err := healthcheck.NewChecker(
+		healthServer,
+		[]string{"", pb.CheckoutService_ServiceDesc.ServiceName},
+		healthInterval, healthInterval/2,
+		logger,
+		deps...,
+	)
+	if err != nil {
+		logger.Error(err.Error())
+	} else {
+		go checker.Run(ctx)
+	}
+
+	relayDone := make(chan struct{})
+	if svc.outboxRelay != nil {
+		go func() {
+			defer close(relayDone)
+			svc.outboxRelay.Run(ctx)
+		}()
+	} else {
+		close(relayDone)
+	}
+
+	logger.Info(fmt.Sprintf("starting to listen on tcp: %q", lis.Addr().String()))
 	go func() {
 		if err := srv.Serve(lis); err != nil {
 			logger.Error(err.Error())
@@ -267,6 +389,7 @@ func main() {
 	<-ctx.Done()
 
 	srv.GracefulStop()
+	<-relayDone
 	logger.Info("Checkout gRPC server stopped")
 }
 
@@ -278,15 +401,68 @@ func mustMapEnv(target *string, envKey string) {
 	*target = v
 }
 
-func (cs *checkout) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
-	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
-}
+func (cs *checkout) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (*pb.PlaceOrderResponse, error) {
+	// Invalid requests are rejected before an idempotency key is taken, so
+	// they can be corrected and retried with the same key.
+	if err := cs.validatePlaceOrder(ctx, req); err != nil {
+		return nil, err
+	}
+
+	key := idempotency.KeyFromRequest(ctx, req)
+	if key == "" {
+		return cs.placeOrder(ctx, req)
+	}
 
-func (cs *checkout) Watch(req *healthpb.HealthCheckRequest, ws healthpb.Health_WatchServer) error {
-	return status.Errorf(codes.Unimplemented, "health check via Watch not implemented")
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Bool("app.order.idempotency_key.present", true))
+
+	prior, err := cs.idempotencyStore.Begin(ctx, key)
+	if errors.Is(err, idempotency.ErrInFlight) {
+		return nil, status.Error(codes.Aborted, "an order with the same idempotency key is already in progress")
+	}
+	if errors.Is(err, idempotency.ErrFailed) {
+		return nil, status.Error(codes.FailedPrecondition, "an order with the same idempotency key failed and its payment could not be refunded")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to check idempotency key: %v", err)
+	}
+	if prior != nil {
+		span.SetAttributes(
+			attribute.Bool("app.order.replayed", true),
+			attribute.String("app.order.id", prior.GetOrderId()),
+		)
+		logger.LogAttrs(
+			ctx,
+			slog.LevelInfo, "replaying order for idempotency key",
+			slog.String("app.order.id", prior.GetOrderId()),
+		)
+		return &pb.PlaceOrderResponse{Order: prior}, nil
+	}
+
+	resp, err := cs.placeOrder(ctx, req)
+
+	// The outcome is recorded even if the client has already given up.
+	storeCtx := context.WithoutCancel(ctx)
+	if err != nil {
+		// A retry of an order that could not be rolled back would be
+		// charged again, so its key is not released.
+		var uncompensated *uncompensatedError
+		if errors.As(err, &uncompensated) {
+			if failErr := cs.idempotencyStore.Fail(storeCtx, key); failErr != nil {
+				logger.Warn(fmt.Sprintf("failed to record failed order for idempotency key: %v", failErr))
+			}
+		} else if abandonErr := cs.idempotencyStore.Abandon(storeCtx, key); abandonErr != nil {
+			logger.Warn(fmt.Sprintf("failed to release idempotency key: %v", abandonErr))
+		}
+		return nil, err
+	}
+	if completeErr := cs.idempotencyStore.Complete(storeCtx, key, resp.GetOrder()); completeErr != nil {
+		logger.Warn(fmt.Sprintf("failed to store order result for idempotency key: %v", completeErr))
+	}
+	return resp, nil
 }
 
-func (cs *checkout) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (*pb.PlaceOrderResponse, error) {
+func (cs *checkout) placeOrder(ctx context.Context, req *pb.PlaceOrderRequest) (*pb.PlaceOrderResponse, error) {
 	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(
 		attribute.String("app.user.id", req.UserId),
@@ -303,6 +479,9 @@ func (cs *checkout) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (
 	defer func() {
 		if err != nil {
 			span.AddEvent("error", trace.WithAttributes(semconv.ExceptionMessageKey.String(err.Error())))
+			if stage, ok := failedStage(err); ok {
+				span.SetAttributes(attribute.String("app.order.failed_stage", stage))
+			}
 		}
 	}()
 
@@ -311,25 +490,23 @@ func (cs *checkout) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (
 		return nil, status.Errorf(codes.Internal, "failed to generate order uuid")
 	}
 
-	prep, err := cs.prepareOrderItemsAndShippingQuoteFromCart(ctx, req.UserId, req.UserCurrency, req.Address)
+	prep, err := cs.prepareOrderItemsAndShippingQuoteFromCart(ctx, req.UserId, req.UserCurrency, req.Address, req.CouponCodes)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, err.Error())
+		return nil, orderStatus(err)
 	}
 	span.AddEvent("prepared")
 
-	total := &pb.Money{CurrencyCode: req.UserCurrency,
-		Units: 0,
-		Nanos: 0}
-	total = money.Must(money.Sum(total, prep.shippingCostLocalized))
-	for _, it := range prep.orderItems {
-		multPrice := money.MultiplySlow(it.Cost, uint32(it.GetItem().GetQuantity()))
-		total = money.Must(money.Sum(total, multPrice))
-	}
+	total := prep.pricing.GetTotal()
+
+	saga := newOrderSaga(orderID.String())
 
 	txID, err := cs.chargeCard(ctx, total, req.CreditCard)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to charge card: %+v", err)
+		return nil, orderStatus(err)
 	}
+	saga.completed(stagePayment, func(ctx context.Context) error {
+		return cs.refundCharge(ctx, txID, total, "order failed")
+	})
 
 	span.AddEvent("charged",
 		trace.WithAttributes(attribute.String("app.payment.transaction.id", txID)))
@@ -337,27 +514,48 @@ func (cs *checkout) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (
 		ctx,
 		slog.LevelInfo, "payment went through",
 		slog.String("transaction_id", txID),
+		slog.String("app.order.total", formatAmount(total)),
 	)
 
 	shippingTrackingID, err := cs.shipOrder(ctx, req.Address, prep.cartItems)
 	if err != nil {
-		return nil, status.Errorf(codes.Unavailable, "shipping error: %+v", err)
+		return nil, abortedOrderStatus(ctx, saga, stageShipping, err)
 	}
 	shippingTrackingAttribute := attribute.String("app.shipping.tracking.id", shippingTrackingID)
 	span.AddEvent("shipped", trace.WithAttributes(shippingTrackingAttribute))
 
-	_ = cs.emptyUserCart(ctx, req.UserId)
-
 	orderResult := &pb.OrderResult{
 		OrderId:            orderID.String(),
 		ShippingTrackingId: shippingTrackingID,
-		ShippingCost:       prep.shippingCostLocalized,
+		ShippingCost:       prep.pricing.GetShipping(),
 		ShippingAddress:    req.Address,
 		Items:              prep.orderItems,
+		Pricing:            prep.pricing,
 	}
 
-	shippingCostFloat, _ := strconv.ParseFloat(fmt.Sprintf("%d.%02d", prep.shippingCostLocalized.GetUnits(), prep.shippingCostLocalized.GetNanos()/1000000000), 64)
-	totalPriceFloat, _ := strconv.ParseFloat(fmt.Sprintf("%d.%02d", total.GetUnits(), total.GetNanos()/1000000000), 64)
+	// Events are keyed so that e.g. the orders of a user stay in order.
+	eventKey := cs.kafkaConfig.Key(kafka.Order{
+		ID:              orderResult.OrderId,
+		UserID:          req.UserId,
+		ShippingCountry: req.GetAddress().GetCountry(),
+	})
+
+	// An order whose event cannot be recorded is rolled back before the
+	// cart is emptied, so it can be placed again. The shipping service has
+	// no cancellation, so only the charge is refunded.
+	if cs.outbox != nil {
+		logger.Info("appending to outbox")
+		if err = cs.appendToOutbox(ctx, eventKey, orderResult); err != nil {
+			err = newStageError(stageEvents, err)
+			return nil, abortedOrderStatus(ctx, saga, stageEvents, err)
+		}
+	}
+
+	_ = cs.emptyUserCart(ctx, req.UserId)
+
+	shippingCostFloat := money.ToFloat64(prep.pricing.GetShipping())
+	totalPriceFloat := money.ToFloat64(total)
+	totalFormatted := formatAmount(total)
 
 	span.SetAttributes(
 		attribute.String("app.order.id", orderID.String()),
@@ -366,26 +564,28 @@ func (cs *checkout) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (
 		attribute.Int("app.order.items.count", len(prep.orderItems)),
 		shippingTrackingAttribute,
 	)
+	span.SetAttributes(pricingAttributes(prep.pricing)...)
 	logger.LogAttrs(
 		ctx,
 		slog.LevelInfo, "order placed",
 		slog.String("app.order.id", orderID.String()),
 		slog.Float64("app.shipping.amount", shippingCostFloat),
 		slog.Float64("app.order.amount", totalPriceFloat),
+		slog.String("app.order.total", totalFormatted),
 		slog.Int("app.order.items.count", len(prep.orderItems)),
 		slog.String("app.shipping.tracking.id", shippingTrackingID),
 	)
 
-	if err := cs.sendOrderConfirmation(ctx, req.Email, orderResult); err != nil {
-		logger.Warn(fmt.Sprintf("failed to send order confirmation to %q: %+v", req.Email, err))
+	if err := cs.sendOrderConfirmation(ctx, req.Email, orderResult, totalFormatted); err != nil {
+		logger.Warn(fmt.Sprintf("failed to send order confirmation to %q: %v", req.Email, err))
 	} else {
 		logger.Info(fmt.Sprintf("order confirmation email sent to %q", req.Email))
 	}
 
 	// send to kafka only if kafka broker address is set
-	if cs.kafkaBrokerSvcAddr != "" {
+	if cs.outbox == nil && cs.KafkaProducerClient != nil {
 		logger.Info("sending to postProcessor")
-		cs.sendToPostProcessor(ctx, orderResult)
+		cs.sendToPostProcessor(ctx, eventKey, orderResult)
 	}
 
 	resp := &pb.PlaceOrderResponse{Order: orderResult}
@@ -393,12 +593,12 @@ func (cs *checkout) PlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) (
 }
 
 type orderPrep struct {
-	orderItems            []*pb.OrderItem
-	cartItems             []*pb.CartItem
-	shippingCostLocalized *pb.Money
+	orderItems []*pb.OrderItem
+	cartItems  []*pb.CartItem
+	pricing    *pb.OrderPricing
 }
 
-func (cs *checkout) prepareOrderItemsAndShippingQuoteFromCart(ctx context.Context, userID, userCurrency string, address *pb.Address) (orderPrep, error) {
+func (cs *checkout) prepareOrderItemsAndShippingQuoteFromCart(ctx context.Context, userID, userCurrency string, address *pb.Address, couponCodes []string) (orderPrep, error) {
 
 	ctx, span := tracer.Start(ctx, "prepareOrderItemsAndShippingQuoteFromCart")
 	defer span.End()
@@ -406,30 +606,66 @@ func (cs *checkout) prepareOrderItemsAndShippingQuoteFromCart(ctx context.Contex
 	var out orderPrep
 	cartItems, err := cs.getUserCart(ctx, userID)
 	if err != nil {
-		return out, fmt.Errorf("cart failure: %+v", err)
+		return out, fmt.Errorf("cart failure: %w", err)
 	}
-	orderItems, err := cs.prepOrderItems(ctx, cartItems, userCurrency)
-	if err != nil {
-		return out, fmt.Errorf("failed to prepare order: %+v", err)
+
+	// The order is priced in USD, the currency of the product prices and the
+	// shipping quote, and the amounts are converted to the user currency at
+	// the end. The currency service is asked for each rate once.
+	rates := newCurrencyRates(cs.currencySvcClient)
+
+	// Items are priced while the shipping quote is fetched.
+	var (
+		orderItems  []*pb.OrderItem
+		pricedItems []pricing.Item
+		shippingUSD *pb.Money
+	)
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		orderItems, pricedItems, err = cs.prepOrderItems(gctx, cartItems, userCurrency, rates)
+		if err != nil {
+			return fmt.Errorf("failed to prepare order: %w", err)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var err error
+		shippingUSD, err = cs.quoteShipping(gctx, address, cartItems)
+		if err != nil {
+			return fmt.Errorf("shipping quote failure: %w", err)
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return out, err
 	}
-	shippingUSD, err := cs.quoteShipping(ctx, address, cartItems)
+
+	breakdown, err := cs.currentPricingRules(ctx).Engine().Price(&pricing.Order{
+		Items:       pricedItems,
+		Shipping:    shippingUSD,
+		Address:     address,
+		CouponCodes: couponCodes,
+	})
 	if err != nil {
-		return out, fmt.Errorf("shipping quote failure: %+v", err)
+		return out, newStageError(stagePricing, fmt.Errorf("failed to price order: %w", err))
 	}
-	shippingPrice, err := cs.convertCurrency(ctx, shippingUSD, userCurrency)
+	orderPricing, err := breakdown.Proto(func(m *pb.Money) (*pb.Money, error) {
+		return convertMoney(ctx, m, userCurrency, rates)
+	})
 	if err != nil {
-		return out, fmt.Errorf("failed to convert shipping cost to currency: %+v", err)
+		return out, fmt.Errorf("failed to convert order pricing to currency: %w", err)
 	}
 
-	out.shippingCostLocalized = shippingPrice
 	out.cartItems = cartItems
 	out.orderItems = orderItems
+	out.pricing = orderPricing
 
 	var totalCart int32
 	for _, ci := range cartItems {
 		totalCart += ci.Quantity
 	}
-	shippingCostFloat, _ := strconv.ParseFloat(fmt.Sprintf("%d.%02d", shippingPrice.GetUnits(), shippingPrice.GetNanos()/1000000000), 64)
+	shippingCostFloat := money.ToFloat64(orderPricing.GetShipping())
 
 	span.SetAttributes(
 		attribute.Float64("app.shipping.amount", shippingCostFloat),
@@ -439,11 +675,11 @@ func (cs *checkout) prepareOrderItemsAndShippingQuoteFromCart(ctx context.Contex
 	return out, nil
 }
 
-func mustCreateClient(svcAddr string) *grpc.ClientConn {
-	c, err := grpc.NewClient(svcAddr,
+func mustCreateClient(svcAddr string, opts ...grpc.DialOption) *grpc.ClientConn {
+	c, err := grpc.NewClient(svcAddr, append([]grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
-	)
+	}, opts...)...)
 	if err != nil {
 		logger.Error(fmt.Sprintf("could not connect to %s service, err: %+v", svcAddr, err))
 	}
@@ -452,84 +688,103 @@ func mustCreateClient(svcAddr string) *grpc.ClientConn {
 }
 
 func (cs *checkout) quoteShipping(ctx context.Context, address *pb.Address, items []*pb.CartItem) (*pb.Money, error) {
-	quotePayload, err := json.Marshal(map[string]interface{}{
-		"address": address,
-		"items":   items,
+	resp, err := cs.shippingSvcClient.GetQuote(ctx, &pb.GetQuoteRequest{
+		Address: address,
+		Items:   items,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal ship order request: %+v", err)
+		return nil, newStageError(stageQuote, fmt.Errorf("failed to get shipping quote: %w", err))
 	}
-
-	resp, err := otelhttp.Post(ctx, cs.shippingSvcAddr+"/get-quote", "application/json", bytes.NewBuffer(quotePayload))
-	if err != nil {
-		return nil, fmt.Errorf("failed POST to shipping service: %+v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed POST to email service: expected 200, got %d", resp.StatusCode)
+	if resp.GetCostUsd() == nil {
+		return nil, newStageError(stageQuote, errors.New("shipping quote missing cost_usd field"))
 	}
-
-	shippingQuoteBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read shipping quote response: %+v", err)
-	}
-
-	var quoteResp struct {
-		CostUsd *pb.Money `json:"cost_usd"`
-	}
-	if err := json.Unmarshal(shippingQuoteBytes, &quoteResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal shipping quote: %+v", err)
-	}
-	if quoteResp.CostUsd == nil {
-		return nil, fmt.Errorf("shipping quote missing cost_usd field")
-	}
-
-	return quoteResp.CostUsd, nil
+	return resp.GetCostUsd(), nil
 }
 
 func (cs *checkout) getUserCart(ctx context.Context, userID string) ([]*pb.CartItem, error) {
 	cart, err := cs.cartSvcClient.GetCart(ctx, &pb.GetCartRequest{UserId: userID})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user cart during checkout: %+v", err)
+		return nil, newStageError(stageCart, fmt.Errorf("failed to get user cart during checkout: %w", err))
 	}
 	return cart.GetItems(), nil
 }
 
 func (cs *checkout) emptyUserCart(ctx context.Context, userID string) error {
 	if _, err := cs.cartSvcClient.EmptyCart(ctx, &pb.EmptyCartRequest{UserId: userID}); err != nil {
-		return fmt.Errorf("failed to empty user cart during checkout: %+v", err)
+		return newStageError(stageCart, fmt.Errorf("failed to empty user cart during checkout: %w", err))
 	}
 	return nil
 }
 
-func (cs *checkout) prepOrderItems(ctx context.Context, items []*pb.CartItem, userCurrency string) ([]*pb.OrderItem, error) {
+// prepOrderItems looks up all cart items with a single GetProducts call and
+// converts their prices to userCurrency. The conversions are done in turn:
+// rates asks the currency service for each rate once, so after the first
+// item they are arithmetic only. It also returns the items with their prices
+// in USD for pricing. The results keep the cart order.
+func (cs *checkout) prepOrderItems(ctx context.Context, items []*pb.CartItem, userCurrency string, rates money.RateProvider) ([]*pb.OrderItem, []pricing.Item, error) {
 	out := make([]*pb.OrderItem, len(items))
+	priced := make([]pricing.Item, len(items))
+
+	products, err := cs.getProducts(ctx, items)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	for i, item := range items {
-		product, err := cs.productCatalogSvcClient.GetProduct(ctx, &pb.GetProductRequest{Id: item.GetProductId()})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get product #%q", item.GetProductId())
+		product, ok := products[item.GetProductId()]
+		if !ok {
+			return nil, nil, newStageError(stageCatalog, status.Errorf(codes.NotFound, "product #%q not found", item.GetProductId()))
 		}
-		price, err := cs.convertCurrency(ctx, product.GetPriceUsd(), userCurrency)
+		price, err := convertMoney(ctx, product.GetPriceUsd(), userCurrency, rates)
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert price of %q to %s", item.GetProductId(), userCurrency)
+			return nil, nil, fmt.Errorf("failed to convert price of %q to %s: %w", item.GetProductId(), userCurrency, err)
 		}
 		out[i] = &pb.OrderItem{
 			Item: item,
 			Cost: price}
+		priced[i] = pricing.Item{
+			ProductID:  item.GetProductId(),
+			Categories: product.GetCategories(),
+			UnitPrice:  product.GetPriceUsd(),
+			Quantity:   item.GetQuantity(),
+		}
 	}
-	return out, nil
+	return out, priced, nil
 }
 
-func (cs *checkout) convertCurrency(ctx context.Context, from *pb.Money, toCurrency string) (*pb.Money, error) {
-	result, err := cs.currencySvcClient.Convert(ctx, &pb.CurrencyConversionRequest{
-		From:   from,
-		ToCode: toCurrency})
+// getProducts fetches the products of the given cart items, keyed by id.
+// Products that do not exist are absent from the result.
+func (cs *checkout) getProducts(ctx context.Context, items []*pb.CartItem) (map[string]*pb.Product, error) {
+	ids := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if !seen[item.GetProductId()] {
+			seen[item.GetProductId()] = true
+			ids = append(ids, item.GetProductId())
+		}
+	}
+
+	resp, err := cs.productCatalogSvcClient.GetProducts(ctx, &pb.GetProductsRequest{Ids: ids})
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert currency: %+v", err)
+		return nil, newStageError(stageCatalog, fmt.Errorf("failed to get products: %w", err))
 	}
-	return result, err
+
+	products := make(map[string]*pb.Product, len(ids))
+	for _, r := range resp.GetResults() {
+		if r.GetFound() {
+			products[r.GetId()] = r.GetProduct()
+		}
+	}
+	return products, nil
+}
+
+// convertMoney converts from to toCurrency at the rate from rates.
+func convertMoney(ctx context.Context, from *pb.Money, toCurrency string, rates money.RateProvider) (*pb.Money, error) {
+	rate, err := rates.Rate(ctx, from.GetCurrencyCode(), toCurrency)
+	if err != nil {
+		return nil, newStageError(stageCurrency, err)
+	}
+	return money.Convert(from, toCurrency, rate, money.RoundHalfEven)
 }
 
 func (cs *checkout) chargeCard(ctx context.Context, amount *pb.Money, paymentInfo *pb.CreditCardInfo) (string, error) {
@@ -544,137 +799,137 @@ func (cs *checkout) chargeCard(ctx context.Context, amount *pb.Money, paymentInf
 		Amount:     amount,
 		CreditCard: paymentInfo})
 	if err != nil {
-		return "", fmt.Errorf("could not charge the card: %+v", err)
+		return "", newStageError(stagePayment, fmt.Errorf("could not charge the card: %w", err))
 	}
 	return paymentResp.GetTransactionId(), nil
 }
 
-func (cs *checkout) sendOrderConfirmation(ctx context.Context, email string, order *pb.OrderResult) error {
-	emailPayload, err := json.Marshal(map[string]interface{}{
-		"email": email,
-		"order": order,
-	})
+func (cs *checkout) refundCharge(ctx context.Context, transactionID string, amount *pb.Money, reason string) error {
+	resp, err := cs.paymentSvcClient.Refund(ctx, &pb.RefundRequest{
+		TransactionId: transactionID,
+		Amount:        amount,
+		Reason:        reason})
 	if err != nil {
-		return fmt.Errorf("failed to marshal order to JSON: %+v", err)
+		return fmt.Errorf("could not refund transaction %q: %w", transactionID, err)
 	}
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("app.payment.transaction.id", transactionID),
+		attribute.String("app.payment.refund.id", resp.GetRefundId()),
+	)
+	return nil
+}
 
-	resp, err := otelhttp.Post(ctx, cs.emailSvcAddr+"/send_order_confirmation", "application/json", bytes.NewBuffer(emailPayload))
+// formatAmount formats m in displayLocale, e.g. for logs and emails.
+func formatAmount(m *pb.Money) string {
+	s, err := money.Format(m, displayLocale)
 	if err != nil {
-		return fmt.Errorf("failed POST to email service: %+v", err)
+		return money.ToDecimalString(m) + " " + m.GetCurrencyCode()
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed POST to email service: expected 200, got %d", resp.StatusCode)
-	}
-
-	return err
+	return s
 }
 
-func (cs *checkout) shipOrder(ctx context.Context, address *pb.Address, items []*pb.CartItem) (string, error) {
-	shipPayload, err := json.Marshal(map[string]interface{}{
-		"address": address,
-		"items":   items,
+func (cs *checkout) sendOrderConfirmation(ctx context.Context, email string, order *pb.OrderResult, total string) error {
+	_, err := cs.emailSvcClient.SendOrderConfirmation(ctx, &pb.SendOrderConfirmationRequest{
+		Email: email,
+		Order: order,
+		Total: total,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal ship order request: %+v", err)
-	}
-
-	resp, err := otelhttp.Post(ctx, cs.shippingSvcAddr+"/ship-order", "application/json", bytes.NewBuffer(shipPayload))
-	if err != nil {
-		return "", fmt.Errorf("failed POST to shipping service: %+v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("failed POST to email service: expected 200, got %d", resp.StatusCode)
+		return fmt.Errorf("failed to send order confirmation: %w", err)
 	}
+	return nil
+}
 
-	trackingRespBytes, err := io.ReadAll(resp.Body)
+func (cs *checkout) shipOrder(ctx context.Context, address *pb.Address, items []*pb.CartItem) (string, error) {
+	resp, err := cs.shippingSvcClient.ShipOrder(ctx, &pb.ShipOrderRequest{
+		Address: address,
+		Items:   items,
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to read ship order response: %+v", err)
+		return "", newStageError(stageShipping, fmt.Errorf("failed to ship order: %w", err))
 	}
-
-	var shipResp struct {
-		TrackingID string `json:"tracking_id"`
+	if resp.GetTrackingId() == "" {
+		return "", newStageError(stageShipping, errors.New("ship order response missing tracking_id field"))
 	}
-	if err := json.Unmarshal(trackingRespBytes, &shipResp); err != nil {
-		return "", fmt.Errorf("failed to unmarshal ship order response: %+v", err)
-	}
-	if shipResp.TrackingID == "" {
-		return "", fmt.Errorf("ship order response missing tracking_id field")
-	}
-
-	return shipResp.TrackingID, nil
+	return resp.GetTrackingId(), nil
 }
 
-func (cs *checkout) sendToPostProcessor(ctx context.Context, result *pb.OrderResult) {
-	message, err := proto.Marshal(result)
+// sendToPostProcessor hands the order event to the Kafka producer, keyed
+// with key unless it is empty. The producer span ends once the broker
+// acknowledges the event.
+func (cs *checkout) sendToPostProcessor(ctx context.Context, key string, result *pb.OrderResult) {
+	event, err := cs.newOrderEvent(result)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to marshal message to protobuf: %+v", err))
+		logger.Error(fmt.Sprintf("Failed to encode order event: %+v", err))
 		return
 	}
 
-	msg := sarama.ProducerMessage{
-		Topic: kafka.Topic,
-		Value: sarama.ByteEncoder(message),
+	newMessage := func() *sarama.ProducerMessage {
+		msg := &sarama.ProducerMessage{
+			Topic: cs.kafkaConfig.Topic,
+			Value: sarama.ByteEncoder(event.Data),
+		}
+		if key != "" {
+			msg.Key = sarama.StringEncoder(key)
+		}
+		for name, value := range event.Headers() {
+			msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(name), Value: []byte(value)})
+		}
+		return msg
 	}
+	msg := newMessage()
+	messageID := trace.WithAttributes(semconv.MessagingMessageID(event.ID))
 
 	// Inject tracing info into message
-	span := createProducerSpan(ctx, &msg)
-	defer span.End()
+	span := createProducerSpan(ctx, msg, key, messageID)
 
-	// Send message asynchronously - don't wait for confirmation
-	// Since RequiredAcks = NoResponse, we can fire-and-forget
+	// Send message asynchronously - don't wait for confirmation,
+	// whatever the acks of the producer profile
 	// This prevents Kafka backpressure from blocking checkout
 	startTime := time.Now()
-	select {
-	case cs.KafkaProducerClient.Input() <- &msg:
-		// Message sent to buffer - don't wait for Successes()
-		// This prevents Kafka backpressure from blocking checkout
-		span.SetAttributes(
-			attribute.Bool("messaging.kafka.producer.sent", true),
-			attribute.Int("messaging.kafka.producer.enqueue_ms", int(time.Since(startTime).Milliseconds())),
-		)
-		logger.Info(fmt.Sprintf("Message sent to Kafka buffer. Enqueue time: %v", time.Since(startTime)))
-	case <-ctx.Done():
-		span.SetAttributes(
-			attribute.Bool("messaging.kafka.producer.sent", false),
-		)
-		span.SetStatus(otelcodes.Error, "Failed to send: "+ctx.Err().Error())
-		logger.Error(fmt.Sprintf("Failed to send message to Kafka within context deadline: %v", ctx.Err()))
+	if err := cs.KafkaProducerClient.Send(ctx, msg, span); err != nil {
+		logger.Error(fmt.Sprintf("Failed to send message to Kafka within context deadline: %v", err))
 		return
 	}
+	logger.Info(fmt.Sprintf("Message sent to Kafka buffer. Enqueue time: %v", time.Since(startTime)))
 
-	// Errors will be logged by the background goroutine in producer.go
-	// Don't wait for Successes() to avoid blocking on Kafka backpressure
+	// The span is ended and errors are logged by the producer once the
+	// broker acknowledges the message or it fails
 
 	ffValue := cs.getIntFeatureFlag(ctx, "kafkaQueueProblems")
 	if ffValue > 0 {
 		logger.Info("Warning: FeatureFlag 'kafkaQueueProblems' is activated, overloading queue now.")
+		// The copies outlive the order, so their spans are not part of its
+		// trace but linked to the span of the original message.
+		link := trace.Link{SpanContext: span.SpanContext()}
 		for i := 0; i < ffValue; i++ {
-			go func(i int) {
-				cs.KafkaProducerClient.Input() <- &msg
-				_ = <-cs.KafkaProducerClient.Successes()
-			}(i)
+			overload := newMessage()
+			overloadSpan := createProducerSpan(context.Background(), overload, key, messageID, trace.WithLinks(link))
+			go func() {
+				_ = cs.KafkaProducerClient.Send(context.Background(), overload, overloadSpan)
+			}()
 		}
 		logger.Info(fmt.Sprintf("Done with #%d messages for overload simulation.", ffValue))
 	}
 }
 
-func createProducerSpan(ctx context.Context, msg *sarama.ProducerMessage) trace.Span {
+// createProducerSpan starts the span of publishing msg. The partition is
+// only known once the broker acknowledges it.
+func createProducerSpan(ctx context.Context, msg *sarama.ProducerMessage, key string, opts ...trace.SpanStartOption) trace.Span {
+	attrs := []attribute.KeyValue{
+		semconv.PeerService("kafka"),
+		semconv.NetworkTransportTCP,
+		semconv.MessagingSystemKafka,
+		semconv.MessagingDestinationName(msg.Topic),
+		semconv.MessagingOperationPublish,
+	}
+	if key != "" {
+		attrs = append(attrs, semconv.MessagingKafkaMessageKey(key))
+	}
 	spanContext, span := tracer.Start(
 		ctx,
 		fmt.Sprintf("%s publish", msg.Topic),
-		trace.WithSpanKind(trace.SpanKindProducer),
-		trace.WithAttributes(
-			semconv.PeerService("kafka"),
-			semconv.NetworkTransportTCP,
-			semconv.MessagingSystemKafka,
-			semconv.MessagingDestinationName(msg.Topic),
-			semconv.MessagingOperationPublish,
-			semconv.MessagingKafkaDestinationPartition(int(msg.Partition)),
-		),
+		append(opts, trace.WithSpanKind(trace.SpanKindProducer), trace.WithAttributes(attrs...))...,
 	)
 
 	carrier := propagation.MapCarrier{}
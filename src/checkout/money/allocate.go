@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package money
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+var ErrInvalidWeights = errors.New("allocation weights must be non-negative and not all zero")
+
+// Allocate splits m into n parts that differ by at most one minor unit of
+// its currency, and sum up to m exactly. The larger parts come first.
+func Allocate(m *pb.Money, n int) ([]*pb.Money, error) {
+	if n <= 0 {
+		return nil, ErrInvalidWeights
+	}
+	weights := make([]int64, n)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return AllocateByWeights(m, weights)
+}
+
+// AllocateByWeights splits m into parts proportional to weights, e.g. the
+// prices of the order items, that sum up to m exactly. The parts are whole
+// minor units of the currency of m, or nanos if the currency is unknown.
+//
+// The parts are rounded towards zero, and the minor units left over are
+// given one each to the parts with the largest remainders, with ties going
+// to the earlier part. If m is not a whole number of minor units, the excess
+// nanos go to the first part with a positive weight.
+func AllocateByWeights(m *pb.Money, weights []int64) ([]*pb.Money, error) {
+	if !IsValid(m) {
+		return nil, ErrInvalidValue
+	}
+	total := big.NewInt(0)
+	for _, w := range weights {
+		if w < 0 {
+			return nil, ErrInvalidWeights
+		}
+		total.Add(total, big.NewInt(w))
+	}
+	if total.Sign() == 0 {
+		return nil, ErrInvalidWeights
+	}
+
+	step := big.NewInt(1)
+	if digits, err := MinorUnits(m.GetCurrencyCode()); err == nil {
+		step = pow10(9 - digits)
+	}
+	// Allocate the absolute amount, and restore the sign at the end.
+	nanos := toNanos(m)
+	sign := nanos.Sign()
+	nanos.Abs(nanos)
+	quanta, excess := new(big.Int).QuoRem(nanos, step, new(big.Int))
+
+	shares := make([]*big.Int, len(weights))
+	remainders := make([]*big.Int, len(weights))
+	left := new(big.Int).Set(quanta)
+	for i, w := range weights {
+		share := new(big.Int).Mul(quanta, big.NewInt(w))
+		shares[i], remainders[i] = share.QuoRem(share, total, new(big.Int))
+		left.Sub(left, shares[i])
+	}
+
+	// left is less than the number of parts with a non-zero remainder.
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return -remainders[a].Cmp(remainders[b])
+	})
+	for _, i := range order[:left.Int64()] {
+		shares[i].Add(shares[i], big.NewInt(1))
+	}
+
+	first := slices.IndexFunc(weights, func(w int64) bool { return w > 0 })
+	parts := make([]*pb.Money, len(weights))
+	for i, share := range shares {
+		share.Mul(share, step)
+		if i == first {
+			share.Add(share, excess)
+		}
+		if sign < 0 {
+			share.Neg(share)
+		}
+		// A part is never larger than m, so it cannot overflow.
+		parts[i], _ = fromNanos(share, m.GetCurrencyCode())
+	}
+	return parts, nil
+}
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package money
+
+import (
+	"maps"
+	"math/big"
+	"reflect"
+	"slices"
+	"testing"
+	"testing/quick"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      *pb.Money
+		n       int
+		want    []*pb.Money
+		wantErr error
+	}{
+		{"even", mmc(9, 0, "USD"), 3, []*pb.Money{mmc(3, 0, "USD"), mmc(3, 0, "USD"), mmc(3, 0, "USD")}, nil},
+		{"cents left over", mmc(10, 0, "USD"), 3, []*pb.Money{mmc(3, 340000000, "USD"), mmc(3, 330000000, "USD"), mmc(3, 330000000, "USD")}, nil},
+		{"negative", mmc(-10, 0, "USD"), 3, []*pb.Money{mmc(-3, -340000000, "USD"), mmc(-3, -330000000, "USD"), mmc(-3, -330000000, "USD")}, nil},
+		{"yen", mmc(100, 0, "JPY"), 3, []*pb.Money{mmc(34, 0, "JPY"), mmc(33, 0, "JPY"), mmc(33, 0, "JPY")}, nil},
+		{"dinar", mmc(1, 0, "BHD"), 3, []*pb.Money{mmc(0, 334000000, "BHD"), mmc(0, 333000000, "BHD"), mmc(0, 333000000, "BHD")}, nil},
+		{"fewer minor units than parts", mmc(0, 20000000, "USD"), 3, []*pb.Money{mmc(0, 10000000, "USD"), mmc(0, 10000000, "USD"), mmc(0, 0, "USD")}, nil},
+		{"sub-cent excess", mmc(1, 5, "USD"), 2, []*pb.Money{mmc(0, 500000005, "USD"), mmc(0, 500000000, "USD")}, nil},
+		{"unknown currency", mm(0, 10), 3, []*pb.Money{mm(0, 4), mm(0, 3), mm(0, 3)}, nil},
+		{"Error: no parts", mmc(1, 0, "USD"), 0, nil, ErrInvalidWeights},
+		{"Error: invalid value", mmc(1, -1, "USD"), 2, nil, ErrInvalidValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Allocate(tt.in, tt.n)
+			if err != tt.wantErr {
+				t.Errorf("Allocate([%v],%d): expected err=\"%v\" got=\"%v\"", tt.in, tt.n, tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Allocate([%v],%d) = %v, want %v", tt.in, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocateByWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      *pb.Money
+		weights []int64
+		want    []*pb.Money
+		wantErr error
+	}{
+		{"proportional", mmc(10, 0, "USD"), []int64{1, 3}, []*pb.Money{mmc(2, 500000000, "USD"), mmc(7, 500000000, "USD")}, nil},
+		{"largest remainder", mmc(1, 0, "USD"), []int64{1, 1, 4}, []*pb.Money{mmc(0, 170000000, "USD"), mmc(0, 170000000, "USD"), mmc(0, 660000000, "USD")}, nil},
+		{"zero weight", mmc(5, 0, "EUR"), []int64{0, 2, 3}, []*pb.Money{mmc(0, 0, "EUR"), mmc(2, 0, "EUR"), mmc(3, 0, "EUR")}, nil},
+		{"excess to first weighted part", mmc(0, 7, "EUR"), []int64{0, 1}, []*pb.Money{mmc(0, 0, "EUR"), mmc(0, 7, "EUR")}, nil},
+		{"Error: negative weight", mmc(5, 0, "EUR"), []int64{1, -1}, nil, ErrInvalidWeights},
+		{"Error: zero weights", mmc(5, 0, "EUR"), []int64{0, 0}, nil, ErrInvalidWeights},
+		{"Error: no weights", mmc(5, 0, "EUR"), nil, nil, ErrInvalidWeights},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AllocateByWeights(tt.in, tt.weights)
+			if err != tt.wantErr {
+				t.Errorf("AllocateByWeights([%v],%v): expected err=\"%v\" got=\"%v\"", tt.in, tt.weights, tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AllocateByWeights([%v],%v) = %v, want %v", tt.in, tt.weights, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAllocateByWeights_properties checks for random values, weights and
+// currencies that the parts are valid, keep the currency, sum up to the
+// allocated value and are within one minor unit of their exact share.
+func TestAllocateByWeights_properties(t *testing.T) {
+	codes := slices.Sorted(maps.Keys(minorUnits))
+	codes = append(codes, "")
+
+	property := func(units int64, nanos int32, weights []uint32, currency uint16) bool {
+		m := fuzzMoney(units, nanos)
+		m.CurrencyCode = codes[int(currency)%len(codes)]
+		w := make([]int64, len(weights)+1)
+		for i, weight := range weights {
+			w[i] = int64(weight)
+		}
+		w[len(weights)] = 1
+
+		parts, err := AllocateByWeights(m, w)
+		if err != nil || len(parts) != len(w) {
+			t.Logf("AllocateByWeights([%v],%v): %v, err=\"%v\"", m, w, parts, err)
+			return false
+		}
+
+		// whole is m without the excess nanos, which go to the first part.
+		step, whole := big.NewRat(1, nanosMod), ratOf(m)
+		if digits, err := MinorUnits(m.CurrencyCode); err == nil {
+			step = new(big.Rat).SetFrac(big.NewInt(1), pow10(digits))
+			rounded, err := RoundToMinorUnits(m, RoundDown)
+			if err != nil {
+				t.Logf("RoundToMinorUnits([%v]): err=\"%v\"", m, err)
+				return false
+			}
+			whole = ratOf(rounded)
+		}
+		first := slices.IndexFunc(w, func(weight int64) bool { return weight > 0 })
+		var total int64
+		for _, weight := range w {
+			total += weight
+		}
+
+		sum := new(big.Rat)
+		for i, p := range parts {
+			if !IsValid(p) || p.CurrencyCode != m.CurrencyCode {
+				t.Logf("part %d of [%v] is %v", i, m, p)
+				return false
+			}
+			sum.Add(sum, ratOf(p))
+
+			// The part must be within a minor unit of its share of whole.
+			exact := new(big.Rat).Mul(whole, big.NewRat(w[i], total))
+			diff := new(big.Rat).Sub(ratOf(p), exact)
+			if i == first {
+				diff.Sub(diff, new(big.Rat).Sub(ratOf(m), whole))
+			}
+			diff.Abs(diff)
+			if diff.Cmp(step) >= 0 {
+				t.Logf("part %d of [%v] by %v is %v, off by %v", i, m, w, p, diff.FloatString(9))
+				return false
+			}
+		}
+		if sum.Cmp(ratOf(m)) != 0 {
+			t.Logf("parts of [%v] by %v sum up to %v", m, w, sum.FloatString(9))
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package money
+
+import (
+	"errors"
+	"math/big"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+var (
+	ErrOverflow       = errors.New("money value overflows the units range")
+	ErrDivisionByZero = errors.New("division by zero")
+)
+
+// RoundingMode specifies how results that are not a whole number of nanos
+// are rounded.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest value, and ties to the even one
+	// ("banker's rounding").
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest value, and ties away from zero.
+	RoundHalfUp
+	// RoundHalfDown rounds to the nearest value, and ties towards zero.
+	RoundHalfDown
+	// RoundDown rounds towards zero (truncation).
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor
+	// RoundCeiling rounds towards positive infinity.
+	RoundCeiling
+)
+
+func (r RoundingMode) String() string {
+	switch r {
+	case RoundHalfEven:
+		return "half-even"
+	case RoundHalfUp:
+		return "half-up"
+	case RoundHalfDown:
+		return "half-down"
+	case RoundDown:
+		return "down"
+	case RoundUp:
+		return "up"
+	case RoundFloor:
+		return "floor"
+	case RoundCeiling:
+		return "ceiling"
+	default:
+		return "unknown"
+	}
+}
+
+var bigNanosMod = big.NewInt(nanosMod)
+
+// toNanos returns m as a number of nanos.
+func toNanos(m *pb.Money) *big.Int {
+	n := new(big.Int).Mul(big.NewInt(m.GetUnits()), bigNanosMod)
+	return n.Add(n, big.NewInt(int64(m.GetNanos())))
+}
+
+// fromNanos converts a number of nanos back to a money value, or returns
+// ErrOverflow if it does not fit.
+func fromNanos(n *big.Int, currencyCode string) (*pb.Money, error) {
+	// QuoRem truncates, so units and nanos get the same sign.
+	units, nanos := new(big.Int).QuoRem(n, bigNanosMod, new(big.Int))
+	if !units.IsInt64() {
+		return &pb.Money{}, ErrOverflow
+	}
+	return &pb.Money{
+		Units:        units.Int64(),
+		Nanos:        int32(nanos.Int64()),
+		CurrencyCode: currencyCode}, nil
+}
+
+// quoRound returns num/den rounded to an integer with mode. den must not be
+// zero.
+func quoRound(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	// The sign of the exact quotient; q may be zero.
+	sign := num.Sign() * den.Sign()
+	// Compare the discarded fraction against one half: 2|r| <=> |den|.
+	half := new(big.Int).Abs(r)
+	half.Lsh(half, 1)
+	cmpHalf := half.Cmp(new(big.Int).Abs(den))
+
+	var away bool
+	switch mode {
+	case RoundHalfEven:
+		away = cmpHalf > 0 || (cmpHalf == 0 && q.Bit(0) == 1)
+	case RoundHalfUp:
+		away = cmpHalf >= 0
+	case RoundHalfDown:
+		away = cmpHalf > 0
+	case RoundDown:
+		away = false
+	case RoundUp:
+		away = true
+	case RoundFloor:
+		away = sign < 0
+	case RoundCeiling:
+		away = sign > 0
+	}
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	return q
+}
+
+// Multiply returns m multiplied by n. The result is exact, so an error is
+// only returned if m is invalid or the result overflows.
+func Multiply(m *pb.Money, n int64) (*pb.Money, error) {
+	if !IsValid(m) {
+		return &pb.Money{}, ErrInvalidValue
+	}
+	nanos := toNanos(m)
+	return fromNanos(nanos.Mul(nanos, big.NewInt(n)), m.GetCurrencyCode())
+}
+
+// MultiplyRat returns m multiplied by the decimal or fraction f, rounded to
+// nanos with mode. Use big.Rat's SetString to create f from a decimal
+// string such as "0.85".
+func MultiplyRat(m *pb.Money, f *big.Rat, mode RoundingMode) (*pb.Money, error) {
+	if !IsValid(m) {
+		return &pb.Money{}, ErrInvalidValue
+	}
+	num := toNanos(m)
+	num.Mul(num, f.Num())
+	return fromNanos(quoRound(num, f.Denom(), mode), m.GetCurrencyCode())
+}
+
+// Divide returns m divided by d, rounded to nanos with mode.
+func Divide(m *pb.Money, d int64, mode RoundingMode) (*pb.Money, error) {
+	if !IsValid(m) {
+		return &pb.Money{}, ErrInvalidValue
+	} else if d == 0 {
+		return &pb.Money{}, ErrDivisionByZero
+	}
+	return fromNanos(quoRound(toNanos(m), big.NewInt(d), mode), m.GetCurrencyCode())
+}
+
+// Percentage returns percent percent of m, e.g. 7.25% with percent 29/4,
+// rounded to nanos with mode.
+func Percentage(m *pb.Money, percent *big.Rat, mode RoundingMode) (*pb.Money, error) {
+	f := new(big.Rat).Quo(percent, big.NewRat(100, 1))
+	return MultiplyRat(m, f, mode)
+}
+
+// Compare returns -1, 0 or +1 depending on whether l is less than, equal to
+// or greater than r. Returns an error if one of the values is invalid or the
+// currency codes are not matching.
+func Compare(l, r *pb.Money) (int, error) {
+	if !IsValid(l) || !IsValid(r) {
+		return 0, ErrInvalidValue
+	} else if l.GetCurrencyCode() != r.GetCurrencyCode() {
+		return 0, ErrMismatchingCurrency
+	}
+	switch {
+	case l.GetUnits() < r.GetUnits():
+		return -1, nil
+	case l.GetUnits() > r.GetUnits():
+		return +1, nil
+	case l.GetNanos() < r.GetNanos():
+		return -1, nil
+	case l.GetNanos() > r.GetNanos():
+		return +1, nil
+	default:
+		return 0, nil
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package money
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+func rat(s string) *big.Rat {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		panic("invalid rational " + s)
+	}
+	return r
+}
+
+func TestMultiply(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       *pb.Money
+		n       int64
+		want    *pb.Money
+		wantErr error
+	}{
+		{"by zero", mmc(12, 340000000, "USD"), 0, mmc(0, 0, "USD"), nil},
+		{"by one", mmc(12, 340000000, "USD"), 1, mmc(12, 340000000, "USD"), nil},
+		{"carry", mmc(12, 340000000, "USD"), 3, mmc(37, 20000000, "USD"), nil},
+		{"negative factor", mmc(12, 340000000, "USD"), -2, mmc(-24, -680000000, "USD"), nil},
+		{"negative value", mmc(-1, -500000000, "USD"), 3, mmc(-4, -500000000, "USD"), nil},
+		{"large", mm(0, 999999999), math.MaxInt32, mm(2147483644, 852516353), nil},
+		{"max units", mm(math.MaxInt64, 0), 1, mm(math.MaxInt64, 0), nil},
+		{"Error: overflow", mm(math.MaxInt64/2+1, 0), 2, mm(0, 0), ErrOverflow},
+		{"Error: overflow by nanos", mm(math.MaxInt64, 500000000), 2, mm(0, 0), ErrOverflow},
+		{"Error: invalid value", mm(1, -1), 2, mm(0, 0), ErrInvalidValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Multiply(tt.m, tt.n)
+			if err != tt.wantErr {
+				t.Errorf("Multiply([%v],%d): expected err=\"%v\" got=\"%v\"", tt.m, tt.n, tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Multiply([%v],%d) = %v, want %v", tt.m, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDivide(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       *pb.Money
+		d       int64
+		mode    RoundingMode
+		want    *pb.Money
+		wantErr error
+	}{
+		{"exact", mmc(10, 0, "EUR"), 4, RoundHalfEven, mmc(2, 500000000, "EUR"), nil},
+		{"half-even (down to even)", mm(0, 5), 2, RoundHalfEven, mm(0, 2), nil},
+		{"half-even (up to even)", mm(0, 7), 2, RoundHalfEven, mm(0, 4), nil},
+		{"half-even (negative)", mm(0, -5), 2, RoundHalfEven, mm(0, -2), nil},
+		{"half-up", mm(0, 5), 2, RoundHalfUp, mm(0, 3), nil},
+		{"half-up (negative)", mm(0, -5), 2, RoundHalfUp, mm(0, -3), nil},
+		{"half-down", mm(0, 5), 2, RoundHalfDown, mm(0, 2), nil},
+		{"half-down (above half)", mm(10, 0), 3, RoundHalfDown, mm(3, 333333333), nil},
+		{"down", mm(20, 0), 3, RoundDown, mm(6, 666666666), nil},
+		{"down (negative)", mm(-20, 0), 3, RoundDown, mm(-6, -666666666), nil},
+		{"up", mm(10, 0), 3, RoundUp, mm(3, 333333334), nil},
+		{"up (negative)", mm(-10, 0), 3, RoundUp, mm(-3, -333333334), nil},
+		{"floor", mm(10, 0), 3, RoundFloor, mm(3, 333333333), nil},
+		{"floor (negative)", mm(-10, 0), 3, RoundFloor, mm(-3, -333333334), nil},
+		{"ceiling", mm(10, 0), 3, RoundCeiling, mm(3, 333333334), nil},
+		{"ceiling (negative)", mm(-10, 0), 3, RoundCeiling, mm(-3, -333333333), nil},
+		{"ceiling (below one nano)", mm(0, 1), 3, RoundCeiling, mm(0, 1), nil},
+		{"floor (below one nano, negative)", mm(0, 1), -3, RoundFloor, mm(0, -1), nil},
+		{"negative divisor", mm(7, 0), -2, RoundHalfEven, mm(-3, -500000000), nil},
+		{"Error: overflow", mm(math.MinInt64, 0), -1, RoundHalfEven, mm(0, 0), ErrOverflow},
+		{"Error: division by zero", mm(1, 0), 0, RoundHalfEven, mm(0, 0), ErrDivisionByZero},
+		{"Error: invalid value", mm(0, 1000000000), 2, RoundHalfEven, mm(0, 0), ErrInvalidValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Divide(tt.m, tt.d, tt.mode)
+			if err != tt.wantErr {
+				t.Errorf("Divide([%v],%d,%v): expected err=\"%v\" got=\"%v\"", tt.m, tt.d, tt.mode, tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Divide([%v],%d,%v) = %v, want %v", tt.m, tt.d, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiplyRat(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *pb.Money
+		f    string
+		mode RoundingMode
+		want *pb.Money
+	}{
+		{"decimal", mmc(19, 990000000, "USD"), "0.85", RoundHalfEven, mmc(16, 991500000, "USD")},
+		{"fraction", mm(1, 0), "1/3", RoundHalfEven, mm(0, 333333333)},
+		{"fraction rounded up", mm(2, 0), "1/3", RoundHalfEven, mm(0, 666666667)},
+		{"negative factor", mm(2, 0), "-1/3", RoundCeiling, mm(0, -666666666)},
+		{"more than one", mm(100, 0), "1.075", RoundHalfEven, mm(107, 500000000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MultiplyRat(tt.m, rat(tt.f), tt.mode)
+			if err != nil {
+				t.Fatalf("MultiplyRat([%v],%s,%v): unexpected err=\"%v\"", tt.m, tt.f, tt.mode, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MultiplyRat([%v],%s,%v) = %v, want %v", tt.m, tt.f, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercentage(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       *pb.Money
+		percent string
+		mode    RoundingMode
+		want    *pb.Money
+	}{
+		{"whole percent", mmc(80, 0, "USD"), "15", RoundHalfEven, mmc(12, 0, "USD")},
+		{"decimal percent", mmc(19, 990000000, "USD"), "7.25", RoundHalfEven, mmc(1, 449275000, "USD")},
+		{"rounded", mm(0, 7), "50", RoundHalfEven, mm(0, 4)},
+		{"rounded down", mm(0, 7), "50", RoundDown, mm(0, 3)},
+		{"over 100", mm(3, 0), "250", RoundHalfEven, mm(7, 500000000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Percentage(tt.m, rat(tt.percent), tt.mode)
+			if err != nil {
+				t.Fatalf("Percentage([%v],%s,%v): unexpected err=\"%v\"", tt.m, tt.percent, tt.mode, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Percentage([%v],%s,%v) = %v, want %v", tt.m, tt.percent, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name    string
+		l, r    *pb.Money
+		want    int
+		wantErr error
+	}{
+		{"equal", mm(1, 5), mm(1, 5), 0, nil},
+		{"units less", mm(1, 999999999), mm(2, 0), -1, nil},
+		{"units greater", mm(2, 0), mm(1, 999999999), +1, nil},
+		{"nanos less", mm(1, 1), mm(1, 2), -1, nil},
+		{"negative nanos", mm(0, -2), mm(0, -1), -1, nil},
+		{"negative units", mm(-1, -1), mm(-1, 0), -1, nil},
+		{"Error: invalid value", mm(1, -1), mm(0, 0), 0, ErrInvalidValue},
+		{"Error: currency code mismatch", mmc(1, 0, "USD"), mmc(1, 0, "EUR"), 0, ErrMismatchingCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Compare(tt.l, tt.r)
+			if err != tt.wantErr {
+				t.Errorf("Compare([%v],[%v]): expected err=\"%v\" got=\"%v\"", tt.l, tt.r, tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Compare([%v],[%v]) = %d, want %d", tt.l, tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSum_overflow(t *testing.T) {
+	for _, args := range [][2]*pb.Money{
+		{mm(math.MaxInt64, 0), mm(1, 0)},
+		{mm(math.MaxInt64, 600000000), mm(0, 600000000)},
+		{mm(math.MinInt64, 0), mm(-1, 0)},
+		{mm(math.MinInt64, -600000000), mm(0, -600000000)},
+	} {
+		if _, err := Sum(args[0], args[1]); err != ErrOverflow {
+			t.Errorf("Sum([%v],[%v]): expected err=\"%v\" got=\"%v\"", args[0], args[1], ErrOverflow, err)
+		}
+	}
+	if got, err := Sum(mm(math.MaxInt64, 0), mm(-1, 0)); err != nil || !AreEquals(got, mm(math.MaxInt64-1, 0)) {
+		t.Errorf("Sum near the limit = %v, %v", got, err)
+	}
+}
+
+// fuzzMoney builds a valid money value from fuzzer input.
+func fuzzMoney(units int64, nanos int32) *pb.Money {
+	nanos %= nanosMod
+	if units != 0 && nanos != 0 && (units < 0) != (nanos < 0) {
+		nanos = -nanos
+	}
+	return mm(units, nanos)
+}
+
+// ratOf returns m as an exact rational number of units.
+func ratOf(m *pb.Money) *big.Rat {
+	r := new(big.Rat).SetInt64(m.GetUnits())
+	return r.Add(r, big.NewRat(int64(m.GetNanos()), nanosMod))
+}
+
+// roundRat rounds r to whole nanos with mode. It works from the floor of
+// the value, rather than the truncated quotient used by quoRound.
+func roundRat(r *big.Rat, mode RoundingMode) *big.Int {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt64(nanosMod))
+	floor := new(big.Int).Div(scaled.Num(), scaled.Denom())
+	frac := new(big.Rat).Sub(scaled, new(big.Rat).SetInt(floor))
+	if frac.Sign() == 0 {
+		return floor
+	}
+	ceil := new(big.Int).Add(floor, big.NewInt(1))
+	half := frac.Cmp(big.NewRat(1, 2))
+	towardZero, awayFromZero := floor, ceil
+	if scaled.Sign() < 0 {
+		towardZero, awayFromZero = ceil, floor
+	}
+	// nearest resolves ties with tie, and picks the closer value otherwise.
+	nearest := func(tie *big.Int) *big.Int {
+		switch {
+		case half < 0:
+			return floor
+		case half > 0:
+			return ceil
+		default:
+			return tie
+		}
+	}
+	switch mode {
+	case RoundHalfEven:
+		if floor.Bit(0) == 0 {
+			return nearest(floor)
+		}
+		return nearest(ceil)
+	case RoundHalfUp:
+		return nearest(awayFromZero)
+	case RoundHalfDown:
+		return nearest(towardZero)
+	case RoundDown:
+		return towardZero
+	case RoundUp:
+		return awayFromZero
+	case RoundFloor:
+		return floor
+	default:
+		return ceil
+	}
+}
+
+// checkAgainstBig compares got with the nanos want computed with math/big.
+func checkAgainstBig(t *testing.T, got *pb.Money, err error, want *big.Int) {
+	t.Helper()
+	units := new(big.Int).Quo(want, bigNanosMod)
+	if !units.IsInt64() {
+		if err != ErrOverflow {
+			t.Fatalf("expected err=\"%v\" for %v nanos, got %v, err=\"%v\"", ErrOverflow, want, got, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected err=\"%v\" for %v nanos", err, want)
+	}
+	if !IsValid(got) {
+		t.Fatalf("invalid result %v", got)
+	}
+	if toNanos(got).Cmp(want) != 0 {
+		t.Fatalf("got %v, want %v nanos", got, want)
+	}
+}
+
+func FuzzMultiply(f *testing.F) {
+	f.Add(int64(12), int32(340000000), int64(3))
+	f.Add(int64(-1), int32(-500000000), int64(-7))
+	f.Add(int64(math.MaxInt64), int32(999999999), int64(2))
+	f.Add(int64(math.MinInt64), int32(0), int64(-1))
+	f.Fuzz(func(t *testing.T, units int64, nanos int32, n int64) {
+		m := fuzzMoney(units, nanos)
+		got, err := Multiply(m, n)
+		want := new(big.Rat).Mul(ratOf(m), new(big.Rat).SetInt64(n))
+		checkAgainstBig(t, got, err, roundRat(want, RoundDown))
+	})
+}
+
+func FuzzDivide(f *testing.F) {
+	f.Add(int64(10), int32(0), int64(3), uint8(RoundHalfEven))
+	f.Add(int64(0), int32(-5), int64(2), uint8(RoundHalfUp))
+	f.Add(int64(-7), int32(-250000000), int64(-4), uint8(RoundFloor))
+	f.Add(int64(math.MinInt64), int32(0), int64(-1), uint8(RoundCeiling))
+	f.Fuzz(func(t *testing.T, units int64, nanos int32, d int64, mode uint8) {
+		if d == 0 {
+			return
+		}
+		m := fuzzMoney(units, nanos)
+		rm := RoundingMode(mode % uint8(RoundCeiling+1))
+		got, err := Divide(m, d, rm)
+		want := new(big.Rat).Quo(ratOf(m), new(big.Rat).SetInt64(d))
+		checkAgainstBig(t, got, err, roundRat(want, rm))
+	})
+}
+
+func FuzzPercentage(f *testing.F) {
+	f.Add(int64(19), int32(990000000), int64(725), int64(100), uint8(RoundHalfEven))
+	f.Add(int64(-3), int32(-1), int64(1), int64(3), uint8(RoundUp))
+	f.Fuzz(func(t *testing.T, units int64, nanos int32, num, denom int64, mode uint8) {
+		if denom == 0 {
+			return
+		}
+		m := fuzzMoney(units, nanos)
+		rm := RoundingMode(mode % uint8(RoundCeiling+1))
+		percent := big.NewRat(num, denom)
+		got, err := Percentage(m, percent, rm)
+		want := new(big.Rat).Mul(ratOf(m), percent)
+		want.Quo(want, big.NewRat(100, 1))
+		checkAgainstBig(t, got, err, roundRat(want, rm))
+	})
+}
+
+func FuzzSum(f *testing.F) {
+	f.Add(int64(0), int32(990000000), int64(0), int32(10000000))
+	f.Add(int64(0), int32(500000000), int64(0), int32(-990000000))
+	f.Add(int64(1), int32(0), int64(0), int32(-1))
+	f.Add(int64(math.MaxInt64), int32(999999999), int64(0), int32(1))
+	f.Fuzz(func(t *testing.T, lUnits int64, lNanos int32, rUnits int64, rNanos int32) {
+		l, r := fuzzMoney(lUnits, lNanos), fuzzMoney(rUnits, rNanos)
+		got, err := Sum(l, r)
+		want := new(big.Rat).Add(ratOf(l), ratOf(r))
+		checkAgainstBig(t, got, err, roundRat(want, RoundDown))
+	})
+}
@@ -0,0 +1,27 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package money
+
+import (
+	"context"
+	"math/big"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+// RateProvider provides exchange rates between currencies.
+type RateProvider interface {
+	// Rate returns the value of one unit of currency from in currency to.
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+}
+
+// Convert returns m in currency to, given the rate of one unit of the
+// currency of m in it, rounded to nanos with mode.
+func Convert(m *pb.Money, to string, rate *big.Rat, mode RoundingMode) (*pb.Money, error) {
+	converted, err := MultiplyRat(m, rate, mode)
+	if err != nil {
+		return converted, err
+	}
+	converted.CurrencyCode = to
+	return converted, nil
+}
@@ -0,0 +1,15 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package money
+
+import "testing"
+
+func TestConvert(t *testing.T) {
+	got, err := Convert(mmc(10, 0, "USD"), "EUR", rat("0.9"), RoundHalfEven)
+	if err != nil || !AreEquals(got, mmc(9, 0, "EUR")) {
+		t.Errorf("Convert() = %v, err=\"%v\"", got, err)
+	}
+	if _, err := Convert(mmc(10, -1, "USD"), "EUR", rat("0.9"), RoundHalfEven); err != ErrInvalidValue {
+		t.Errorf("Convert(invalid): expected err=\"%v\" got=\"%v\"", ErrInvalidValue, err)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package money
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+var ErrUnknownCurrency = errors.New("unknown currency code")
+
+// minorUnits holds the number of decimal digits of the minor unit of the
+// active ISO 4217 currencies, e.g. cents for USD, and of HRK, which the
+// currency service still supports.
+var minorUnits = map[string]int{}
+
+func init() {
+	for digits, codes := range map[int]string{
+		0: "BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF",
+		2: "AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BMD BND BOB " +
+			"BOV BRL BSD BTN BWP BYN BZD CAD CDF CHE CHF CHW CNY COP COU CRC CUP " +
+			"CVE CZK DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GTQ " +
+			"GYD HKD HNL HRK HTG HUF IDR ILS INR IRR JMD KES KGS KHR KPW KYD KZT " +
+			"LAK LBP LKR LRD LSL MAD MDL MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV " +
+			"MYR MZN NAD NGN NIO NOK NPR NZD PAB PEN PGK PHP PKR PLN QAR RON RSD " +
+			"RUB SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB " +
+			"TJS TMT TOP TRY TTD TWD TZS UAH USD USN UYU UZS VED VES WST XCD YER " +
+			"ZAR ZMW ZWG",
+		3: "BHD IQD JOD KWD LYD OMR TND",
+		4: "CLF UYW",
+	} {
+		for _, code := range strings.Fields(codes) {
+			minorUnits[code] = digits
+		}
+	}
+}
+
+// MinorUnits returns the number of decimal digits of the minor unit of the
+// currency, e.g. 2 for USD, 0 for JPY and 3 for BHD.
+func MinorUnits(currencyCode string) (int, error) {
+	digits, ok := minorUnits[currencyCode]
+	if !ok {
+		return 0, ErrUnknownCurrency
+	}
+	return digits, nil
+}
+
+// pow10 returns 10^n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// RoundToMinorUnits rounds m to the minor unit of its currency with mode,
+// e.g. to whole cents for USD and to whole yen for JPY.
+func RoundToMinorUnits(m *pb.Money, mode RoundingMode) (*pb.Money, error) {
+	if !IsValid(m) {
+		return &pb.Money{}, ErrInvalidValue
+	}
+	digits, err := MinorUnits(m.GetCurrencyCode())
+	if err != nil {
+		return &pb.Money{}, err
+	}
+	step := pow10(9 - digits)
+	minor := quoRound(toNanos(m), step, mode)
+	return fromNanos(minor.Mul(minor, step), m.GetCurrencyCode())
+}
+
+// ToDecimalString returns m as a decimal number of units, without the
+// currency code, e.g. "12.50" for 12 USD and 500000000 nanos. It shows at
+// least the minor units of the currency, and more digits if m is not a whole
+// number of minor units. The value is assumed to be valid.
+func ToDecimalString(m *pb.Money) string {
+	units, nanos := m.GetUnits(), m.GetNanos()
+
+	var b strings.Builder
+	if units < 0 || nanos < 0 {
+		b.WriteByte('-')
+	}
+	// Formatting the unsigned value avoids overflowing on -MinInt64.
+	u := uint64(units)
+	if units < 0 {
+		u = -u
+	}
+	b.WriteString(strconv.FormatUint(u, 10))
+
+	if nanos < 0 {
+		nanos = -nanos
+	}
+	frac := strconv.Itoa(int(nanos) + nanosMod)[1:]
+	digits := minorUnits[m.GetCurrencyCode()]
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) < digits {
+		frac += strings.Repeat("0", digits-len(frac))
+	}
+	if frac != "" {
+		b.WriteByte('.')
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// ToRat returns m as an exact number of units.
+func ToRat(m *pb.Money) *big.Rat {
+	return new(big.Rat).SetFrac(toNanos(m), bigNanosMod)
+}
+
+// ToFloat64 returns m as the float64 closest to its number of units, e.g.
+// for metrics and span attributes. It must not be used for arithmetic.
+func ToFloat64(m *pb.Money) float64 {
+	f, _ := ToRat(m).Float64()
+	return f
+}
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package money
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+func TestMinorUnits(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    int
+		wantErr error
+	}{
+		{"USD", 2, nil},
+		{"EUR", 2, nil},
+		{"JPY", 0, nil},
+		{"KRW", 0, nil},
+		{"BHD", 3, nil},
+		{"CLF", 4, nil},
+		{"XXX", 0, ErrUnknownCurrency},
+		{"", 0, ErrUnknownCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			got, err := MinorUnits(tt.code)
+			if err != tt.wantErr {
+				t.Errorf("MinorUnits(%q): expected err=\"%v\" got=\"%v\"", tt.code, tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("MinorUnits(%q) = %d, want %d", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundToMinorUnits(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      *pb.Money
+		mode    RoundingMode
+		want    *pb.Money
+		wantErr error
+	}{
+		{"USD half-even down", mmc(1, 125000000, "USD"), RoundHalfEven, mmc(1, 120000000, "USD"), nil},
+		{"USD half-even up", mmc(1, 135000000, "USD"), RoundHalfEven, mmc(1, 140000000, "USD"), nil},
+		{"USD half-up", mmc(1, 125000000, "USD"), RoundHalfUp, mmc(1, 130000000, "USD"), nil},
+		{"USD half-up (negative)", mmc(-1, -125000000, "USD"), RoundHalfUp, mmc(-1, -130000000, "USD"), nil},
+		{"USD down", mmc(1, 129999999, "USD"), RoundDown, mmc(1, 120000000, "USD"), nil},
+		{"USD carry", mmc(1, 995000000, "USD"), RoundHalfUp, mmc(2, 0, "USD"), nil},
+		{"USD already rounded", mmc(3, 450000000, "USD"), RoundUp, mmc(3, 450000000, "USD"), nil},
+		{"JPY", mmc(1234, 500000000, "JPY"), RoundHalfEven, mmc(1234, 0, "JPY"), nil},
+		{"JPY half-up", mmc(1234, 500000000, "JPY"), RoundHalfUp, mmc(1235, 0, "JPY"), nil},
+		{"BHD", mmc(7, 123456789, "BHD"), RoundHalfEven, mmc(7, 123000000, "BHD"), nil},
+		{"BHD half-up", mmc(7, 123500000, "BHD"), RoundHalfUp, mmc(7, 124000000, "BHD"), nil},
+		{"Error: overflow", mmc(math.MaxInt64, 999999999, "USD"), RoundHalfUp, mm(0, 0), ErrOverflow},
+		{"Error: unknown currency", mmc(1, 0, "XXX"), RoundHalfEven, mm(0, 0), ErrUnknownCurrency},
+		{"Error: invalid value", mmc(1, -1, "USD"), RoundHalfEven, mm(0, 0), ErrInvalidValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RoundToMinorUnits(tt.in, tt.mode)
+			if err != tt.wantErr {
+				t.Errorf("RoundToMinorUnits([%v],%v): expected err=\"%v\" got=\"%v\"", tt.in, tt.mode, tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RoundToMinorUnits([%v],%v) = %v, want %v", tt.in, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToDecimalString(t *testing.T) {
+	tests := []struct {
+		in   *pb.Money
+		want string
+	}{
+		{mmc(12, 500000000, "USD"), "12.50"},
+		{mmc(12, 0, "USD"), "12.00"},
+		{mmc(0, 50000000, "USD"), "0.05"},
+		{mmc(1, 234500000, "USD"), "1.2345"},
+		{mmc(0, -500000000, "USD"), "-0.50"},
+		{mmc(-3, -10000000, "EUR"), "-3.01"},
+		{mmc(1200, 0, "JPY"), "1200"},
+		{mmc(1200, 500000000, "JPY"), "1200.5"},
+		{mmc(7, 5000000, "BHD"), "7.005"},
+		{mm(5, 1), "5.000000001"},
+		{mm(5, 0), "5"},
+		{mm(math.MinInt64, -999999999), "-9223372036854775808.999999999"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := ToDecimalString(tt.in); got != tt.want {
+				t.Errorf("ToDecimalString(%v) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToFloat64(t *testing.T) {
+	tests := []struct {
+		in   *pb.Money
+		want float64
+	}{
+		{mmc(12, 500000000, "USD"), 12.5},
+		{mmc(0, 990000000, "USD"), 0.99},
+		{mmc(-3, -10000000, "EUR"), -3.01},
+		{mmc(1200, 0, "JPY"), 1200},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.FormatFloat(tt.want, 'f', -1, 64), func(t *testing.T) {
+			if got := ToFloat64(tt.in); got != tt.want {
+				t.Errorf("ToFloat64(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzToDecimalString(f *testing.F) {
+	f.Add(int64(12), int32(500000000), "USD")
+	f.Add(int64(-1), int32(-1), "JPY")
+	f.Fuzz(func(t *testing.T, units int64, nanos int32, code string) {
+		m := fuzzMoney(units, nanos)
+		m.CurrencyCode = code
+		got, ok := ratOf(m).SetString(ToDecimalString(m))
+		if !ok {
+			t.Fatalf("ToDecimalString(%v) = %q is not a decimal", m, ToDecimalString(m))
+		}
+		if got.Cmp(ratOf(m)) != 0 {
+			t.Fatalf("ToDecimalString(%v) = %q, want %v", m, ToDecimalString(m), ratOf(m).FloatString(9))
+		}
+		if want, _ := ratOf(m).Float64(); ToFloat64(m) != want {
+			t.Fatalf("ToFloat64(%v) = %v, want %v", m, ToFloat64(m), want)
+		}
+	})
+}
@@ -0,0 +1,292 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package money
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+var ErrInvalidAmount = errors.New("invalid money amount")
+
+const (
+	nbsp  = "\u00a0" // no-break space
+	nnbsp = "\u202f" // narrow no-break space
+)
+
+// symbols holds the CLDR symbols of the currencies supported by the currency
+// service in the root locale. Currencies without one are shown with their
+// code.
+var symbols = map[string]string{
+	"AUD": "A$",
+	"BRL": "R$",
+	"CAD": "CA$",
+	"CNY": "CN¥",
+	"EUR": "€",
+	"GBP": "£",
+	"HKD": "HK$",
+	"ILS": "₪",
+	"INR": "₹",
+	"JPY": "¥",
+	"KRW": "₩",
+	"MXN": "MX$",
+	"NZD": "NZ$",
+	"PHP": "₱",
+	"USD": "US$",
+}
+
+// locale holds the CLDR number formatting conventions of a locale.
+type locale struct {
+	decimal string
+	group   string
+
+	// symbolAfter places the symbol after the number, separated by
+	// spacing.
+	symbolAfter bool
+	spacing     string
+
+	// minGrouping is the number of integer digits from which they are
+	// grouped, e.g. 5 for "1234,56 €" but "12.345,67 €".
+	minGrouping int
+
+	// indianGrouping groups all but the last three integer digits by two,
+	// e.g. "₹12,34,567.00".
+	indianGrouping bool
+
+	// symbols overrides the root symbols of some currencies, e.g. "$" for
+	// USD in en-US.
+	symbols map[string]string
+}
+
+var locales = map[string]locale{
+	"cs":    {decimal: ",", group: nbsp, symbolAfter: true, spacing: nbsp, symbols: map[string]string{"CZK": "Kč"}},
+	"da":    {decimal: ",", group: ".", symbolAfter: true, spacing: nbsp, symbols: map[string]string{"DKK": "kr."}},
+	"de":    {decimal: ",", group: ".", symbolAfter: true, spacing: nbsp},
+	"de-CH": {decimal: ".", group: "’", spacing: nbsp, symbols: map[string]string{"EUR": "€"}},
+	"en":    {decimal: ".", group: ",", symbols: map[string]string{"USD": "$"}},
+	"en-AU": {decimal: ".", group: ",", symbols: map[string]string{"AUD": "$", "USD": "USD"}},
+	"en-CA": {decimal: ".", group: ",", symbols: map[string]string{"CAD": "$", "USD": "US$"}},
+	"en-GB": {decimal: ".", group: ","},
+	"en-IN": {decimal: ".", group: ",", indianGrouping: true},
+	"en-NZ": {decimal: ".", group: ",", symbols: map[string]string{"NZD": "$"}},
+	"en-SG": {decimal: ".", group: ",", symbols: map[string]string{"SGD": "$"}},
+	"en-ZA": {decimal: ",", group: nbsp, symbols: map[string]string{"ZAR": "R"}},
+	"es":    {decimal: ",", group: ".", symbolAfter: true, spacing: nbsp, minGrouping: 5},
+	"es-MX": {decimal: ".", group: ",", symbols: map[string]string{"MXN": "$", "USD": "USD"}},
+	"fr":    {decimal: ",", group: nnbsp, symbolAfter: true, spacing: nbsp, symbols: map[string]string{"USD": "$US"}},
+	"fr-CA": {decimal: ",", group: nbsp, symbolAfter: true, spacing: nbsp, symbols: map[string]string{"CAD": "$", "USD": "$ US"}},
+	"hu":    {decimal: ",", group: nbsp, symbolAfter: true, spacing: nbsp, symbols: map[string]string{"HUF": "Ft"}},
+	"it":    {decimal: ",", group: ".", symbolAfter: true, spacing: nbsp, symbols: map[string]string{"USD": "USD"}},
+	"ja":    {decimal: ".", group: ",", symbols: map[string]string{"JPY": "￥", "USD": "$"}},
+	"ko":    {decimal: ".", group: ",", symbols: map[string]string{"USD": "US$"}},
+	"nb":    {decimal: ",", group: nbsp, symbolAfter: true, spacing: nbsp, symbols: map[string]string{"NOK": "kr"}},
+	"pl":    {decimal: ",", group: nbsp, symbolAfter: true, spacing: nbsp, minGrouping: 5, symbols: map[string]string{"PLN": "zł"}},
+	"pt":    {decimal: ",", group: ".", spacing: nbsp},
+	"ro":    {decimal: ",", group: ".", symbolAfter: true, spacing: nbsp},
+	"ru":    {decimal: ",", group: nbsp, symbolAfter: true, spacing: nbsp, symbols: map[string]string{"RUB": "₽", "USD": "$"}},
+	"sv":    {decimal: ",", group: nbsp, symbolAfter: true, spacing: nbsp, symbols: map[string]string{"SEK": "kr", "USD": "US$"}},
+	"th":    {decimal: ".", group: ",", symbols: map[string]string{"THB": "฿", "USD": "US$"}},
+	"tr":    {decimal: ",", group: ".", symbols: map[string]string{"TRY": "₺", "USD": "$"}},
+	"zh":    {decimal: ".", group: ",", symbols: map[string]string{"CNY": "¥", "USD": "US$"}},
+}
+
+// lookupLocale returns the conventions of a BCP 47 tag such as "de-CH",
+// falling back to its language, and then to English.
+func lookupLocale(tag string) locale {
+	tag = strings.ReplaceAll(tag, "_", "-")
+	for {
+		if l, ok := locales[tag]; ok {
+			return l
+		}
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			return locales["en"]
+		}
+		tag = tag[:i]
+	}
+}
+
+func (l locale) symbol(currencyCode string) string {
+	if s, ok := l.symbols[currencyCode]; ok {
+		return s
+	}
+	if s, ok := symbols[currencyCode]; ok {
+		return s
+	}
+	return currencyCode
+}
+
+// groupDigits inserts the group separator into the integer digits.
+func (l locale) groupDigits(digits string) string {
+	if len(digits) < max(l.minGrouping, 4) {
+		return digits
+	}
+	var groups []string
+	size := 3
+	for len(digits) > size {
+		groups = append(groups, digits[len(digits)-size:])
+		digits = digits[:len(digits)-size]
+		if l.indianGrouping {
+			size = 2
+		}
+	}
+	groups = append(groups, digits)
+	slices.Reverse(groups)
+	return strings.Join(groups, l.group)
+}
+
+// Format returns m as shown to users of the locale, given as a BCP 47 tag
+// such as "en-US" or "de-CH", e.g. "$1,234.50" or "CHF 1’234.50". The value
+// is rounded half-even to the minor unit of its currency.
+func Format(m *pb.Money, localeTag string) (string, error) {
+	if !IsValid(m) {
+		return "", ErrInvalidValue
+	}
+	if _, err := MinorUnits(m.GetCurrencyCode()); err == nil {
+		var err error
+		if m, err = RoundToMinorUnits(m, RoundHalfEven); err != nil {
+			return "", err
+		}
+	}
+	l := lookupLocale(localeTag)
+
+	number, negative := strings.CutPrefix(ToDecimalString(m), "-")
+	integer, fraction, _ := strings.Cut(number, ".")
+	number = l.groupDigits(integer)
+	if fraction != "" {
+		number += l.decimal + fraction
+	}
+
+	var b strings.Builder
+	if negative {
+		b.WriteByte('-')
+	}
+	symbol := l.symbol(m.GetCurrencyCode())
+	if l.symbolAfter {
+		b.WriteString(number)
+		b.WriteString(l.spacing)
+		b.WriteString(symbol)
+	} else {
+		b.WriteString(symbol)
+		// Letters are kept apart from the digits, e.g. "CHF 12.50".
+		if r, _ := utf8.DecodeLastRuneInString(symbol); l.spacing != "" || unicode.IsLetter(r) {
+			b.WriteString(nbsp)
+		}
+		b.WriteString(number)
+	}
+	return b.String(), nil
+}
+
+// currencySymbols returns the ways an amount of the currency may be marked,
+// longest first.
+func currencySymbols(currencyCode string) []string {
+	marks := []string{currencyCode}
+	if s, ok := symbols[currencyCode]; ok {
+		marks = append(marks, s)
+	}
+	for _, l := range locales {
+		if s, ok := l.symbols[currencyCode]; ok && !slices.Contains(marks, s) {
+			marks = append(marks, s)
+		}
+	}
+	slices.SortFunc(marks, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), strings.Compare(a, b))
+	})
+	return marks
+}
+
+// Parse reads an amount of the currency written in any of the supported
+// locales, with or without its symbol or code, e.g. "$1,234.50",
+// "1.234,50 €" or "-12.5". A single separator followed by exactly three
+// digits is taken as a group separator, unless the currency has three
+// decimal digits.
+func Parse(s, currencyCode string) (*pb.Money, error) {
+	digits, err := MinorUnits(currencyCode)
+	if err != nil {
+		return &pb.Money{}, err
+	}
+
+	for _, mark := range currencySymbols(currencyCode) {
+		if i := strings.Index(s, mark); i >= 0 {
+			s = s[:i] + s[i+len(mark):]
+			break
+		}
+	}
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '’' || r == '\'' {
+			return -1
+		}
+		if r == '−' {
+			return '-'
+		}
+		return r
+	}, s)
+	s, negative := strings.CutPrefix(s, "-")
+
+	integer, fraction, err := splitDecimal(s, digits)
+	if err != nil {
+		return &pb.Money{}, err
+	}
+	if len(fraction) > 9 {
+		return &pb.Money{}, ErrInvalidAmount
+	}
+	units, err := strconv.ParseInt(integer, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return &pb.Money{}, ErrOverflow
+		}
+		return &pb.Money{}, ErrInvalidAmount
+	}
+	nanos := 0
+	if fraction != "" {
+		nanos, _ = strconv.Atoi(fraction + strings.Repeat("0", 9-len(fraction)))
+	}
+	if negative {
+		units, nanos = -units, -nanos
+	}
+	return &pb.Money{Units: units, Nanos: int32(nanos), CurrencyCode: currencyCode}, nil
+}
+
+// splitDecimal splits a number with '.' and ',' separators into its integer
+// and fraction digits.
+func splitDecimal(s string, minorDigits int) (integer, fraction string, err error) {
+	if s == "" || strings.IndexFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.' && r != ','
+	}) >= 0 {
+		return "", "", ErrInvalidAmount
+	}
+
+	decimal := ""
+	dots, commas := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	switch {
+	case dots >= 0 && commas >= 0:
+		decimal = s[max(dots, commas) : max(dots, commas)+1]
+	case dots >= 0 || commas >= 0:
+		sep := s[max(dots, commas) : max(dots, commas)+1]
+		last := strings.LastIndex(s, sep)
+		if strings.Count(s, sep) == 1 && (len(s)-last-1 != 3 || minorDigits == 3) {
+			decimal = sep
+		}
+	}
+
+	integer = s
+	if decimal != "" {
+		i := strings.LastIndex(s, decimal)
+		integer, fraction = s[:i], s[i+1:]
+		if strings.Contains(integer, decimal) || fraction == "" {
+			return "", "", ErrInvalidAmount
+		}
+	}
+	integer = strings.NewReplacer(".", "", ",", "").Replace(integer)
+	if integer == "" {
+		integer = "0"
+	}
+	return integer, fraction, nil
+}
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package money
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+// supportedCurrencies are the currencies of the currency service.
+var supportedCurrencies = []string{
+	"EUR", "USD", "JPY", "BGN", "CZK", "DKK", "GBP", "HUF", "PLN", "RON", "SEK",
+	"CHF", "ISK", "NOK", "HRK", "RUB", "TRY", "AUD", "BRL", "CAD", "CNY", "HKD",
+	"IDR", "ILS", "INR", "KRW", "MXN", "MYR", "NZD", "PHP", "SGD", "THB", "ZAR",
+}
+
+// sp makes the no-break spaces of formatted values visible in test tables.
+func sp(s string) string {
+	return strings.NewReplacer("_", nbsp, "~", nnbsp).Replace(s)
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in     *pb.Money
+		locale string
+		want   string
+	}{
+		{mmc(1234, 500000000, "USD"), "en-US", "$1,234.50"},
+		{mmc(-1234, -500000000, "USD"), "en-US", "-$1,234.50"},
+		{mmc(0, 5000000, "USD"), "en-US", "$0.00"},
+		{mmc(0, 15000000, "USD"), "en-US", "$0.02"},
+		{mmc(12, 0, "USD"), "en-GB", "US$12.00"},
+		{mmc(12, 0, "GBP"), "en-GB", "£12.00"},
+		{mmc(12, 0, "CHF"), "en-US", sp("CHF_12.00")},
+		{mmc(1234567, 0, "CHF"), "de-CH", sp("CHF_1’234’567.00")},
+		{mmc(1234567, 0, "EUR"), "de-DE", sp("1.234.567,00_€")},
+		{mmc(1234, 560000000, "EUR"), "es-ES", sp("1234,56_€")},
+		{mmc(12345, 670000000, "EUR"), "es-ES", sp("12.345,67_€")},
+		{mmc(1234, 560000000, "EUR"), "fr-FR", sp("1~234,56_€")},
+		{mmc(1234, 560000000, "USD"), "fr", sp("1~234,56_$US")},
+		{mmc(1234567, 0, "INR"), "en-IN", "₹12,34,567.00"},
+		{mmc(1234567, 0, "JPY"), "ja-JP", "￥1,234,567"},
+		{mmc(1234, 500000000, "JPY"), "ja-JP", "￥1,234"},
+		{mmc(1235, 500000000, "JPY"), "en-US", "¥1,236"},
+		{mmc(1234, 0, "KRW"), "ko-KR", "₩1,234"},
+		{mmc(1234, 500000000, "BRL"), "pt-BR", sp("R$_1.234,50")},
+		{mmc(99, 990000000, "PLN"), "pl-PL", sp("99,99_zł")},
+		{mmc(1234, 0, "CZK"), "cs_CZ", sp("1_234,00_Kč")},
+		{mmc(7, 5000000, "BHD"), "en-US", sp("BHD_7.005")},
+		{mmc(12, 0, "XXX"), "en-US", sp("XXX_12")},
+		{mmc(12, 0, "USD"), "xx-YY", "$12.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.want, func(t *testing.T) {
+			got, err := Format(tt.in, tt.locale)
+			if err != nil {
+				t.Fatalf("Format([%v],%q): unexpected err=\"%v\"", tt.in, tt.locale, err)
+			}
+			if got != tt.want {
+				t.Errorf("Format([%v],%q) = %q, want %q", tt.in, tt.locale, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Format(mmc(1, -1, "USD"), "en-US"); err != ErrInvalidValue {
+		t.Errorf("Format(invalid): expected err=\"%v\" got=\"%v\"", ErrInvalidValue, err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency string
+		want     *pb.Money
+		wantErr  error
+	}{
+		{"$1,234.50", "USD", mmc(1234, 500000000, "USD"), nil},
+		{"US$ 1,234.50", "USD", mmc(1234, 500000000, "USD"), nil},
+		{"1234.5 USD", "USD", mmc(1234, 500000000, "USD"), nil},
+		{"-$12.05", "USD", mmc(-12, -50000000, "USD"), nil},
+		{"$-12.05", "USD", mmc(-12, -50000000, "USD"), nil},
+		{"1,234", "USD", mmc(1234, 0, "USD"), nil},
+		{"1,23", "USD", mmc(1, 230000000, "USD"), nil},
+		{"1.234.567", "EUR", mmc(1234567, 0, "EUR"), nil},
+		{sp("1.234,56_€"), "EUR", mmc(1234, 560000000, "EUR"), nil},
+		{sp("1~234,56_€"), "EUR", mmc(1234, 560000000, "EUR"), nil},
+		{"CHF 1’234.50", "CHF", mmc(1234, 500000000, "CHF"), nil},
+		{"￥1,234", "JPY", mmc(1234, 0, "JPY"), nil},
+		{"¥1.234", "JPY", mmc(1234, 0, "JPY"), nil},
+		{"1.234", "BHD", mmc(1, 234000000, "BHD"), nil},
+		{"0.000000001", "USD", mmc(0, 1, "USD"), nil},
+		{".5", "USD", mmc(0, 500000000, "USD"), nil},
+		{"9223372036854775807.99", "USD", mmc(9223372036854775807, 990000000, "USD"), nil},
+		{"9223372036854775808", "USD", mm(0, 0), ErrOverflow},
+		{"0.0000000001", "USD", mm(0, 0), ErrInvalidAmount},
+		{"1.234,5.6", "USD", mm(0, 0), ErrInvalidAmount},
+		{"1.", "USD", mm(0, 0), ErrInvalidAmount},
+		{"12 apples", "USD", mm(0, 0), ErrInvalidAmount},
+		{"€12", "USD", mm(0, 0), ErrInvalidAmount},
+		{"", "USD", mm(0, 0), ErrInvalidAmount},
+		{"12", "XXX", mm(0, 0), ErrUnknownCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in, tt.currency)
+			if err != tt.wantErr {
+				t.Errorf("Parse(%q,%q): expected err=\"%v\" got=\"%v\"", tt.in, tt.currency, tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q,%q) = %v, want %v", tt.in, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatParse_roundTrip(t *testing.T) {
+	values := []struct {
+		units int64
+		nanos int32
+	}{
+		{0, 0}, {0, 10000000}, {1, 0}, {12, 340000000}, {999, 990000000},
+		{1000, 0}, {1234, 560000000}, {12345, 0}, {1234567, 890000000},
+		{-1, -500000000}, {-98765, -430000000}, {9223372036854775807, 0},
+	}
+	for _, tag := range slices.Sorted(maps.Keys(locales)) {
+		for _, code := range supportedCurrencies {
+			for _, v := range values {
+				m := Must(RoundToMinorUnits(mmc(v.units, v.nanos, code), RoundDown))
+				s, err := Format(m, tag)
+				if err != nil {
+					t.Fatalf("Format([%v],%q): unexpected err=\"%v\"", m, tag, err)
+				}
+				got, err := Parse(s, code)
+				if err != nil {
+					t.Errorf("Parse(Format([%v],%q) = %q): unexpected err=\"%v\"", m, tag, s, err)
+				} else if !AreEquals(got, m) {
+					t.Errorf("Parse(Format([%v],%q) = %q) = %v", m, tag, s, got)
+				}
+			}
+		}
+	}
+}
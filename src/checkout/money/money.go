@@ -75,31 +75,34 @@ func Must(v *pb.Money, err error) *pb.Money {
 	return v
 }
 
-// Sum adds two values. Returns an error if one of the values are invalid or
+// Sum adds two values. Returns an error if one of the values are invalid,
 // currency codes are not matching (unless currency code is unspecified for
-// both).
+// both) or the result overflows.
 func Sum(l, r *pb.Money) (*pb.Money, error) {
 	if !IsValid(l) || !IsValid(r) {
 		return &pb.Money{}, ErrInvalidValue
 	} else if l.GetCurrencyCode() != r.GetCurrencyCode() {
 		return &pb.Money{}, ErrMismatchingCurrency
 	}
-	units := l.GetUnits() + r.GetUnits()
+	units, ok := addUnits(l.GetUnits(), r.GetUnits())
+	if !ok {
+		return &pb.Money{}, ErrOverflow
+	}
 	nanos := l.GetNanos() + r.GetNanos()
 
-	if (units == 0 && nanos == 0) || (units > 0 && nanos >= 0) || (units < 0 && nanos <= 0) {
-		// same sign <units, nanos>
-		units += int64(nanos / nanosMod)
-		nanos = nanos % nanosMod
-	} else {
-		// different sign. nanos guaranteed to not to go over the limit
-		if units > 0 {
-			units--
-			nanos += nanosMod
-		} else {
-			units++
-			nanos -= nanosMod
-		}
+	// Carry whole units out of the nanos, then give both parts the sign of
+	// the total. When units is zero, nanos alone carries the sign.
+	if units, ok = addUnits(units, int64(nanos/nanosMod)); !ok {
+		return &pb.Money{}, ErrOverflow
+	}
+	nanos = nanos % nanosMod
+	if units > 0 && nanos < 0 {
+		// no overflow: units only moves towards zero
+		units--
+		nanos += nanosMod
+	} else if units < 0 && nanos > 0 {
+		units++
+		nanos -= nanosMod
 	}
 
 	return &pb.Money{
@@ -108,13 +111,8 @@ func Sum(l, r *pb.Money) (*pb.Money, error) {
 		CurrencyCode: l.GetCurrencyCode()}, nil
 }
 
-// MultiplySlow is a slow multiplication operation done through adding the value
-// to itself n-1 times.
-func MultiplySlow(m *pb.Money, n uint32) *pb.Money {
-	out := m
-	for n > 1 {
-		out = Must(Sum(out, m))
-		n--
-	}
-	return out
+// addUnits adds a and b, and reports whether the sum did not overflow.
+func addUnits(a, b int64) (int64, bool) {
+	sum := a + b
+	return sum, (sum > a) == (b > 0)
 }
@@ -218,6 +218,14 @@ func TestSum(t *testing.T) {
 		{"mixed (larger negative, with borrow)", args{mm(-11, -100000000), mm(2, 9000000 /*.09*/)}, mm(-9, -91000000 /*.091*/), nil},
 		{"0+negative", args{mm(0, 0), mm(-2, -100000000)}, mm(-2, -100000000), nil},
 		{"negative+0", args{mm(-2, -100000000), mm(0, 0)}, mm(-2, -100000000), nil},
+		{"sub-unit (carry to one)", args{mm(0, 990000000), mm(0, 10000000)}, mm(1, 0), nil},
+		{"sub-unit (positive+positive)", args{mm(0, 500000000), mm(0, 400000000)}, mm(0, 900000000), nil},
+		{"sub-unit (negative result)", args{mm(0, 500000000), mm(0, -990000000)}, mm(0, -490000000), nil},
+		{"sub-unit (negative+positive)", args{mm(0, -100000000), mm(0, 50000000)}, mm(0, -50000000), nil},
+		{"sub-unit (negative carry)", args{mm(0, -600000000), mm(0, -700000000)}, mm(-1, -300000000), nil},
+		{"units to sub-unit (positive)", args{mm(1, 0), mm(0, -10000000)}, mm(0, 990000000), nil},
+		{"units to sub-unit (negative)", args{mm(-1, 0), mm(0, 10000000)}, mm(0, -990000000), nil},
+		{"mixed (sign flips)", args{mm(1, 100000000), mm(-2, -200000000)}, mm(-1, -100000000), nil},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,343 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const logName = "outbox.log"
+
+// ErrLogInUse is returned by OpenFileStore when another process, e.g. the
+// running service, holds the log.
+var ErrLogInUse = errors.New("outbox log is in use by another process")
+
+// Operations recorded in the log.
+const (
+	opAppend    = "append"
+	opDelivered = "delivered"
+	opFailed    = "failed"
+	opRequeued  = "requeued"
+)
+
+// record is a line of the log.
+type record struct {
+	Op    string  `json:"op"`
+	Event *Event  `json:"event,omitempty"`
+	IDs   []int64 `json:"ids,omitempty"`
+}
+
+// fileEvent is an event and its delivery state.
+type fileEvent struct {
+	Event
+	delivered bool
+}
+
+// FileStore is a Store kept in an append-only log file, for deployments
+// without a database. Every change is synced to disk before it is
+// acknowledged. The log is rewritten without pruned events, so it does not
+// grow without bound. The log is locked, so only one process can use it at a
+// time.
+type FileStore struct {
+	// delivering is held by Deliver while it publishes, without mu, so
+	// events can be appended meanwhile.
+	delivering sync.Mutex
+
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	events []fileEvent
+	nextID int64
+}
+
+// OpenFileStore opens the log in dir, creating it if needed. A partially
+// written last record, e.g. after a crash, is discarded.
+func OpenFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+	s := &FileStore{path: filepath.Join(dir, logName), nextID: 1}
+
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox log: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock outbox log: %w", err)
+	}
+	valid, err := s.load(f)
+	if err == nil {
+		err = f.Truncate(valid)
+	}
+	if err == nil {
+		_, err = f.Seek(valid, io.SeekStart)
+	}
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to load outbox log: %w", err)
+	}
+	s.file = f
+	return s, nil
+}
+
+// load replays the log and returns the length of its valid prefix.
+func (s *FileStore) load(f *os.File) (int64, error) {
+	r := bufio.NewReader(f)
+	var valid int64
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// A line without its newline was not fully written.
+			return valid, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return valid, nil
+		}
+		s.apply(rec)
+		valid += int64(len(line))
+	}
+}
+
+// apply updates the in-memory state with rec.
+func (s *FileStore) apply(rec record) {
+	switch rec.Op {
+	case opAppend:
+		s.events = append(s.events, fileEvent{Event: *rec.Event})
+		s.nextID = max(s.nextID, rec.Event.ID+1)
+	case opDelivered, opFailed, opRequeued:
+		for _, id := range rec.IDs {
+			i, ok := slices.BinarySearchFunc(s.events, id, func(e fileEvent, id int64) int {
+				return cmp.Compare(e.ID, id)
+			})
+			if !ok {
+				continue
+			}
+			switch rec.Op {
+			case opDelivered:
+				s.events[i].delivered = true
+			case opFailed:
+				s.events[i].Attempts++
+			case opRequeued:
+				s.events[i].delivered = false
+			}
+		}
+	}
+}
+
+// write appends records to the log, syncs it and applies them.
+func (s *FileStore) write(recs ...record) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rec := range recs {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	if _, err := s.file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	for _, rec := range recs {
+		s.apply(rec)
+	}
+	return nil
+}
+
+func (s *FileStore) Append(_ context.Context, e *Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := *e
+	stored.ID = s.nextID
+	stored.CreatedAt = time.Now().UTC()
+	if err := s.write(record{Op: opAppend, Event: &stored}); err != nil {
+		return fmt.Errorf("failed to append outbox event: %w", err)
+	}
+	e.ID, e.CreatedAt = stored.ID, stored.CreatedAt
+	return nil
+}
+
+func (s *FileStore) Deliver(ctx context.Context, limit int, publish PublishFunc) (int, error) {
+	s.delivering.Lock()
+	defer s.delivering.Unlock()
+
+	events := s.pending(limit)
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	// Publishing waits for the broker, so it must not block Append. The
+	// events cannot change meanwhile: Requeue and Prune only touch
+	// delivered events.
+	published, publishErr := publish(ctx, events)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var recs []record
+	if published > 0 {
+		ids := make([]int64, published)
+		for i, e := range events[:published] {
+			ids[i] = e.ID
+		}
+		recs = append(recs, record{Op: opDelivered, IDs: ids})
+	}
+	if publishErr != nil && published < len(events) {
+		recs = append(recs, record{Op: opFailed, IDs: []int64{events[published].ID}})
+	}
+	if len(recs) > 0 {
+		if err := s.write(recs...); err != nil {
+			return 0, fmt.Errorf("failed to record outbox delivery: %w", err)
+		}
+	}
+	return published, publishErr
+}
+
+// pending returns up to limit pending events, oldest first.
+func (s *FileStore) pending(limit int) []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []Event
+	for _, e := range s.events {
+		if len(events) == limit {
+			break
+		}
+		if !e.delivered {
+			events = append(events, e.Event)
+		}
+	}
+	return events
+}
+
+func (s *FileStore) Requeue(_ context.Context, f Filter) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids []int64
+	for _, e := range s.events {
+		if e.delivered && !e.CreatedAt.Before(f.Since) && (f.OrderID == "" || e.OrderID == f.OrderID) {
+			ids = append(ids, e.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := s.write(record{Op: opRequeued, IDs: ids}); err != nil {
+		return 0, fmt.Errorf("failed to requeue outbox events: %w", err)
+	}
+	return len(ids), nil
+}
+
+// Prune forgets delivered events created before t, and rewrites the log with
+// the remaining events.
+func (s *FileStore) Prune(_ context.Context, t time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := make([]fileEvent, 0, len(s.events))
+	for _, e := range s.events {
+		if !e.delivered || !e.CreatedAt.Before(t) {
+			kept = append(kept, e)
+		}
+	}
+	pruned := len(s.events) - len(kept)
+	if pruned == 0 {
+		return 0, nil
+	}
+	if err := s.rewrite(kept); err != nil {
+		return 0, fmt.Errorf("failed to compact outbox log: %w", err)
+	}
+	return pruned, nil
+}
+
+// rewrite replaces the log with one holding only events, and switches to it.
+func (s *FileStore) rewrite(events []fileEvent) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), logName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	var delivered []int64
+	for _, e := range events {
+		if err := enc.Encode(record{Op: opAppend, Event: &e.Event}); err != nil {
+			tmp.Close()
+			return err
+		}
+		if e.delivered {
+			delivered = append(delivered, e.ID)
+		}
+	}
+	if len(delivered) > 0 {
+		if err := enc.Encode(record{Op: opDelivered, IDs: delivered}); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := lockFile(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	s.file.Close()
+	s.file = tmp
+	s.events = events
+	return nil
+}
+
+func (s *FileStore) Lag(_ context.Context) (Lag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var lag Lag
+	for _, e := range s.events {
+		if e.delivered {
+			continue
+		}
+		if lag.Pending == 0 {
+			lag.Oldest = e.CreatedAt
+		}
+		lag.Pending++
+	}
+	return lag, nil
+}
+
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package outbox
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// publishAll returns a PublishFunc recording the order ids it publishes. It
+// fails on the event of order failOn.
+func publishAll(got *[]string, failOn string) PublishFunc {
+	return func(_ context.Context, events []Event) (int, error) {
+		for i, e := range events {
+			if e.OrderID == failOn {
+				return i, errors.New("broker unavailable")
+			}
+			*got = append(*got, e.OrderID)
+		}
+		return len(events), nil
+	}
+}
+
+func appendOrders(t *testing.T, s Store, orders ...string) {
+	t.Helper()
+	for _, o := range orders {
+		if err := s.Append(context.Background(), &Event{OrderID: o, Payload: []byte(o)}); err != nil {
+			t.Fatalf("Append(%s) err = %v", o, err)
+		}
+	}
+}
+
+func TestFileStoreDeliver(t *testing.T) {
+	ctx := context.Background()
+	s, err := OpenFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	appendOrders(t, s, "a", "b", "c")
+
+	var got []string
+	n, err := s.Deliver(ctx, 10, publishAll(&got, "b"))
+	if n != 1 || err == nil {
+		t.Fatalf("Deliver() = (%d, %v), want 1 and an error", n, err)
+	}
+	lag, _ := s.Lag(ctx)
+	if lag.Pending != 2 || lag.Oldest.IsZero() {
+		t.Errorf("Lag() = %+v, want 2 pending", lag)
+	}
+
+	n, err = s.Deliver(ctx, 10, publishAll(&got, ""))
+	if n != 2 || err != nil {
+		t.Fatalf("Deliver() = (%d, %v), want (2, nil)", n, err)
+	}
+	if want := []string{"a", "b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("published %v, want %v", got, want)
+	}
+	if lag, _ := s.Lag(ctx); lag.Pending != 0 {
+		t.Errorf("Lag() = %+v, want none pending", lag)
+	}
+}
+
+func TestFileStoreDeliver_appendWhilePublishing(t *testing.T) {
+	ctx := context.Background()
+	s, err := OpenFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	appendOrders(t, s, "a")
+
+	publishing, release := make(chan struct{}), make(chan struct{})
+	done := make(chan int)
+	go func() {
+		n, _ := s.Deliver(ctx, 10, func(_ context.Context, events []Event) (int, error) {
+			close(publishing)
+			<-release
+			return len(events), nil
+		})
+		done <- n
+	}()
+
+	// The broker is slow, but orders are still recorded.
+	<-publishing
+	appendOrders(t, s, "b")
+	close(release)
+	if n := <-done; n != 1 {
+		t.Fatalf("Deliver() = %d, want 1", n)
+	}
+	if lag, _ := s.Lag(ctx); lag.Pending != 1 {
+		t.Errorf("Lag() = %+v, want the event appended while publishing pending", lag)
+	}
+}
+
+func TestFileStoreReopen(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	appendOrders(t, s, "a", "b")
+	var got []string
+	s.Deliver(ctx, 1, publishAll(&got, ""))
+	s.Deliver(ctx, 1, publishAll(&got, "b"))
+	s.Close()
+
+	// Simulate a crash in the middle of a write.
+	f, _ := os.OpenFile(filepath.Join(dir, logName), os.O_APPEND|os.O_WRONLY, 0)
+	f.WriteString(`{"op":"append","event":{"id":3,`)
+	f.Close()
+
+	s, err = OpenFileStore(dir)
+	if err != nil {
+		t.Fatalf("OpenFileStore() after crash err = %v", err)
+	}
+	defer s.Close()
+
+	got = nil
+	var attempts int
+	s.Deliver(ctx, 10, func(_ context.Context, events []Event) (int, error) {
+		for _, e := range events {
+			got = append(got, e.OrderID)
+			attempts = e.Attempts
+		}
+		return len(events), nil
+	})
+	if !slices.Equal(got, []string{"b"}) || attempts != 1 {
+		t.Errorf("pending after reopen = %v with %d attempts, want [b] with 1 attempt", got, attempts)
+	}
+
+	appendOrders(t, s, "c")
+	got = nil
+	s.Deliver(ctx, 10, publishAll(&got, ""))
+	if !slices.Equal(got, []string{"c"}) {
+		t.Errorf("published %v after reopen, want [c]", got)
+	}
+}
+
+func TestFileStoreLocked(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if _, err := OpenFileStore(dir); !errors.Is(err, ErrLogInUse) {
+		t.Errorf("second OpenFileStore() err = %v, want %v", err, ErrLogInUse)
+	}
+}
+
+func TestFileStoreRequeueAndPrune(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := OpenFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	appendOrders(t, s, "a", "b", "c")
+	var got []string
+	s.Deliver(ctx, 10, publishAll(&got, ""))
+
+	n, err := s.Requeue(ctx, Filter{OrderID: "b"})
+	if n != 1 || err != nil {
+		t.Fatalf("Requeue(b) = (%d, %v), want (1, nil)", n, err)
+	}
+	got = nil
+	s.Deliver(ctx, 10, publishAll(&got, ""))
+	if !slices.Equal(got, []string{"b"}) {
+		t.Errorf("published %v after requeue, want [b]", got)
+	}
+
+	n, err = s.Prune(ctx, time.Now().Add(time.Minute))
+	if n != 3 || err != nil {
+		t.Fatalf("Prune() = (%d, %v), want (3, nil)", n, err)
+	}
+	if n, _ := s.Requeue(ctx, Filter{}); n != 0 {
+		t.Errorf("Requeue() after prune = %d, want 0", n)
+	}
+
+	// The compacted log is still appended to, and survives a reopen.
+	appendOrders(t, s, "d")
+	s.Close()
+	s, err = OpenFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	s.Deliver(ctx, 10, publishAll(&got, ""))
+	if !slices.Equal(got, []string{"d"}) {
+		t.Errorf("published %v after compaction, want [d]", got)
+	}
+}
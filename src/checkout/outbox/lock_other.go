@@ -0,0 +1,11 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !unix
+
+package outbox
+
+import "os"
+
+// lockFile does nothing where flock is not available.
+func lockFile(*os.File) error { return nil }
@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package outbox implements a transactional outbox for checkout's order
+// events: events are recorded durably in the order path, and a Relay
+// publishes them to Kafka afterwards, retrying until the broker acknowledges
+// them. Delivery is at least once.
+package outbox
+
+import (
+	"context"
+	"time"
+)
+
+// Event is an order event awaiting publication.
+type Event struct {
+	// ID orders events. It is assigned by Store.Append.
+	ID int64 `json:"id"`
+
+	OrderID string `json:"order_id"`
+	Payload []byte `json:"payload"`
+
+	// Key is the key of the Kafka message, which picks its partition. The
+	// message is unkeyed if it is empty.
+	Key string `json:"key,omitempty"`
+
+	// Headers are added to the Kafka message, e.g. the trace context of
+	// the order.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Attempts counts failed publications.
+	Attempts int `json:"attempts,omitempty"`
+}
+
+// PublishFunc publishes events in order. It returns how many events were
+// published before it failed, if it did.
+type PublishFunc func(ctx context.Context, events []Event) (int, error)
+
+// Filter selects delivered events to publish again.
+type Filter struct {
+	// Since selects events created at or after it.
+	Since time.Time
+
+	// OrderID, if set, only selects the events of that order.
+	OrderID string
+}
+
+// Lag describes the events not yet published.
+type Lag struct {
+	Pending int
+
+	// Oldest is the creation time of the oldest pending event, or zero if
+	// there are none.
+	Oldest time.Time
+}
+
+// Store is a durable log of events.
+type Store interface {
+	// Append records e as pending and sets its ID and CreatedAt.
+	Append(ctx context.Context, e *Event) error
+
+	// Deliver passes up to limit pending events, oldest first, to publish,
+	// and marks the ones it published as delivered. If publish fails, the
+	// failed event's attempt count is incremented. Deliver returns the
+	// number of events delivered.
+	Deliver(ctx context.Context, limit int, publish PublishFunc) (int, error)
+
+	// Requeue marks the delivered events matching f as pending again, and
+	// returns how many there were.
+	Requeue(ctx context.Context, f Filter) (int, error)
+
+	// Prune forgets delivered events created before t.
+	Prune(ctx context.Context, t time.Time) (int, error)
+
+	Lag(ctx context.Context) (Lag, error)
+
+	Close() error
+}
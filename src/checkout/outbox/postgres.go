@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// PostgresStore is a Store backed by the checkout_outbox table. Relays of
+// several checkout replicas can share it: each pending event is handed to
+// one relay at a time.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Append(ctx context.Context, e *Event) error {
+	headers, err := json.Marshal(e.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event headers: %w", err)
+	}
+
+	err = s.db.QueryRowContext(ctx,
+		`INSERT INTO checkout_outbox (order_id, message_key, payload, headers)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, created_at`,
+		e.OrderID, e.Key, e.Payload, headers,
+	).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to append outbox event: %w", err)
+	}
+	return nil
+}
+
+func (s *PostgresStore) Deliver(ctx context.Context, limit int, publish PublishFunc) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin outbox transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The rows stay locked until the transaction ends, so concurrent relays
+	// skip them rather than publishing them twice.
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id, order_id, message_key, payload, headers, created_at, attempts
+		 FROM checkout_outbox
+		 WHERE delivered_at IS NULL
+		 ORDER BY id
+		 LIMIT $1
+		 FOR UPDATE SKIP LOCKED`,
+		limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query pending outbox events: %w", err)
+	}
+	var events []Event
+	for rows.Next() {
+		var e Event
+		var headers []byte
+		if err := rows.Scan(&e.ID, &e.OrderID, &e.Key, &e.Payload, &headers, &e.CreatedAt, &e.Attempts); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		if err := json.Unmarshal(headers, &e.Headers); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to unmarshal headers of outbox event %d: %w", e.ID, err)
+		}
+		events = append(events, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read pending outbox events: %w", err)
+	}
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	published, publishErr := publish(ctx, events)
+
+	if published > 0 {
+		ids := make([]int64, published)
+		for i, e := range events[:published] {
+			ids[i] = e.ID
+		}
+		_, err := tx.ExecContext(ctx,
+			`UPDATE checkout_outbox SET delivered_at = NOW() WHERE id = ANY($1)`,
+			pq.Array(ids),
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to mark outbox events delivered: %w", err)
+		}
+	}
+	if publishErr != nil && published < len(events) {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE checkout_outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1`,
+			events[published].ID, publishErr.Error(),
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to record outbox publish failure: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit outbox delivery: %w", err)
+	}
+	return published, publishErr
+}
+
+func (s *PostgresStore) Requeue(ctx context.Context, f Filter) (int, error) {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE checkout_outbox SET delivered_at = NULL
+		 WHERE delivered_at IS NOT NULL
+		   AND created_at >= $1
+		   AND ($2 = '' OR order_id = $2)`,
+		f.Since, f.OrderID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue outbox events: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (s *PostgresStore) Prune(ctx context.Context, t time.Time) (int, error) {
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM checkout_outbox WHERE delivered_at IS NOT NULL AND created_at < $1`, t,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune outbox events: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (s *PostgresStore) Lag(ctx context.Context) (Lag, error) {
+	var lag Lag
+	var oldest sql.NullTime
+	err := s.db.QueryRowContext(ctx,
+		`SELECT count(*), min(created_at) FROM checkout_outbox WHERE delivered_at IS NULL`,
+	).Scan(&lag.Pending, &oldest)
+	if err != nil {
+		return lag, fmt.Errorf("failed to query outbox lag: %w", err)
+	}
+	lag.Oldest = oldest.Time
+	return lag, nil
+}
+
+// Close does nothing: the database is shared with the rest of checkout.
+func (s *PostgresStore) Close() error { return nil }
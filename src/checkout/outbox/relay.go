@@ -0,0 +1,235 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Relay defaults.
+const (
+	DefaultBatchSize = 100
+	DefaultInterval  = time.Second
+	DefaultRetention = 24 * time.Hour
+)
+
+// Relay publishes the pending events of a Store to a Kafka topic.
+type Relay struct {
+	store    Store
+	producer sarama.SyncProducer
+	topic    string
+	logger   *slog.Logger
+
+	// BatchSize is the maximum number of events handed out by one
+	// Store.Deliver call.
+	BatchSize int
+
+	// Interval is how long the relay waits before polling the store again
+	// once it is drained, or after a failure.
+	Interval time.Duration
+
+	// Retention is how long delivered events are kept for replays.
+	Retention time.Duration
+
+	wake      chan struct{}
+	published metric.Int64Counter
+	failures  metric.Int64Counter
+}
+
+// NewRelay creates a Relay. producer should wait for acknowledgements and
+// retry, as an event is marked delivered as soon as SendMessage returns.
+func NewRelay(store Store, producer sarama.SyncProducer, topic string, logger *slog.Logger) (*Relay, error) {
+	r := &Relay{
+		store:     store,
+		producer:  producer,
+		topic:     topic,
+		logger:    logger,
+		BatchSize: DefaultBatchSize,
+		Interval:  DefaultInterval,
+		Retention: DefaultRetention,
+		wake:      make(chan struct{}, 1),
+	}
+
+	meter := otel.Meter("checkout")
+	var err error
+	r.published, err = meter.Int64Counter(
+		"app.checkout.outbox.published",
+		metric.WithDescription("Number of outbox events published to Kafka."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create outbox published counter: %w", err)
+	}
+	r.failures, err = meter.Int64Counter(
+		"app.checkout.outbox.publish_failures",
+		metric.WithDescription("Number of failed attempts to publish an outbox event to Kafka."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create outbox failure counter: %w", err)
+	}
+	_, err = meter.Int64ObservableGauge(
+		"app.checkout.outbox.pending",
+		metric.WithDescription("Number of outbox events not yet published to Kafka."),
+		metric.WithInt64Callback(func(ctx context.Context, o metric.Int64Observer) error {
+			lag, err := store.Lag(ctx)
+			if err != nil {
+				return err
+			}
+			o.Observe(int64(lag.Pending))
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create outbox pending gauge: %w", err)
+	}
+	_, err = meter.Float64ObservableGauge(
+		"app.checkout.outbox.lag",
+		metric.WithDescription("Age of the oldest outbox event not yet published to Kafka."),
+		metric.WithUnit("s"),
+		metric.WithFloat64Callback(func(ctx context.Context, o metric.Float64Observer) error {
+			lag, err := store.Lag(ctx)
+			if err != nil {
+				return err
+			}
+			var age float64
+			if !lag.Oldest.IsZero() {
+				age = time.Since(lag.Oldest).Seconds()
+			}
+			o.Observe(age)
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create outbox lag gauge: %w", err)
+	}
+	return r, nil
+}
+
+// Notify wakes the relay up after an event was appended, rather than
+// waiting for the next poll.
+func (r *Relay) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run publishes pending events until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	lastPrune := time.Time{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-r.wake:
+		}
+
+		if _, err := r.Drain(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Warn(fmt.Sprintf("outbox relay: %v", err))
+		}
+
+		if time.Since(lastPrune) > time.Hour {
+			lastPrune = time.Now()
+			if n, err := r.store.Prune(ctx, time.Now().Add(-r.Retention)); err != nil {
+				r.logger.Warn(fmt.Sprintf("failed to prune outbox: %v", err))
+			} else if n > 0 {
+				r.logger.Info(fmt.Sprintf("pruned %d delivered outbox events", n))
+			}
+		}
+
+		timer.Reset(r.Interval)
+	}
+}
+
+// Drain publishes pending events until there are none left or publishing
+// fails, and returns how many were published.
+func (r *Relay) Drain(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := r.store.Deliver(ctx, r.BatchSize, r.publish)
+		total += n
+		if err != nil || n < r.BatchSize {
+			return total, err
+		}
+	}
+}
+
+// publish sends events one at a time, so a failure leaves the rest pending
+// in order.
+func (r *Relay) publish(ctx context.Context, events []Event) (int, error) {
+	tracer := otel.Tracer("checkout")
+	propagator := otel.GetTextMapPropagator()
+
+	for i, e := range events {
+		// Continue the trace of the order that produced the event.
+		orderCtx := propagator.Extract(ctx, propagation.MapCarrier(e.Headers))
+		spanCtx, span := tracer.Start(
+			orderCtx,
+			fmt.Sprintf("%s publish", r.topic),
+			trace.WithSpanKind(trace.SpanKindProducer),
+			trace.WithAttributes(
+				semconv.PeerService("kafka"),
+				semconv.NetworkTransportTCP,
+				semconv.MessagingSystemKafka,
+				semconv.MessagingDestinationName(r.topic),
+				semconv.MessagingOperationPublish,
+				attribute.String("app.order.id", e.OrderID),
+				attribute.Int64("app.outbox.event.id", e.ID),
+				attribute.Int("app.outbox.event.attempts", e.Attempts),
+				attribute.Float64("app.outbox.event.age", time.Since(e.CreatedAt).Seconds()),
+			),
+		)
+
+		msg := &sarama.ProducerMessage{
+			Topic: r.topic,
+			Value: sarama.ByteEncoder(e.Payload),
+		}
+		if e.Key != "" {
+			msg.Key = sarama.StringEncoder(e.Key)
+			span.SetAttributes(semconv.MessagingKafkaMessageKey(e.Key))
+		}
+		// The trace context of the order is replaced with that of the span.
+		carrier := propagation.MapCarrier{}
+		for key, value := range e.Headers {
+			carrier[key] = value
+		}
+		for _, field := range propagator.Fields() {
+			delete(carrier, field)
+		}
+		propagator.Inject(spanCtx, carrier)
+		for key, value := range carrier {
+			msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+		}
+
+		partition, offset, err := r.producer.SendMessage(msg)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			span.End()
+			r.failures.Add(ctx, 1)
+			return i, fmt.Errorf("failed to publish outbox event %d of order %s: %w", e.ID, e.OrderID, err)
+		}
+		span.SetAttributes(
+			semconv.MessagingKafkaDestinationPartition(int(partition)),
+			semconv.MessagingKafkaMessageOffset(int(offset)),
+		)
+		span.End()
+		r.published.Add(ctx, 1)
+	}
+	return len(events), nil
+}
@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestRelayDrain(t *testing.T) {
+	ctx := context.Background()
+	s, err := OpenFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	appendOrders(t, s, "a", "b", "c")
+
+	producer := mocks.NewSyncProducer(t, mocks.NewTestConfig())
+	defer producer.Close()
+	relay, err := NewRelay(s, producer, "orders", slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	relay.BatchSize = 2
+
+	producer.ExpectSendMessageAndSucceed()
+	producer.ExpectSendMessageAndFail(sarama.ErrNotEnoughReplicas)
+	n, err := relay.Drain(ctx)
+	if n != 1 || !errors.Is(err, sarama.ErrNotEnoughReplicas) {
+		t.Fatalf("Drain() = (%d, %v), want 1 and %v", n, err, sarama.ErrNotEnoughReplicas)
+	}
+	if lag, _ := s.Lag(ctx); lag.Pending != 2 {
+		t.Errorf("Lag() after failure = %+v, want 2 pending", lag)
+	}
+
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(val []byte) error {
+		if string(val) != "b" {
+			return errors.New("expected the failed event to be retried first, got " + string(val))
+		}
+		return nil
+	})
+	producer.ExpectSendMessageAndSucceed()
+	n, err = relay.Drain(ctx)
+	if n != 2 || err != nil {
+		t.Fatalf("Drain() = (%d, %v), want (2, nil)", n, err)
+	}
+	if lag, _ := s.Lag(ctx); lag.Pending != 0 {
+		t.Errorf("Lag() after retry = %+v, want none pending", lag)
+	}
+}
+
+func TestRelayDrain_key(t *testing.T) {
+	ctx := context.Background()
+	s, err := OpenFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	for _, key := range []string{"user-1", ""} {
+		if err := s.Append(ctx, &Event{OrderID: "o", Key: key, Payload: []byte("o")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	producer := mocks.NewSyncProducer(t, mocks.NewTestConfig())
+	defer producer.Close()
+	relay, err := NewRelay(s, producer, "orders", slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		if msg.Key == nil {
+			return errors.New("expected a key")
+		}
+		if key, _ := msg.Key.Encode(); string(key) != "user-1" {
+			return errors.New("expected key user-1, got " + string(key))
+		}
+		return nil
+	})
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		if msg.Key != nil {
+			return errors.New("expected no key")
+		}
+		return nil
+	})
+	if n, err := relay.Drain(ctx); n != 2 || err != nil {
+		t.Fatalf("Drain() = (%d, %v), want (2, nil)", n, err)
+	}
+}
+
+func TestRelayDrain_headers(t *testing.T) {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	ctx := context.Background()
+	s, err := OpenFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	orderTrace := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	headers := map[string]string{"ce_type": "oteldemo.checkout.order.placed", "traceparent": orderTrace}
+	if err := s.Append(ctx, &Event{OrderID: "o", Payload: []byte("o"), Headers: headers}); err != nil {
+		t.Fatal(err)
+	}
+
+	producer := mocks.NewSyncProducer(t, mocks.NewTestConfig())
+	defer producer.Close()
+	relay, err := NewRelay(s, producer, "orders", slog.Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		got := map[string]string{}
+		for _, h := range msg.Headers {
+			got[string(h.Key)] = string(h.Value)
+		}
+		if got["ce_type"] != headers["ce_type"] {
+			return fmt.Errorf("headers = %v, want the event's ce_type", got)
+		}
+		// The publish span is a child of the order's.
+		if tp := got["traceparent"]; tp == orderTrace || !strings.HasPrefix(tp, orderTrace[:36]) {
+			return fmt.Errorf("traceparent = %q, want a child of %q", tp, orderTrace)
+		}
+		return nil
+	})
+	if n, err := relay.Drain(ctx); n != 1 || err != nil {
+		t.Fatalf("Drain() = (%d, %v), want (1, nil)", n, err)
+	}
+}
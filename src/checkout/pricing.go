@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/open-feature/go-sdk/openfeature"
+	"go.opentelemetry.io/otel/attribute"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/money"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/pricing"
+)
+
+// pricingRulesFlag is an object flag holding pricing rules. When it is set,
+// its rules replace the ones from CHECKOUT_PRICING_RULES, so that promotions
+// and tax can be switched on from flagd.
+const pricingRulesFlag = "pricingRules"
+
+// loadPricingRules loads the rules from the file at CHECKOUT_PRICING_RULES.
+// Orders are priced without promotions, coupons or tax when it is not set or
+// cannot be loaded.
+func loadPricingRules() *pricing.Rules {
+	path := os.Getenv("CHECKOUT_PRICING_RULES")
+	if path == "" {
+		return &pricing.Rules{}
+	}
+	rules, err := pricing.LoadRules(path)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("failed to load pricing rules, pricing without them: %v", err))
+		return &pricing.Rules{}
+	}
+	logger.Info(fmt.Sprintf("loaded pricing rules from %s", path))
+	return rules
+}
+
+// currentPricingRules returns the rules of the pricingRules flag if it is
+// set, and the rules loaded at startup otherwise.
+func (cs *checkout) currentPricingRules(ctx context.Context) *pricing.Rules {
+	client := openfeature.NewClient("checkout")
+
+	value, _ := client.ObjectValue(ctx, pricingRulesFlag, nil, openfeature.EvaluationContext{})
+	if v, ok := value.(map[string]any); !ok || len(v) == 0 {
+		return cs.pricingRules
+	}
+
+	data, err := json.Marshal(value)
+	if err == nil {
+		var rules *pricing.Rules
+		if rules, err = pricing.ParseRules(data); err == nil {
+			return rules
+		}
+	}
+	logger.Warn(fmt.Sprintf("invalid %s flag, using the default pricing rules: %v", pricingRulesFlag, err))
+	return cs.pricingRules
+}
+
+// pricingAttributes returns the span attributes of the pricing of an order.
+func pricingAttributes(p *pb.OrderPricing) []attribute.KeyValue {
+	// A promotion has an adjustment per item it applies to.
+	var coupons, promotions []string
+	for _, a := range p.GetAdjustments() {
+		switch a.GetKind() {
+		case pricing.KindCoupon:
+			coupons = append(coupons, a.GetId())
+		case pricing.KindPromotion:
+			if !slices.Contains(promotions, a.GetId()) {
+				promotions = append(promotions, a.GetId())
+			}
+		}
+	}
+	return []attribute.KeyValue{
+		attribute.Float64("app.order.subtotal", money.ToFloat64(p.GetSubtotal())),
+		attribute.Float64("app.order.discount", money.ToFloat64(p.GetDiscount())),
+		attribute.Float64("app.order.tax", money.ToFloat64(p.GetTax())),
+		attribute.StringSlice("app.order.coupon_codes", coupons),
+		attribute.StringSlice("app.order.promotions", promotions),
+	}
+}
@@ -0,0 +1,379 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pricing prices orders: an Engine passes an order through stages
+// that apply promotions, coupons and tax to it, and breaks its total down.
+// Orders are priced in the currency of their prices, usually USD.
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/money"
+)
+
+var (
+	// ErrInvalidCoupon is returned for coupon codes that do not exist or do
+	// not apply to the order.
+	ErrInvalidCoupon = errors.New("invalid coupon")
+
+	ErrEmptyOrder = errors.New("order has no items and no shipping cost")
+)
+
+// Item is a line of an order.
+type Item struct {
+	ProductID  string
+	Categories []string
+	UnitPrice  *pb.Money
+	Quantity   int32
+}
+
+// Order is an order to price.
+type Order struct {
+	Items       []Item
+	Shipping    *pb.Money
+	Address     *pb.Address
+	CouponCodes []string
+}
+
+// Kinds of adjustments.
+const (
+	KindPromotion = "promotion"
+	KindCoupon    = "coupon"
+	KindTax       = "tax"
+)
+
+// Adjustment is a discount or tax applied to an order.
+type Adjustment struct {
+	// Kind is KindPromotion, KindCoupon or KindTax.
+	Kind string
+
+	// ID is the promotion id, coupon code or tax region.
+	ID          string
+	Description string
+
+	// ProductID is set for adjustments of a single item.
+	ProductID string
+
+	// Amount is negative for discounts and positive for tax.
+	Amount *pb.Money
+}
+
+// Breakdown is how an order was priced.
+type Breakdown struct {
+	// Subtotal is the sum of the unit prices times the quantities.
+	Subtotal *pb.Money
+
+	// Discount is the sum of the item and order discounts, as a positive
+	// amount. Shipping discounts are deducted from Shipping instead.
+	Discount *pb.Money
+
+	Shipping *pb.Money
+	Tax      *pb.Money
+
+	// Total is Subtotal - Discount + Shipping + Tax.
+	Total *pb.Money
+
+	Adjustments []Adjustment
+}
+
+// Proto returns b as an OrderPricing, with its amounts passed through
+// convert, e.g. to the user currency. The total is computed from the
+// converted amounts rather than converted itself, so that it still adds up
+// after they are rounded.
+func (b *Breakdown) Proto(convert func(*pb.Money) (*pb.Money, error)) (*pb.OrderPricing, error) {
+	var err error
+	conv := func(m *pb.Money) *pb.Money {
+		if err != nil {
+			return nil
+		}
+		var converted *pb.Money
+		converted, err = convert(m)
+		return converted
+	}
+
+	p := &pb.OrderPricing{
+		Subtotal: conv(b.Subtotal),
+		Discount: conv(b.Discount),
+		Shipping: conv(b.Shipping),
+		Tax:      conv(b.Tax),
+	}
+	for _, a := range b.Adjustments {
+		p.Adjustments = append(p.Adjustments, &pb.PriceAdjustment{
+			Kind:        a.Kind,
+			Id:          a.ID,
+			Description: a.Description,
+			ProductId:   a.ProductID,
+			Amount:      conv(a.Amount),
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := money.Sum(p.Subtotal, money.Negate(p.Discount))
+	if err == nil {
+		total, err = money.Sum(total, p.Shipping)
+	}
+	if err == nil {
+		total, err = money.Sum(total, p.Tax)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to total order: %w", err)
+	}
+	p.Total = total
+	return p, nil
+}
+
+// Stage is a step of an Engine, such as applying promotions.
+type Stage interface {
+	Apply(c *Calculation) error
+}
+
+// StageFunc adapts a function to a Stage.
+type StageFunc func(c *Calculation) error
+
+func (f StageFunc) Apply(c *Calculation) error { return f(c) }
+
+// Engine prices orders by passing them through its stages in order.
+type Engine struct {
+	stages []Stage
+}
+
+func NewEngine(stages ...Stage) *Engine {
+	return &Engine{stages: stages}
+}
+
+// Price prices o.
+func (e *Engine) Price(o *Order) (*Breakdown, error) {
+	c, err := newCalculation(o)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range e.stages {
+		if err := s.Apply(c); err != nil {
+			return nil, err
+		}
+	}
+	return c.breakdown()
+}
+
+// Calculation is an order being priced by the stages of an Engine.
+type Calculation struct {
+	Order *Order
+
+	currency    string
+	subtotal    *pb.Money
+	lines       []*pb.Money // item totals after discounts
+	discount    *pb.Money
+	shipping    *pb.Money
+	tax         *pb.Money
+	adjustments []Adjustment
+}
+
+func newCalculation(o *Order) (*Calculation, error) {
+	c := &Calculation{Order: o}
+	switch {
+	case len(o.Items) > 0:
+		c.currency = o.Items[0].UnitPrice.GetCurrencyCode()
+	case o.Shipping != nil:
+		c.currency = o.Shipping.GetCurrencyCode()
+	default:
+		return nil, ErrEmptyOrder
+	}
+	c.subtotal = c.zero()
+	c.discount = c.zero()
+	c.tax = c.zero()
+
+	c.shipping = o.Shipping
+	if c.shipping == nil {
+		c.shipping = c.zero()
+	} else if !money.IsValid(c.shipping) || money.IsNegative(c.shipping) {
+		return nil, fmt.Errorf("invalid shipping cost %v: %w", c.shipping, money.ErrInvalidValue)
+	}
+
+	c.lines = make([]*pb.Money, len(o.Items))
+	for i, item := range o.Items {
+		if money.IsNegative(item.UnitPrice) || item.Quantity < 0 {
+			return nil, fmt.Errorf("invalid price of %q: %w", item.ProductID, money.ErrInvalidValue)
+		}
+		line, err := money.Multiply(item.UnitPrice, int64(item.Quantity))
+		if err != nil {
+			return nil, fmt.Errorf("invalid price of %q: %w", item.ProductID, err)
+		}
+		if c.subtotal, err = money.Sum(c.subtotal, line); err != nil {
+			return nil, fmt.Errorf("invalid price of %q: %w", item.ProductID, err)
+		}
+		c.lines[i] = line
+	}
+	return c, nil
+}
+
+func (c *Calculation) zero() *pb.Money { return &pb.Money{CurrencyCode: c.currency} }
+
+// Currency returns the currency the order is priced in.
+func (c *Calculation) Currency() string { return c.currency }
+
+// LineTotal returns the total of item i after the discounts so far.
+func (c *Calculation) LineTotal(i int) *pb.Money { return c.lines[i] }
+
+// Subtotal returns the total of the items after the discounts so far.
+func (c *Calculation) Subtotal() (*pb.Money, error) {
+	subtotal, err := money.Sum(c.subtotal, money.Negate(c.discount))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute subtotal: %w", err)
+	}
+	return subtotal, nil
+}
+
+// Shipping returns the shipping cost after the discounts so far.
+func (c *Calculation) Shipping() *pb.Money { return c.shipping }
+
+// discountAmount returns the amount of discount a, capped at limit.
+func discountAmount(a Adjustment, limit *pb.Money) (*pb.Money, error) {
+	if money.IsNegative(a.Amount) {
+		return nil, fmt.Errorf("invalid discount %q: %w", a.ID, money.ErrInvalidValue)
+	}
+	cmp, err := money.Compare(a.Amount, limit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid discount %q: %w", a.ID, err)
+	}
+	if cmp > 0 {
+		return limit, nil
+	}
+	return a.Amount, nil
+}
+
+// record adds a to the adjustments, with amount as its amount.
+func (c *Calculation) record(a Adjustment, amount *pb.Money) {
+	a.Amount = amount
+	c.adjustments = append(c.adjustments, a)
+}
+
+// DiscountItem takes a.Amount, a positive amount, off item i, and records
+// a. The discount is capped at the item total.
+func (c *Calculation) DiscountItem(i int, a Adjustment) error {
+	amount, err := discountAmount(a, c.lines[i])
+	if err != nil {
+		return err
+	}
+	if money.IsZero(amount) {
+		return nil
+	}
+	line, err := money.Sum(c.lines[i], money.Negate(amount))
+	if err != nil {
+		return fmt.Errorf("failed to apply discount %q: %w", a.ID, err)
+	}
+	discount, err := money.Sum(c.discount, amount)
+	if err != nil {
+		return fmt.Errorf("failed to apply discount %q: %w", a.ID, err)
+	}
+	c.lines[i], c.discount = line, discount
+	a.ProductID = c.Order.Items[i].ProductID
+	c.record(a, money.Negate(amount))
+	return nil
+}
+
+// DiscountOrder takes a.Amount, a positive amount, off the order, and
+// records a. The discount is capped at the subtotal, and is spread over the
+// items in proportion to their totals.
+func (c *Calculation) DiscountOrder(a Adjustment) error {
+	subtotal, err := c.Subtotal()
+	if err != nil {
+		return err
+	}
+	amount, err := discountAmount(a, subtotal)
+	if err != nil {
+		return err
+	}
+	if money.IsZero(amount) {
+		return nil
+	}
+	weights := make([]int64, len(c.lines))
+	for i, line := range c.lines {
+		if weights[i], err = nanos(line); err != nil {
+			return err
+		}
+	}
+	shares, err := money.AllocateByWeights(amount, weights)
+	if err != nil {
+		return fmt.Errorf("failed to spread discount %q: %w", a.ID, err)
+	}
+	lines := make([]*pb.Money, len(c.lines))
+	for i, share := range shares {
+		if lines[i], err = money.Sum(c.lines[i], money.Negate(share)); err != nil {
+			return fmt.Errorf("failed to apply discount %q: %w", a.ID, err)
+		}
+	}
+	discount, err := money.Sum(c.discount, amount)
+	if err != nil {
+		return fmt.Errorf("failed to apply discount %q: %w", a.ID, err)
+	}
+	c.lines, c.discount = lines, discount
+	c.record(a, money.Negate(amount))
+	return nil
+}
+
+// nanos returns m, which is not negative, in nanos.
+func nanos(m *pb.Money) (int64, error) {
+	const nanosPerUnit = 1000000000
+	if m.GetUnits() >= math.MaxInt64/nanosPerUnit {
+		return 0, money.ErrOverflow
+	}
+	return m.GetUnits()*nanosPerUnit + int64(m.GetNanos()), nil
+}
+
+// DiscountShipping takes a.Amount, a positive amount, off the shipping
+// cost, and records a. The discount is capped at the shipping cost.
+func (c *Calculation) DiscountShipping(a Adjustment) error {
+	amount, err := discountAmount(a, c.shipping)
+	if err != nil {
+		return err
+	}
+	if money.IsZero(amount) {
+		return nil
+	}
+	shipping, err := money.Sum(c.shipping, money.Negate(amount))
+	if err != nil {
+		return fmt.Errorf("failed to apply discount %q: %w", a.ID, err)
+	}
+	c.shipping = shipping
+	c.record(a, money.Negate(amount))
+	return nil
+}
+
+// AddTax adds a.Amount to the tax, and records a.
+func (c *Calculation) AddTax(a Adjustment) error {
+	tax, err := money.Sum(c.tax, a.Amount)
+	if err != nil {
+		return fmt.Errorf("invalid tax %q: %w", a.ID, err)
+	}
+	c.tax = tax
+	c.record(a, a.Amount)
+	return nil
+}
+
+func (c *Calculation) breakdown() (*Breakdown, error) {
+	subtotal, err := c.Subtotal()
+	if err != nil {
+		return nil, err
+	}
+	total, err := money.Sum(subtotal, c.shipping)
+	if err == nil {
+		total, err = money.Sum(total, c.tax)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to total order: %w", err)
+	}
+	return &Breakdown{
+		Subtotal:    c.subtotal,
+		Discount:    c.discount,
+		Shipping:    c.shipping,
+		Tax:         c.tax,
+		Total:       total,
+		Adjustments: c.adjustments,
+	}, nil
+}
@@ -0,0 +1,435 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package pricing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/money"
+)
+
+// Rules configure the promotions, coupons and tax rates of an Engine. They
+// are read from JSON, e.g.:
+//
+//	{
+//	  "promotions": [
+//	    {"id": "telescopes-10", "percent_off": {"category": "telescopes", "percent": 10}},
+//	    {"id": "lenses-3-for-2", "buy_x_get_y": {"product_ids": ["LENSKIT1"], "buy": 2, "get": 1}},
+//	    {"id": "free-shipping", "free_shipping": {"threshold": "100 USD"}}
+//	  ],
+//	  "coupons": [
+//	    {"code": "WELCOME5", "amount_off": "5 USD", "min_subtotal": "50 USD"}
+//	  ],
+//	  "tax": [
+//	    {"country": "United States", "state": "CA", "rate": 7.25, "shipping": true}
+//	  ]
+//	}
+type Rules struct {
+	Promotions []Promotion `json:"promotions,omitempty"`
+	Coupons    []Coupon    `json:"coupons,omitempty"`
+	Tax        []TaxRate   `json:"tax,omitempty"`
+}
+
+// Selector selects the items of an order by category or product id.
+type Selector struct {
+	Category   string   `json:"category,omitempty"`
+	ProductIDs []string `json:"product_ids,omitempty"`
+}
+
+func (s Selector) matches(item Item) bool {
+	return slices.Contains(s.ProductIDs, item.ProductID) ||
+		(s.Category != "" && slices.Contains(item.Categories, s.Category))
+}
+
+func (s Selector) validate() error {
+	if s.Category == "" && len(s.ProductIDs) == 0 {
+		return errors.New("no category or product ids")
+	}
+	return nil
+}
+
+// Promotion is applied automatically to the orders it matches. Exactly one
+// of its kinds must be set.
+type Promotion struct {
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+
+	PercentOff   *PercentOff   `json:"percent_off,omitempty"`
+	BuyXGetY     *BuyXGetY     `json:"buy_x_get_y,omitempty"`
+	FreeShipping *FreeShipping `json:"free_shipping,omitempty"`
+}
+
+// PercentOff takes a percentage off the selected items.
+type PercentOff struct {
+	Selector
+	Percent Decimal `json:"percent"`
+}
+
+// BuyXGetY makes Get of every Buy+Get units of a selected item free.
+type BuyXGetY struct {
+	Selector
+	Buy int32 `json:"buy"`
+	Get int32 `json:"get"`
+}
+
+// FreeShipping waives the shipping cost of orders whose items total at
+// least Threshold after discounts.
+type FreeShipping struct {
+	Threshold Amount `json:"threshold"`
+}
+
+// Coupon is a discount applied to orders that give its code. Exactly one of
+// PercentOff and AmountOff must be set.
+type Coupon struct {
+	Code        string `json:"code"`
+	Description string `json:"description,omitempty"`
+
+	PercentOff *Decimal `json:"percent_off,omitempty"`
+	AmountOff  *Amount  `json:"amount_off,omitempty"`
+
+	// MinSubtotal, if set, is the least the items must total after
+	// promotions for the coupon to apply.
+	MinSubtotal *Amount `json:"min_subtotal,omitempty"`
+}
+
+// TaxRate is the tax of a region: a country, or a state of a country. The
+// rate of a state takes precedence over the one of its country.
+type TaxRate struct {
+	Country     string  `json:"country"`
+	State       string  `json:"state,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Rate        Decimal `json:"rate"`
+
+	// Shipping makes the shipping cost taxable.
+	Shipping bool `json:"shipping,omitempty"`
+}
+
+func (t TaxRate) region() string {
+	if t.State == "" {
+		return t.Country
+	}
+	return t.Country + "/" + t.State
+}
+
+// Decimal is an exact decimal number, written as a JSON number or string.
+type Decimal struct {
+	*big.Rat
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(bytes.Trim(data, `"`))
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return fmt.Errorf("invalid decimal %s", data)
+	}
+	d.Rat = r
+	return nil
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d.Rat == nil {
+		return []byte("null"), nil
+	}
+	return []byte(d.RatString()), nil
+}
+
+// Amount is a money amount, written as a JSON string of a decimal number and
+// a currency code, e.g. "12.50 USD".
+type Amount struct {
+	*pb.Money
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid amount %s: %w", data, err)
+	}
+	number, code, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return fmt.Errorf("invalid amount %q: no currency code", s)
+	}
+	m, err := money.Parse(number, strings.TrimSpace(code))
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", s, err)
+	}
+	a.Money = m
+	return nil
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(money.ToDecimalString(a.Money) + " " + a.GetCurrencyCode())
+}
+
+// ParseRules reads rules from JSON and checks them.
+func ParseRules(data []byte) (*Rules, error) {
+	var r Rules
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&r); err != nil {
+		return nil, fmt.Errorf("failed to parse pricing rules: %w", err)
+	}
+	if err := r.validate(); err != nil {
+		return nil, fmt.Errorf("invalid pricing rules: %w", err)
+	}
+	return &r, nil
+}
+
+// LoadRules reads rules from a JSON file.
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pricing rules: %w", err)
+	}
+	return ParseRules(data)
+}
+
+var hundred = big.NewRat(100, 1)
+
+// validPercent reports whether p is more than 0 and at most 100.
+func validPercent(p *big.Rat) bool {
+	return p != nil && p.Sign() > 0 && p.Cmp(hundred) <= 0
+}
+
+func (r *Rules) validate() error {
+	ids := make(map[string]bool)
+	for _, p := range r.Promotions {
+		if p.ID == "" || ids[p.ID] {
+			return fmt.Errorf("promotion id %q is missing or not unique", p.ID)
+		}
+		ids[p.ID] = true
+
+		kinds := 0
+		if p.PercentOff != nil {
+			kinds++
+			if err := p.PercentOff.validate(); err != nil {
+				return fmt.Errorf("promotion %q: %w", p.ID, err)
+			} else if !validPercent(p.PercentOff.Percent.Rat) {
+				return fmt.Errorf("promotion %q: percent must be in (0, 100]", p.ID)
+			}
+		}
+		if p.BuyXGetY != nil {
+			kinds++
+			if err := p.BuyXGetY.validate(); err != nil {
+				return fmt.Errorf("promotion %q: %w", p.ID, err)
+			} else if p.BuyXGetY.Buy <= 0 || p.BuyXGetY.Get <= 0 {
+				return fmt.Errorf("promotion %q: buy and get must be positive", p.ID)
+			}
+		}
+		if p.FreeShipping != nil {
+			kinds++
+			if p.FreeShipping.Threshold.Money == nil || money.IsNegative(p.FreeShipping.Threshold.Money) {
+				return fmt.Errorf("promotion %q: threshold must not be negative", p.ID)
+			}
+		}
+		if kinds != 1 {
+			return fmt.Errorf("promotion %q must have exactly one of percent_off, buy_x_get_y and free_shipping", p.ID)
+		}
+	}
+
+	codes := make(map[string]bool)
+	for _, c := range r.Coupons {
+		code := normalizeCode(c.Code)
+		if code == "" || codes[code] {
+			return fmt.Errorf("coupon code %q is missing or not unique", c.Code)
+		}
+		codes[code] = true
+
+		switch {
+		case (c.PercentOff == nil) == (c.AmountOff == nil):
+			return fmt.Errorf("coupon %q must have exactly one of percent_off and amount_off", c.Code)
+		case c.PercentOff != nil && !validPercent(c.PercentOff.Rat):
+			return fmt.Errorf("coupon %q: percent must be in (0, 100]", c.Code)
+		case c.AmountOff != nil && !money.IsPositive(c.AmountOff.Money):
+			return fmt.Errorf("coupon %q: amount must be positive", c.Code)
+		}
+	}
+
+	regions := make(map[string]bool)
+	for _, t := range r.Tax {
+		if t.Country == "" || regions[t.region()] {
+			return fmt.Errorf("tax region %q is missing or not unique", t.region())
+		}
+		regions[t.region()] = true
+		if t.Rate.Rat == nil || t.Rate.Sign() < 0 {
+			return fmt.Errorf("tax rate of %q must not be negative", t.region())
+		}
+	}
+	return nil
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Engine returns an engine that applies, in order: the item promotions,
+// the coupons of the order, the shipping promotions, and the tax.
+func (r *Rules) Engine() *Engine {
+	return NewEngine(
+		StageFunc(r.applyItemPromotions),
+		StageFunc(r.applyCoupons),
+		StageFunc(r.applyShippingPromotions),
+		StageFunc(r.applyTax),
+	)
+}
+
+func (r *Rules) applyItemPromotions(c *Calculation) error {
+	for _, p := range r.Promotions {
+		adj := Adjustment{Kind: KindPromotion, ID: p.ID, Description: p.Description}
+		for i, item := range c.Order.Items {
+			switch {
+			case p.PercentOff != nil && p.PercentOff.matches(item):
+				amount, err := percentOf(c.LineTotal(i), p.PercentOff.Percent.Rat)
+				if err != nil {
+					return err
+				}
+				adj.Amount = amount
+			case p.BuyXGetY != nil && p.BuyXGetY.matches(item):
+				free := item.Quantity / (p.BuyXGetY.Buy + p.BuyXGetY.Get) * p.BuyXGetY.Get
+				amount, err := money.Multiply(item.UnitPrice, int64(free))
+				if err != nil {
+					return err
+				}
+				adj.Amount = amount
+			default:
+				continue
+			}
+			if err := c.DiscountItem(i, adj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// percentOf returns percent percent of m, rounded to its minor unit.
+func percentOf(m *pb.Money, percent *big.Rat) (*pb.Money, error) {
+	amount, err := money.Percentage(m, percent, money.RoundHalfEven)
+	if err != nil {
+		return nil, err
+	}
+	if rounded, err := money.RoundToMinorUnits(amount, money.RoundHalfEven); err == nil {
+		amount = rounded
+	}
+	return amount, nil
+}
+
+func (r *Rules) applyCoupons(c *Calculation) error {
+	applied := make(map[string]bool)
+	for _, given := range c.Order.CouponCodes {
+		code := normalizeCode(given)
+		if applied[code] {
+			continue
+		}
+		i := slices.IndexFunc(r.Coupons, func(coupon Coupon) bool { return normalizeCode(coupon.Code) == code })
+		if i < 0 {
+			return fmt.Errorf("%w: unknown code %q", ErrInvalidCoupon, given)
+		}
+		coupon := r.Coupons[i]
+
+		subtotal, err := c.Subtotal()
+		if err != nil {
+			return err
+		}
+		if coupon.MinSubtotal != nil {
+			cmp, err := money.Compare(subtotal, coupon.MinSubtotal.Money)
+			if err != nil {
+				return fmt.Errorf("coupon %q: %w", coupon.Code, err)
+			}
+			if cmp < 0 {
+				return fmt.Errorf("%w: %q requires a subtotal of at least %s %s", ErrInvalidCoupon, given,
+					money.ToDecimalString(coupon.MinSubtotal.Money), coupon.MinSubtotal.GetCurrencyCode())
+			}
+		}
+
+		adj := Adjustment{Kind: KindCoupon, ID: code, Description: coupon.Description}
+		if coupon.PercentOff != nil {
+			amount, err := percentOf(subtotal, coupon.PercentOff.Rat)
+			if err != nil {
+				return err
+			}
+			adj.Amount = amount
+		} else {
+			adj.Amount = coupon.AmountOff.Money
+		}
+		if err := c.DiscountOrder(adj); err != nil {
+			return err
+		}
+		applied[code] = true
+	}
+	return nil
+}
+
+func (r *Rules) applyShippingPromotions(c *Calculation) error {
+	for _, p := range r.Promotions {
+		if p.FreeShipping == nil {
+			continue
+		}
+		subtotal, err := c.Subtotal()
+		if err != nil {
+			return err
+		}
+		cmp, err := money.Compare(subtotal, p.FreeShipping.Threshold.Money)
+		if err != nil {
+			return fmt.Errorf("promotion %q: %w", p.ID, err)
+		}
+		if cmp < 0 {
+			continue
+		}
+		adj := Adjustment{Kind: KindPromotion, ID: p.ID, Description: p.Description, Amount: c.Shipping()}
+		if err := c.DiscountShipping(adj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taxRate returns the tax rate of address, if there is one.
+func (r *Rules) taxRate(address *pb.Address) (TaxRate, bool) {
+	var countryRate *TaxRate
+	for i, t := range r.Tax {
+		if !strings.EqualFold(t.Country, address.GetCountry()) {
+			continue
+		}
+		if t.State == "" {
+			countryRate = &r.Tax[i]
+		} else if strings.EqualFold(t.State, address.GetState()) {
+			return t, true
+		}
+	}
+	if countryRate == nil {
+		return TaxRate{}, false
+	}
+	return *countryRate, true
+}
+
+func (r *Rules) applyTax(c *Calculation) error {
+	t, ok := r.taxRate(c.Order.Address)
+	if !ok {
+		return nil
+	}
+	base, err := c.Subtotal()
+	if err != nil {
+		return err
+	}
+	if t.Shipping {
+		if base, err = money.Sum(base, c.Shipping()); err != nil {
+			return fmt.Errorf("tax of %q: %w", t.region(), err)
+		}
+	}
+	amount, err := percentOf(base, t.Rate.Rat)
+	if err != nil {
+		return fmt.Errorf("tax of %q: %w", t.region(), err)
+	}
+	if money.IsZero(amount) {
+		return nil
+	}
+	return c.AddTax(Adjustment{Kind: KindTax, ID: t.region(), Description: t.Description, Amount: amount})
+}
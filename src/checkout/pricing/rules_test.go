@@ -0,0 +1,286 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package pricing
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/money"
+)
+
+func usd(u int64, n int32) *pb.Money { return &pb.Money{Units: u, Nanos: n, CurrencyCode: "USD"} }
+
+const testRules = `{
+  "promotions": [
+    {"id": "telescopes-20", "percent_off": {"category": "telescopes", "percent": 20}},
+    {"id": "books-2-for-1", "buy_x_get_y": {"product_ids": ["BOOK"], "buy": 1, "get": 1}},
+    {"id": "free-shipping", "free_shipping": {"threshold": "500 USD"}}
+  ],
+  "coupons": [
+    {"code": "WELCOME5", "amount_off": "5 USD", "min_subtotal": "50 USD"},
+    {"code": "Stargazer10", "percent_off": "10"}
+  ],
+  "tax": [
+    {"country": "United States", "rate": 5},
+    {"country": "United States", "state": "CA", "rate": 7.25, "shipping": true}
+  ]
+}`
+
+func mustParseRules(t *testing.T, data string) *Rules {
+	t.Helper()
+	r, err := ParseRules([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseRules(): unexpected err=\"%v\"", err)
+	}
+	return r
+}
+
+func testOrder(state string, coupons ...string) *Order {
+	return &Order{
+		Items: []Item{
+			{ProductID: "TEL", Categories: []string{"telescopes"}, UnitPrice: usd(100, 0), Quantity: 2},
+			{ProductID: "BOOK", Categories: []string{"books"}, UnitPrice: usd(10, 500000000), Quantity: 3},
+			{ProductID: "ACC", Categories: []string{"accessories"}, UnitPrice: usd(5, 0), Quantity: 1},
+		},
+		Shipping:    usd(8, 990000000),
+		Address:     &pb.Address{Country: "united states", State: state},
+		CouponCodes: coupons,
+	}
+}
+
+func TestEngine_Price(t *testing.T) {
+	rules := mustParseRules(t, testRules)
+
+	got, err := rules.Engine().Price(testOrder("CA", "welcome5", "WELCOME5"))
+	if err != nil {
+		t.Fatalf("Price(): unexpected err=\"%v\"", err)
+	}
+	// 236.50 - 40 (20% of 200) - 10.50 (one book of three) - 5 (coupon),
+	// plus 8.99 shipping and 7.25% tax on 189.99.
+	want := &Breakdown{
+		Subtotal: usd(236, 500000000),
+		Discount: usd(55, 500000000),
+		Shipping: usd(8, 990000000),
+		Tax:      usd(13, 770000000),
+		Total:    usd(203, 760000000),
+		Adjustments: []Adjustment{
+			{Kind: KindPromotion, ID: "telescopes-20", ProductID: "TEL", Amount: usd(-40, 0)},
+			{Kind: KindPromotion, ID: "books-2-for-1", ProductID: "BOOK", Amount: usd(-10, -500000000)},
+			{Kind: KindCoupon, ID: "WELCOME5", Amount: usd(-5, 0)},
+			{Kind: KindTax, ID: "United States/CA", Amount: usd(13, 770000000)},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Price() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestEngine_PriceFreeShipping(t *testing.T) {
+	rules := mustParseRules(t, testRules)
+	rules.Promotions[2].FreeShipping.Threshold.Money = usd(150, 0)
+
+	got, err := rules.Engine().Price(testOrder("NY", "stargazer10"))
+	if err != nil {
+		t.Fatalf("Price(): unexpected err=\"%v\"", err)
+	}
+	// 186 after promotions, less 10% is 167.40, taxed 5% without shipping.
+	if !money.IsZero(got.Shipping) {
+		t.Errorf("Shipping = %v, want zero", got.Shipping)
+	}
+	if want := usd(8, 370000000); !money.AreEquals(got.Tax, want) {
+		t.Errorf("Tax = %v, want %v", got.Tax, want)
+	}
+	if want := usd(175, 770000000); !money.AreEquals(got.Total, want) {
+		t.Errorf("Total = %v, want %v", got.Total, want)
+	}
+	last := got.Adjustments[len(got.Adjustments)-2]
+	if last.ID != "free-shipping" || !money.AreEquals(last.Amount, usd(-8, -990000000)) {
+		t.Errorf("shipping adjustment = %+v", last)
+	}
+}
+
+func TestEngine_PriceWithoutRules(t *testing.T) {
+	var rules Rules
+	got, err := rules.Engine().Price(testOrder("CA"))
+	if err != nil {
+		t.Fatalf("Price(): unexpected err=\"%v\"", err)
+	}
+	if want := usd(245, 490000000); !money.AreEquals(got.Total, want) || len(got.Adjustments) != 0 {
+		t.Errorf("Price() = %+v, want a total of %v", got, want)
+	}
+
+	if _, err := rules.Engine().Price(&Order{}); !errors.Is(err, ErrEmptyOrder) {
+		t.Errorf("Price(empty): expected err=\"%v\" got=\"%v\"", ErrEmptyOrder, err)
+	}
+}
+
+func TestEngine_PriceSubUnit(t *testing.T) {
+	rules := mustParseRules(t, testRules)
+	rules.Coupons = append(rules.Coupons, mustParseRules(t, `{"coupons": [{"code": "PENNIES", "amount_off": "0.25 USD"}]}`).Coupons...)
+	tests := []struct {
+		name     string
+		rules    *Rules
+		items    []Item
+		shipping *pb.Money
+		state    string
+		coupons  []string
+		discount *pb.Money
+		tax      *pb.Money
+		total    *pb.Money
+	}{
+		{
+			name:  "without rules",
+			rules: &Rules{},
+			items: []Item{{ProductID: "BOOK", UnitPrice: usd(0, 990000000), Quantity: 1}},
+			total: usd(0, 990000000),
+		},
+		{
+			// 1.98 - 0.99, plus 7.25% tax on 0.99 + 0.50 shipping.
+			name:     "item discount and tax",
+			rules:    rules,
+			items:    []Item{{ProductID: "BOOK", UnitPrice: usd(0, 990000000), Quantity: 2}},
+			shipping: usd(0, 500000000),
+			state:    "CA",
+			discount: usd(0, 990000000),
+			tax:      usd(0, 110000000),
+			total:    usd(1, 600000000),
+		},
+		{
+			// 0.60 - 0.25, plus 5% tax on 0.35.
+			name:     "coupon and tax",
+			rules:    rules,
+			items:    []Item{{ProductID: "ACC", UnitPrice: usd(0, 300000000), Quantity: 2}},
+			state:    "NY",
+			coupons:  []string{"pennies"},
+			discount: usd(0, 250000000),
+			tax:      usd(0, 20000000),
+			total:    usd(0, 370000000),
+		},
+		{
+			// 0.45 - 20%, plus 5% tax on 0.36.
+			name:     "percent discount",
+			rules:    rules,
+			items:    []Item{{ProductID: "TEL", Categories: []string{"telescopes"}, UnitPrice: usd(0, 450000000), Quantity: 1}},
+			state:    "NY",
+			discount: usd(0, 90000000),
+			tax:      usd(0, 20000000),
+			total:    usd(0, 380000000),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &Order{
+				Items:       tt.items,
+				Shipping:    tt.shipping,
+				Address:     &pb.Address{Country: "United States", State: tt.state},
+				CouponCodes: tt.coupons,
+			}
+			got, err := tt.rules.Engine().Price(order)
+			if err != nil {
+				t.Fatalf("Price(): unexpected err=\"%v\"", err)
+			}
+			for _, m := range []struct {
+				name      string
+				got, want *pb.Money
+			}{
+				{"Discount", got.Discount, tt.discount},
+				{"Tax", got.Tax, tt.tax},
+				{"Total", got.Total, tt.total},
+			} {
+				want := m.want
+				if want == nil {
+					want = usd(0, 0)
+				}
+				if !money.AreEquals(m.got, want) {
+					t.Errorf("%s = %v, want %v", m.name, m.got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEngine_PriceInvalidCoupon(t *testing.T) {
+	rules := mustParseRules(t, testRules)
+
+	if _, err := rules.Engine().Price(testOrder("CA", "NOPE")); !errors.Is(err, ErrInvalidCoupon) {
+		t.Errorf("Price(unknown coupon): expected err=\"%v\" got=\"%v\"", ErrInvalidCoupon, err)
+	}
+
+	small := &Order{
+		Items:       []Item{{ProductID: "ACC", UnitPrice: usd(5, 0), Quantity: 2}},
+		CouponCodes: []string{"WELCOME5"},
+	}
+	if _, err := rules.Engine().Price(small); !errors.Is(err, ErrInvalidCoupon) {
+		t.Errorf("Price(below min subtotal): expected err=\"%v\" got=\"%v\"", ErrInvalidCoupon, err)
+	}
+}
+
+func TestCalculation_DiscountOrderCapped(t *testing.T) {
+	engine := NewEngine(StageFunc(func(c *Calculation) error {
+		return c.DiscountOrder(Adjustment{Kind: KindCoupon, ID: "ALL", Amount: usd(1000, 0)})
+	}))
+	got, err := engine.Price(testOrder(""))
+	if err != nil {
+		t.Fatalf("Price(): unexpected err=\"%v\"", err)
+	}
+	if !money.AreEquals(got.Discount, got.Subtotal) || !money.AreEquals(got.Total, got.Shipping) {
+		t.Errorf("Price() = %+v, want the items to be free", got)
+	}
+}
+
+func TestBreakdown_Proto(t *testing.T) {
+	b := &Breakdown{
+		Subtotal: usd(1, 0),
+		Discount: usd(0, 0),
+		Shipping: usd(1, 0),
+		Tax:      usd(1, 0),
+		Total:    usd(3, 0),
+	}
+	// Each amount converts to 0.333333333 EUR, while the total on its own
+	// would convert to 1 EUR.
+	rate := big.NewRat(1, 3)
+	got, err := b.Proto(func(m *pb.Money) (*pb.Money, error) {
+		return money.Convert(m, "EUR", rate, money.RoundHalfEven)
+	})
+	if err != nil {
+		t.Fatalf("Proto(): unexpected err=\"%v\"", err)
+	}
+	want := &pb.Money{Units: 0, Nanos: 999999999, CurrencyCode: "EUR"}
+	if !money.AreEquals(got.Total, want) {
+		t.Errorf("Proto().Total = %v, want %v", got.Total, want)
+	}
+}
+
+func TestParseRules_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"unknown field", `{"discounts": []}`, "unknown field"},
+		{"two kinds", `{"promotions": [{"id": "p", "free_shipping": {"threshold": "1 USD"}, "percent_off": {"category": "c", "percent": 5}}]}`, "exactly one"},
+		{"no kind", `{"promotions": [{"id": "p"}]}`, "exactly one"},
+		{"duplicate id", `{"promotions": [{"id": "p", "free_shipping": {"threshold": "1 USD"}}, {"id": "p", "free_shipping": {"threshold": "2 USD"}}]}`, "not unique"},
+		{"no selector", `{"promotions": [{"id": "p", "percent_off": {"percent": 5}}]}`, "no category"},
+		{"zero percent", `{"promotions": [{"id": "p", "percent_off": {"category": "c", "percent": 0}}]}`, "percent must be"},
+		{"buy zero", `{"promotions": [{"id": "p", "buy_x_get_y": {"category": "c", "buy": 0, "get": 1}}]}`, "must be positive"},
+		{"bad amount", `{"coupons": [{"code": "C", "amount_off": "5"}]}`, "no currency code"},
+		{"coupon both", `{"coupons": [{"code": "C", "amount_off": "5 USD", "percent_off": 5}]}`, "exactly one"},
+		{"duplicate code", `{"coupons": [{"code": "c", "percent_off": 5}, {"code": "C ", "percent_off": 10}]}`, "not unique"},
+		{"negative tax", `{"tax": [{"country": "France", "rate": -1}]}`, "must not be negative"},
+		{"duplicate region", `{"tax": [{"country": "France", "rate": 20}, {"country": "France", "rate": 10}]}`, "not unique"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRules([]byte(tt.json))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseRules() err=\"%v\", want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package protohttp calls services that take and return protobuf messages
+// as JSON over HTTP, like the shipping and email services.
+package protohttp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// The services use the field names of the proto files.
+	marshalOptions   = protojson.MarshalOptions{UseProtoNames: true}
+	unmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// StatusError is a response with a status other than 200 OK.
+type StatusError struct {
+	Service    string
+	Path       string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s service returned %d %s for %s", e.Service, e.StatusCode, http.StatusText(e.StatusCode), e.Path)
+}
+
+// Client posts messages to a service.
+type Client struct {
+	// Service names the service in errors.
+	Service string
+
+	// BaseURL is the URL the paths of the calls are relative to.
+	BaseURL string
+
+	HTTPClient *http.Client
+}
+
+// Call posts req as JSON to path and reads the JSON response into resp.
+func (c *Client) Call(ctx context.Context, path string, req, resp proto.Message) error {
+	payload, err := marshalOptions.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s request: %w", path, err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.BaseURL, "/")+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", path, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed POST to %s service: %w", c.Service, err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return &StatusError{Service: c.Service, Path: path, StatusCode: httpResp.StatusCode}
+	}
+
+	// Services answer calls returning Empty with any body, or none.
+	if resp.ProtoReflect().Descriptor().Fields().Len() == 0 {
+		proto.Reset(resp)
+		return nil
+	}
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", path, err)
+	}
+	if err := unmarshalOptions.Unmarshal(body, resp); err != nil {
+		return fmt.Errorf("failed to unmarshal %s response: %w", path, err)
+	}
+	return nil
+}
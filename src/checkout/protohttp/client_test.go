@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package protohttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+func TestClient_Call(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/get-quote" || r.Header.Get("Content-Type") != "application/json" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &got)
+		// Like the shipping service, with units as a number and an extra
+		// field.
+		w.Write([]byte(`{"cost_usd": {"currency_code": "USD", "units": 8, "nanos": 990000000}, "eta": "2d"}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{Service: "shipping", BaseURL: srv.URL + "/", HTTPClient: srv.Client()}
+	resp := new(pb.GetQuoteResponse)
+	err := c.Call(context.Background(), "/get-quote", &pb.GetQuoteRequest{
+		Address: &pb.Address{ZipCode: "94043"},
+		Items:   []*pb.CartItem{{ProductId: "OLJCESPC7Z", Quantity: 2}},
+	}, resp)
+	if err != nil {
+		t.Fatalf("Call(): unexpected err=\"%v\"", err)
+	}
+
+	want := &pb.GetQuoteResponse{CostUsd: &pb.Money{CurrencyCode: "USD", Units: 8, Nanos: 990000000}}
+	if !proto.Equal(resp, want) {
+		t.Errorf("response = %v, want %v", resp, want)
+	}
+	// The request uses the field names of the proto file.
+	if got["address"].(map[string]any)["zip_code"] != "94043" || got["items"].([]any)[0].(map[string]any)["product_id"] != "OLJCESPC7Z" {
+		t.Errorf("request = %v", got)
+	}
+}
+
+func TestClient_CallEmpty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("sent"))
+	}))
+	defer srv.Close()
+
+	c := &Client{Service: "email", BaseURL: srv.URL, HTTPClient: srv.Client()}
+	if err := c.Call(context.Background(), "/send_order_confirmation", &pb.SendOrderConfirmationRequest{}, new(pb.Empty)); err != nil {
+		t.Errorf("Call(): unexpected err=\"%v\"", err)
+	}
+}
+
+func TestClient_CallStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := &Client{Service: "shipping", BaseURL: srv.URL, HTTPClient: srv.Client()}
+	err := c.Call(context.Background(), "/ship-order", &pb.ShipOrderRequest{}, new(pb.ShipOrderResponse))
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable || statusErr.Service != "shipping" {
+		t.Fatalf("Call(): err=\"%v\", want a 503 StatusError", err)
+	}
+	if want := "shipping service returned 503 Service Unavailable for /ship-order"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
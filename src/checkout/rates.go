@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/money"
+)
+
+// rateProbeUnits is the amount converted to derive a rate. The currency
+// service returns nanos, so converting one unit would keep as few as three
+// significant digits of e.g. the JPY/USD rate.
+const rateProbeUnits = 1000000
+
+// currencyRates is a money.RateProvider backed by the currency service. It
+// asks for each rate once, so all amounts of an order are converted at the
+// same rates.
+type currencyRates struct {
+	client pb.CurrencyServiceClient
+
+	mu    sync.Mutex
+	rates map[[2]string]*big.Rat
+}
+
+func newCurrencyRates(client pb.CurrencyServiceClient) *currencyRates {
+	return &currencyRates{client: client, rates: make(map[[2]string]*big.Rat)}
+}
+
+func (r *currencyRates) Rate(ctx context.Context, from, to string) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	// The lock is held while converting, so concurrent callers wait for the
+	// rate rather than asking for it again.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rate, ok := r.rates[[2]string{from, to}]; ok {
+		return rate, nil
+	}
+
+	converted, err := r.client.Convert(ctx, &pb.CurrencyConversionRequest{
+		From:   &pb.Money{CurrencyCode: from, Units: rateProbeUnits},
+		ToCode: to})
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert currency: %w", err)
+	}
+	if !money.IsPositive(converted) {
+		return nil, fmt.Errorf("invalid conversion of %d %s to %s: %v", rateProbeUnits, from, to, converted)
+	}
+	rate := money.ToRat(converted)
+	rate.Quo(rate, big.NewRat(rateProbeUnits, 1))
+	r.rates[[2]string{from, to}] = rate
+	return rate, nil
+}
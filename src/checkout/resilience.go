@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"google.golang.org/grpc"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/resilience"
+)
+
+// idempotentMethods are the gRPC methods of each dependency that are safe
+// to retry. Charges and refunds are not, as a retried call could go through
+// twice.
+var idempotentMethods = map[string][]string{
+	"cart": {
+		pb.CartService_GetCart_FullMethodName,
+		pb.CartService_EmptyCart_FullMethodName,
+	},
+	"product-catalog": {
+		pb.ProductCatalogService_GetProduct_FullMethodName,
+		pb.ProductCatalogService_GetProducts_FullMethodName,
+		pb.ProductCatalogService_ListProducts_FullMethodName,
+	},
+	"currency": {
+		pb.CurrencyService_GetSupportedCurrencies_FullMethodName,
+		pb.CurrencyService_Convert_FullMethodName,
+	},
+	"shipping": {
+		pb.ShippingService_GetQuote_FullMethodName,
+	},
+}
+
+// idempotentRequests tells which HTTP requests to each dependency are safe
+// to retry. Shipping an order or sending an email twice is not.
+var idempotentRequests = map[string]func(*http.Request) bool{
+	"shipping": func(req *http.Request) bool { return req.URL.Path == "/get-quote" },
+}
+
+// loadResilienceConfig loads the policies of the dependencies from the file
+// at CHECKOUT_RESILIENCE_CONFIG, if set, and the environment.
+func loadResilienceConfig() *resilience.Config {
+	config, err := resilience.LoadConfig(os.Getenv("CHECKOUT_RESILIENCE_CONFIG"), os.Getenv)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("%v, using the default resilience policy", err))
+		return resilience.NewConfig(nil)
+	}
+	return config
+}
+
+// newDependency returns the dependency with the policy of name in config,
+// or the default policy if it is invalid.
+func newDependency(config *resilience.Config, name string) *resilience.Dependency {
+	policy, err := config.Policy(name)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("%v, using the default resilience policy", err))
+		policy = resilience.DefaultPolicy
+	}
+	dep, err := resilience.NewDependency(name, policy, logger)
+	if err != nil {
+		panic(err)
+	}
+	logger.Info(fmt.Sprintf("%s resilience policy: %+v", name, dep.Policy()))
+	return dep
+}
+
+// grpcOption returns the dial option applying the policy of dep.
+func grpcOption(dep *resilience.Dependency) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(dep.UnaryClientInterceptor(idempotentMethods[dep.Name()]...))
+}
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned for calls rejected by an open circuit breaker.
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a circuit breaker.
+type State int
+
+const (
+	// StateClosed lets all calls through.
+	StateClosed State = iota
+
+	// StateHalfOpen lets a single call through to probe the dependency.
+	StateHalfOpen
+
+	// StateOpen rejects all calls.
+	StateOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	}
+	return "unknown"
+}
+
+// Breaker is a circuit breaker. It opens after a number of consecutive
+// failures, and after a cooldown lets one call through: the breaker closes
+// if it succeeds and opens again if it fails.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewBreaker returns a closed breaker that opens after threshold
+// consecutive failures, for cooldown.
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// State returns the state of the breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Allow returns ErrOpen if a call must not be made now. Otherwise the
+// outcome of the call must be passed to Record. It returns the states
+// before and after, which differ when the cooldown has elapsed.
+func (b *Breaker) Allow() (from, to State, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	from = b.state
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return from, b.state, ErrOpen
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+	case StateHalfOpen:
+		if b.probing {
+			return from, b.state, ErrOpen
+		}
+		b.probing = true
+	}
+	return from, b.state, nil
+}
+
+// Record records the outcome of a call allowed by Allow, returning the
+// states before and after.
+func (b *Breaker) Record(success bool) (from, to State) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	from = b.state
+	switch {
+	case success:
+		b.failures = 0
+		b.state = StateClosed
+		b.probing = false
+	case b.state == StateHalfOpen:
+		b.open()
+	default:
+		b.failures++
+		if b.failures >= b.threshold && b.state == StateClosed {
+			b.open()
+		}
+	}
+	return from, b.state
+}
+
+// Release ends a call allowed by Allow whose outcome says nothing about the
+// dependency, e.g. a client error.
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateHalfOpen {
+		b.probing = false
+	}
+}
+
+func (b *Breaker) open() {
+	b.state = StateOpen
+	b.openedAt = b.now()
+	b.failures = 0
+	b.probing = false
+}
@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package resilience
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewBreaker(3, 10*time.Second)
+	b.now = func() time.Time { return now }
+
+	fail := func() (State, State) {
+		if _, _, err := b.Allow(); err != nil {
+			t.Fatalf("Allow(): unexpected err=\"%v\"", err)
+		}
+		return b.Record(false)
+	}
+
+	fail()
+	fail()
+	if _, _, err := b.Allow(); err != nil {
+		t.Fatalf("Allow(): unexpected err=\"%v\"", err)
+	}
+	b.Record(true) // resets the count
+	fail()
+	fail()
+	if from, to := fail(); from != StateClosed || to != StateOpen {
+		t.Fatalf("third failure: %v -> %v, want closed -> open", from, to)
+	}
+
+	if _, _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Errorf("Allow() while open: expected err=\"%v\" got=\"%v\"", ErrOpen, err)
+	}
+
+	// After the cooldown a single probe goes through.
+	now = now.Add(10 * time.Second)
+	from, to, err := b.Allow()
+	if err != nil || from != StateOpen || to != StateHalfOpen {
+		t.Fatalf("Allow() after cooldown = %v -> %v, err=\"%v\"", from, to, err)
+	}
+	if _, _, err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Errorf("second Allow() while probing: expected err=\"%v\" got=\"%v\"", ErrOpen, err)
+	}
+	if _, to := b.Record(false); to != StateOpen {
+		t.Fatalf("failed probe: state %v, want open", to)
+	}
+
+	now = now.Add(10 * time.Second)
+	b.Allow()
+	b.Release() // an abandoned probe lets another one through
+	if _, _, err := b.Allow(); err != nil {
+		t.Fatalf("Allow() after release: unexpected err=\"%v\"", err)
+	}
+	if _, to := b.Record(true); to != StateClosed {
+		t.Errorf("successful probe: state %v, want closed", to)
+	}
+}
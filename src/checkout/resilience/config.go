@@ -0,0 +1,220 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package resilience protects checkout from slow or failing dependencies:
+// calls get a deadline, idempotent calls are retried with jittered backoff,
+// and a circuit breaker per dependency fails calls fast while it is down.
+// It applies to gRPC clients as an interceptor and to HTTP clients as a
+// RoundTripper.
+package resilience
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy configures the calls to a dependency.
+type Policy struct {
+	// Timeout is the deadline of each attempt of a call. Zero means none.
+	Timeout Duration `json:"timeout"`
+
+	// MaxAttempts is the number of attempts of idempotent calls, including
+	// the first one. Other calls are attempted once.
+	MaxAttempts int `json:"max_attempts"`
+
+	// InitialBackoff and MaxBackoff bound the delay before a retry, which is
+	// random up to InitialBackoff doubled for each retry, capped at
+	// MaxBackoff.
+	InitialBackoff Duration `json:"initial_backoff"`
+	MaxBackoff     Duration `json:"max_backoff"`
+
+	// BreakerThreshold is the number of consecutive failures that opens the
+	// circuit breaker. Zero disables the breaker.
+	BreakerThreshold int `json:"breaker_threshold"`
+
+	// BreakerCooldown is how long the breaker stays open before it lets a
+	// call through to probe the dependency.
+	BreakerCooldown Duration `json:"breaker_cooldown"`
+}
+
+// DefaultPolicy is the policy of dependencies that are not configured.
+var DefaultPolicy = Policy{
+	Timeout:          Duration(5 * time.Second),
+	MaxAttempts:      3,
+	InitialBackoff:   Duration(100 * time.Millisecond),
+	MaxBackoff:       Duration(time.Second),
+	BreakerThreshold: 5,
+	BreakerCooldown:  Duration(10 * time.Second),
+}
+
+func (p Policy) validate() error {
+	switch {
+	case p.Timeout < 0 || p.InitialBackoff < 0 || p.MaxBackoff < 0 || p.BreakerCooldown < 0:
+		return fmt.Errorf("durations must not be negative")
+	case p.MaxAttempts < 1:
+		return fmt.Errorf("max_attempts must be at least 1")
+	case p.BreakerThreshold < 0:
+		return fmt.Errorf("breaker_threshold must not be negative")
+	}
+	return nil
+}
+
+// Duration is a time.Duration written in JSON as a string such as "250ms".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid duration %s: %w", data, err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) String() string { return time.Duration(d).String() }
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Config holds the policies of the dependencies. A dependency's policy is
+// the default policy, overridden by the fields set for the dependency in the
+// config file, overridden by environment variables named
+// CHECKOUT_RESILIENCE_<DEPENDENCY>_<FIELD>, e.g.
+// CHECKOUT_RESILIENCE_PAYMENT_TIMEOUT=2s. The default policy can be set the
+// same way, with DEFAULT as the dependency.
+type Config struct {
+	defaults     Policy
+	dependencies map[string]json.RawMessage
+	getenv       func(string) string
+}
+
+// NewConfig returns a config with DefaultPolicy for all dependencies,
+// reading overrides from getenv, e.g. os.Getenv.
+func NewConfig(getenv func(string) string) *Config {
+	return &Config{defaults: DefaultPolicy, getenv: getenv}
+}
+
+// LoadConfig reads a config file, e.g.:
+//
+//	{
+//	  "default": {"timeout": "3s"},
+//	  "dependencies": {
+//	    "payment": {"max_attempts": 1, "breaker_threshold": 10}
+//	  }
+//	}
+//
+// An empty path is the same as an empty file.
+func LoadConfig(path string, getenv func(string) string) (*Config, error) {
+	c := NewConfig(getenv)
+	if path == "" {
+		return c, c.init()
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read resilience config: %w", err)
+	}
+
+	var file struct {
+		Default      json.RawMessage            `json:"default"`
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse resilience config: %w", err)
+	}
+	if err := decodePolicy(file.Default, &c.defaults); err != nil {
+		return nil, fmt.Errorf("invalid default policy: %w", err)
+	}
+	c.dependencies = file.Dependencies
+	return c, c.init()
+}
+
+// init applies the environment to the default policy and checks the
+// policies configured so far.
+func (c *Config) init() error {
+	if err := c.applyEnv("DEFAULT", &c.defaults); err != nil {
+		return err
+	}
+	if err := c.defaults.validate(); err != nil {
+		return fmt.Errorf("invalid default policy: %w", err)
+	}
+	for name := range c.dependencies {
+		if _, err := c.Policy(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodePolicy(data json.RawMessage, p *Policy) error {
+	if len(data) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(p)
+}
+
+// Policy returns the policy of a dependency.
+func (c *Config) Policy(dependency string) (Policy, error) {
+	p := c.defaults
+	if err := decodePolicy(c.dependencies[dependency], &p); err != nil {
+		return p, fmt.Errorf("invalid policy of %s: %w", dependency, err)
+	}
+	if err := c.applyEnv(dependency, &p); err != nil {
+		return p, err
+	}
+	if err := p.validate(); err != nil {
+		return p, fmt.Errorf("invalid policy of %s: %w", dependency, err)
+	}
+	return p, nil
+}
+
+func (c *Config) applyEnv(dependency string, p *Policy) error {
+	if c.getenv == nil {
+		return nil
+	}
+	prefix := "CHECKOUT_RESILIENCE_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(dependency)) + "_"
+
+	durations := map[string]*Duration{
+		"TIMEOUT":          &p.Timeout,
+		"INITIAL_BACKOFF":  &p.InitialBackoff,
+		"MAX_BACKOFF":      &p.MaxBackoff,
+		"BREAKER_COOLDOWN": &p.BreakerCooldown,
+	}
+	for field, d := range durations {
+		if v := c.getenv(prefix + field); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s%s: %w", prefix, field, err)
+			}
+			*d = Duration(parsed)
+		}
+	}
+
+	ints := map[string]*int{
+		"MAX_ATTEMPTS":      &p.MaxAttempts,
+		"BREAKER_THRESHOLD": &p.BreakerThreshold,
+	}
+	for field, n := range ints {
+		if v := c.getenv(prefix + field); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s%s: %w", prefix, field, err)
+			}
+			*n = parsed
+		}
+	}
+	return nil
+}
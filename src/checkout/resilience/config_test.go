@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package resilience
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "resilience.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, `{
+		"default": {"timeout": "3s"},
+		"dependencies": {"payment": {"max_attempts": 1, "breaker_threshold": 10}}
+	}`)
+	env := map[string]string{
+		"CHECKOUT_RESILIENCE_DEFAULT_MAX_BACKOFF":          "2s",
+		"CHECKOUT_RESILIENCE_PAYMENT_TIMEOUT":              "1500ms",
+		"CHECKOUT_RESILIENCE_PRODUCT_CATALOG_MAX_ATTEMPTS": "5",
+	}
+	c, err := LoadConfig(path, func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("LoadConfig(): unexpected err=\"%v\"", err)
+	}
+
+	tests := []struct {
+		dependency string
+		want       Policy
+	}{
+		{"cart", Policy{
+			Timeout: Duration(3 * time.Second), MaxAttempts: 3,
+			InitialBackoff: DefaultPolicy.InitialBackoff, MaxBackoff: Duration(2 * time.Second),
+			BreakerThreshold: 5, BreakerCooldown: DefaultPolicy.BreakerCooldown,
+		}},
+		{"payment", Policy{
+			Timeout: Duration(1500 * time.Millisecond), MaxAttempts: 1,
+			InitialBackoff: DefaultPolicy.InitialBackoff, MaxBackoff: Duration(2 * time.Second),
+			BreakerThreshold: 10, BreakerCooldown: DefaultPolicy.BreakerCooldown,
+		}},
+		{"product-catalog", Policy{
+			Timeout: Duration(3 * time.Second), MaxAttempts: 5,
+			InitialBackoff: DefaultPolicy.InitialBackoff, MaxBackoff: Duration(2 * time.Second),
+			BreakerThreshold: 5, BreakerCooldown: DefaultPolicy.BreakerCooldown,
+		}},
+	}
+	for _, tt := range tests {
+		got, err := c.Policy(tt.dependency)
+		if err != nil || got != tt.want {
+			t.Errorf("Policy(%q) = %+v, err=\"%v\", want %+v", tt.dependency, got, err, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		want string
+	}{
+		{"unknown field", `{"dependencies": {"cart": {"retries": 2}}}`, nil, "unknown field"},
+		{"bad duration", `{"default": {"timeout": "soon"}}`, nil, "invalid duration"},
+		{"no attempts", `{"dependencies": {"cart": {"max_attempts": 0}}}`, nil, "max_attempts"},
+		{"bad env", `{}`, map[string]string{"CHECKOUT_RESILIENCE_DEFAULT_MAX_ATTEMPTS": "x"}, "CHECKOUT_RESILIENCE_DEFAULT_MAX_ATTEMPTS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tt.file), func(k string) string { return tt.env[k] })
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadConfig() err=\"%v\", want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
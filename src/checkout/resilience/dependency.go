@@ -0,0 +1,241 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// outcome is how a call went, as far as the policy is concerned.
+type outcome int
+
+const (
+	// outcomeOK is a call the dependency answered, including client errors.
+	outcomeOK outcome = iota
+
+	// outcomeFailed is a failure of the dependency that may persist.
+	outcomeFailed
+
+	// outcomeTransient is a failure of the dependency that may not happen
+	// again, so idempotent calls are retried.
+	outcomeTransient
+
+	// outcomeAbandoned is a call the caller gave up on.
+	outcomeAbandoned
+)
+
+// Dependency applies a Policy to the calls to a downstream service.
+type Dependency struct {
+	name    string
+	policy  Policy
+	breaker *Breaker
+	logger  *slog.Logger
+	attrs   metric.MeasurementOption
+}
+
+// NewDependency returns a Dependency with the given name, used in metrics
+// and span events.
+func NewDependency(name string, policy Policy, logger *slog.Logger) (*Dependency, error) {
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy of %s: %w", name, err)
+	}
+	d := &Dependency{
+		name:   name,
+		policy: policy,
+		logger: logger,
+		attrs:  metric.WithAttributes(attribute.String("app.dependency", name)),
+	}
+	if policy.BreakerThreshold > 0 {
+		d.breaker = NewBreaker(policy.BreakerThreshold, time.Duration(policy.BreakerCooldown))
+	}
+	if err := register(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Name returns the name of the dependency.
+func (d *Dependency) Name() string { return d.name }
+
+// Policy returns the policy of the dependency.
+func (d *Dependency) Policy() Policy { return d.policy }
+
+// BreakerState returns the state of the circuit breaker, which is always
+// closed when it is disabled.
+func (d *Dependency) BreakerState() State {
+	if d.breaker == nil {
+		return StateClosed
+	}
+	return d.breaker.State()
+}
+
+// attempts returns the number of attempts of a call.
+func (d *Dependency) attempts(idempotent bool) int {
+	if !idempotent {
+		return 1
+	}
+	return d.policy.MaxAttempts
+}
+
+// attemptContext returns the context of an attempt, with the timeout of the
+// policy.
+func (d *Dependency) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.policy.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(d.policy.Timeout))
+}
+
+// allow returns an error wrapping ErrOpen if the breaker rejects the call.
+func (d *Dependency) allow(ctx context.Context) error {
+	if d.breaker == nil {
+		return nil
+	}
+	from, to, err := d.breaker.Allow()
+	d.transitioned(ctx, from, to)
+	if err != nil {
+		metrics.rejections.Add(ctx, 1, d.attrs)
+		trace.SpanFromContext(ctx).AddEvent("circuit breaker rejected call",
+			trace.WithAttributes(attribute.String("app.dependency", d.name)))
+		return fmt.Errorf("%s: %w", d.name, err)
+	}
+	return nil
+}
+
+// record records the outcome of an attempt allowed by allow.
+func (d *Dependency) record(ctx context.Context, o outcome) {
+	if d.breaker == nil {
+		return
+	}
+	if o == outcomeAbandoned {
+		d.breaker.Release()
+		return
+	}
+	from, to := d.breaker.Record(o == outcomeOK)
+	d.transitioned(ctx, from, to)
+}
+
+func (d *Dependency) transitioned(ctx context.Context, from, to State) {
+	if from == to {
+		return
+	}
+	trace.SpanFromContext(ctx).AddEvent("circuit breaker state changed",
+		trace.WithAttributes(
+			attribute.String("app.dependency", d.name),
+			attribute.String("app.breaker.from", from.String()),
+			attribute.String("app.breaker.to", to.String()),
+		))
+	if d.logger != nil {
+		d.logger.LogAttrs(ctx, slog.LevelWarn, "circuit breaker state changed",
+			slog.String("app.dependency", d.name),
+			slog.String("app.breaker.from", from.String()),
+			slog.String("app.breaker.to", to.String()),
+		)
+	}
+}
+
+// retry waits before retry attempt+1 of a call that failed with err. It
+// returns false if the caller gives up first.
+func (d *Dependency) retry(ctx context.Context, attempt int, err error) bool {
+	wait := d.backoff(attempt)
+	metrics.retries.Add(ctx, 1, d.attrs)
+	trace.SpanFromContext(ctx).AddEvent("retry",
+		trace.WithAttributes(
+			attribute.String("app.dependency", d.name),
+			attribute.Int("app.retry.attempt", attempt+1),
+			attribute.String("app.retry.backoff", wait.String()),
+			attribute.String("app.retry.cause", err.Error()),
+		))
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// backoff returns a random delay up to the initial backoff doubled for each
+// attempt made, capped at the max backoff ("full jitter").
+func (d *Dependency) backoff(attempt int) time.Duration {
+	ceiling := time.Duration(d.policy.InitialBackoff)
+	for i := 1; i < attempt && ceiling < time.Duration(d.policy.MaxBackoff); i++ {
+		ceiling *= 2
+	}
+	ceiling = min(ceiling, time.Duration(d.policy.MaxBackoff))
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+var metrics struct {
+	once       sync.Once
+	err        error
+	retries    metric.Int64Counter
+	rejections metric.Int64Counter
+
+	mu           sync.Mutex
+	dependencies []*Dependency
+}
+
+// register adds d to the dependencies whose breaker state is reported,
+// creating the instruments on first use.
+func register(d *Dependency) error {
+	metrics.once.Do(func() {
+		meter := otel.Meter("checkout")
+		var err error
+		if metrics.retries, err = meter.Int64Counter(
+			"app.checkout.dependency.retries",
+			metric.WithDescription("Number of retried calls to a dependency of checkout."),
+		); err != nil {
+			metrics.err = fmt.Errorf("failed to create retry counter: %w", err)
+			return
+		}
+		if metrics.rejections, err = meter.Int64Counter(
+			"app.checkout.breaker.rejections",
+			metric.WithDescription("Number of calls to a dependency of checkout rejected by its open circuit breaker."),
+		); err != nil {
+			metrics.err = fmt.Errorf("failed to create breaker rejection counter: %w", err)
+			return
+		}
+		if _, err = meter.Int64ObservableGauge(
+			"app.checkout.breaker.state",
+			metric.WithDescription("State of the circuit breaker of a dependency of checkout: closed (0), half-open (1) or open (2)."),
+			metric.WithInt64Callback(observeBreakers),
+		); err != nil {
+			metrics.err = fmt.Errorf("failed to create breaker state gauge: %w", err)
+		}
+	})
+	if metrics.err != nil {
+		return metrics.err
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	metrics.dependencies = append(metrics.dependencies, d)
+	return nil
+}
+
+func observeBreakers(_ context.Context, o metric.Int64Observer) error {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	for _, d := range metrics.dependencies {
+		if d.breaker != nil {
+			o.Observe(int64(d.breaker.State()), metric.WithAttributes(attribute.String("app.dependency", d.name)))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package resilience
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var testPolicy = Policy{
+	Timeout:          Duration(time.Second),
+	MaxAttempts:      3,
+	InitialBackoff:   Duration(time.Millisecond),
+	MaxBackoff:       Duration(5 * time.Millisecond),
+	BreakerThreshold: 4,
+	BreakerCooldown:  Duration(time.Hour),
+}
+
+func newTestDependency(t *testing.T) *Dependency {
+	t.Helper()
+	d, err := NewDependency(t.Name(), testPolicy, nil)
+	if err != nil {
+		t.Fatalf("NewDependency(): unexpected err=\"%v\"", err)
+	}
+	return d
+}
+
+// invoker fails with the given codes, then succeeds.
+func invoker(calls *int, fails ...codes.Code) grpc.UnaryInvoker {
+	return func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		*calls++
+		if _, ok := ctx.Deadline(); !ok {
+			return errors.New("attempt without deadline")
+		}
+		if *calls <= len(fails) {
+			return status.Error(fails[*calls-1], "failed")
+		}
+		return nil
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	const get, charge = "/test.Service/Get", "/test.Service/Charge"
+	tests := []struct {
+		name      string
+		method    string
+		fails     []codes.Code
+		wantCode  codes.Code
+		wantCalls int
+	}{
+		{"retried until success", get, []codes.Code{codes.Unavailable, codes.DeadlineExceeded}, codes.OK, 3},
+		{"gives up after max attempts", get, []codes.Code{codes.Unavailable, codes.Unavailable, codes.Unavailable}, codes.Unavailable, 3},
+		{"not idempotent", charge, []codes.Code{codes.Unavailable}, codes.Unavailable, 1},
+		{"client error", get, []codes.Code{codes.NotFound}, codes.NotFound, 1},
+		{"internal error", get, []codes.Code{codes.Internal}, codes.Internal, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := newTestDependency(t).UnaryClientInterceptor(get)
+			calls := 0
+			err := interceptor(context.Background(), tt.method, nil, nil, nil, invoker(&calls, tt.fails...))
+			if status.Code(err) != tt.wantCode || calls != tt.wantCalls {
+				t.Errorf("err=\"%v\" after %d calls, want %v after %d", err, calls, tt.wantCode, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestUnaryClientInterceptor_breaker(t *testing.T) {
+	d := newTestDependency(t)
+	interceptor := d.UnaryClientInterceptor()
+
+	calls := 0
+	fail := invoker(&calls, codes.Internal, codes.Internal, codes.Internal, codes.Internal, codes.Internal)
+	for range testPolicy.BreakerThreshold {
+		interceptor(context.Background(), "/test.Service/Charge", nil, nil, nil, fail)
+	}
+	if d.BreakerState() != StateOpen {
+		t.Fatalf("BreakerState() = %v, want open", d.BreakerState())
+	}
+
+	err := interceptor(context.Background(), "/test.Service/Charge", nil, nil, nil, fail)
+	if status.Code(err) != codes.Unavailable || calls != testPolicy.BreakerThreshold {
+		t.Errorf("call with open breaker: err=\"%v\" after %d calls, want Unavailable without a call", err, calls)
+	}
+}
+
+func TestRoundTripper(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if n := requests.Add(1); n < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	d := newTestDependency(t)
+	client := &http.Client{Transport: d.RoundTripper(http.DefaultTransport, func(r *http.Request) bool {
+		return r.URL.Path == "/get-quote"
+	})}
+
+	// The body is sent again with each attempt.
+	resp, err := client.Post(srv.URL+"/get-quote", "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Post(): unexpected err=\"%v\"", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "payload" || requests.Load() != 3 {
+		t.Errorf("Post() = %d %q after %d requests", resp.StatusCode, body, requests.Load())
+	}
+
+	requests.Store(0)
+	resp, err = client.Post(srv.URL+"/ship-order", "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("Post(): unexpected err=\"%v\"", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || requests.Load() != 1 {
+		t.Errorf("Post(not idempotent) = %d after %d requests, want 503 after 1", resp.StatusCode, requests.Load())
+	}
+}
+
+func TestRoundTripper_timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	policy := testPolicy
+	policy.Timeout = Duration(20 * time.Millisecond)
+	policy.MaxAttempts = 1
+	d, err := NewDependency(t.Name(), policy, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: d.RoundTripper(http.DefaultTransport, nil)}
+
+	start := time.Now()
+	if _, err := client.Get(srv.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Get(): expected err=\"%v\" got=\"%v\"", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Get() took %v", elapsed)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d := &Dependency{policy: Policy{InitialBackoff: Duration(100 * time.Millisecond), MaxBackoff: Duration(time.Second)}}
+	for attempt, ceiling := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		for range 100 {
+			if got := d.backoff(attempt + 1); got < 0 || got > ceiling {
+				t.Fatalf("backoff(%d) = %v, want at most %v", attempt+1, got, ceiling)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package resilience
+
+import (
+	"context"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor returns an interceptor applying the policy of d to
+// unary calls. Only the idempotent methods, given by full method name (e.g.
+// "/oteldemo.CartService/GetCart"), are retried. Calls rejected by the
+// breaker fail with Unavailable.
+func (d *Dependency) UnaryClientInterceptor(idempotent ...string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		attempts := d.attempts(slices.Contains(idempotent, method))
+		for attempt := 1; ; attempt++ {
+			if err := d.allow(ctx); err != nil {
+				return status.Error(codes.Unavailable, err.Error())
+			}
+
+			actx, cancel := d.attemptContext(ctx)
+			err := invoker(actx, method, req, reply, cc, opts...)
+			cancel()
+
+			o := grpcOutcome(ctx, err)
+			d.record(ctx, o)
+			if o != outcomeTransient || attempt >= attempts || !d.retry(ctx, attempt, err) {
+				return err
+			}
+		}
+	}
+}
+
+func grpcOutcome(ctx context.Context, err error) outcome {
+	if err == nil {
+		return outcomeOK
+	}
+	if ctx.Err() != nil {
+		return outcomeAbandoned
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return outcomeTransient
+	case codes.Internal, codes.DataLoss:
+		return outcomeFailed
+	}
+	return outcomeOK
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package resilience
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// RoundTripper returns a RoundTripper applying the policy of d to requests
+// sent with next. Requests for which idempotent returns true are retried;
+// idempotent may be nil to retry none. Their bodies must be replayable, as
+// the bodies of requests created by http.NewRequest from bytes are.
+func (d *Dependency) RoundTripper(next http.RoundTripper, idempotent func(*http.Request) bool) http.RoundTripper {
+	return &roundTripper{d: d, next: next, idempotent: idempotent}
+}
+
+type roundTripper struct {
+	d          *Dependency
+	next       http.RoundTripper
+	idempotent func(*http.Request) bool
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	retryable := rt.idempotent != nil && rt.idempotent(req) && (req.Body == nil || req.GetBody != nil)
+	attempts := rt.d.attempts(retryable)
+
+	for attempt := 1; ; attempt++ {
+		if err := rt.d.allow(ctx); err != nil {
+			return nil, err
+		}
+
+		actx, cancel := rt.d.attemptContext(ctx)
+		areq := req.Clone(actx)
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			areq.Body = body
+		}
+		resp, err := rt.next.RoundTrip(areq)
+
+		o := httpOutcome(ctx, resp, err)
+		rt.d.record(ctx, o)
+		if o != outcomeTransient || attempt >= attempts {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			// The attempt lasts until the body is read.
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		cause := err
+		if resp != nil {
+			cause = &statusError{code: resp.StatusCode}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		cancel()
+		if !rt.d.retry(ctx, attempt, cause) {
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func httpOutcome(ctx context.Context, resp *http.Response, err error) outcome {
+	if err != nil {
+		if ctx.Err() != nil {
+			return outcomeAbandoned
+		}
+		return outcomeTransient
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return outcomeTransient
+	}
+	if resp.StatusCode >= 500 {
+		return outcomeFailed
+	}
+	return outcomeOK
+}
+
+// statusError is the cause of a retry after an HTTP error status.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return http.StatusText(e.code)
+}
+
+// cancelBody cancels the context of an attempt once its body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
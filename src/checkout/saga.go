@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// compensation undoes the side effect of a completed saga stage.
+type compensation struct {
+	stage string
+	fn    func(ctx context.Context) error
+}
+
+// orderSaga records a compensation for every stage of PlaceOrder that has
+// completed, so a failure in a later stage can roll the earlier ones back.
+type orderSaga struct {
+	orderID       string
+	compensations []compensation
+}
+
+func newOrderSaga(orderID string) *orderSaga {
+	return &orderSaga{orderID: orderID}
+}
+
+// completed registers the compensation for a stage that finished successfully.
+func (s *orderSaga) completed(stage string, fn func(ctx context.Context) error) {
+	s.compensations = append(s.compensations, compensation{stage: stage, fn: fn})
+}
+
+// abort runs the registered compensations in reverse order. Each compensation
+// is traced as its own span. Compensations run even if the caller's context
+// was cancelled, since the customer must not be left with an orphaned charge.
+// The errors of the compensations that failed are returned.
+func (s *orderSaga) abort(ctx context.Context, failedStage string, cause error) error {
+	ctx = context.WithoutCancel(ctx)
+
+	var errs []error
+	for i := len(s.compensations) - 1; i >= 0; i-- {
+		c := s.compensations[i]
+
+		cctx, span := tracer.Start(ctx, fmt.Sprintf("compensate %s", c.stage),
+			trace.WithAttributes(
+				attribute.String("app.order.id", s.orderID),
+				attribute.String("app.saga.stage", c.stage),
+				attribute.String("app.saga.failed_stage", failedStage),
+				attribute.String("app.saga.cause", cause.Error()),
+			),
+		)
+
+		if err := c.fn(cctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to compensate %s: %w", c.stage, err))
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			logger.LogAttrs(
+				cctx,
+				slog.LevelError, "saga compensation failed",
+				slog.String("app.order.id", s.orderID),
+				slog.String("app.saga.stage", c.stage),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			logger.LogAttrs(
+				cctx,
+				slog.LevelInfo, "saga compensation completed",
+				slog.String("app.order.id", s.orderID),
+				slog.String("app.saga.stage", c.stage),
+			)
+		}
+		span.End()
+	}
+	s.compensations = nil
+	return errors.Join(errs...)
+}
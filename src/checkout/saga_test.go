@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/idempotency"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/kafka"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/pricing"
+)
+
+type fakeCart struct {
+	pb.CartServiceClient
+}
+
+func (fakeCart) GetCart(context.Context, *pb.GetCartRequest, ...grpc.CallOption) (*pb.Cart, error) {
+	return &pb.Cart{Items: []*pb.CartItem{{ProductId: "OLJCESPC7Z", Quantity: 1}}}, nil
+}
+
+func (fakeCart) EmptyCart(context.Context, *pb.EmptyCartRequest, ...grpc.CallOption) (*pb.Empty, error) {
+	return &pb.Empty{}, nil
+}
+
+type fakeCatalog struct {
+	pb.ProductCatalogServiceClient
+}
+
+func (fakeCatalog) GetProducts(_ context.Context, in *pb.GetProductsRequest, _ ...grpc.CallOption) (*pb.GetProductsResponse, error) {
+	resp := &pb.GetProductsResponse{}
+	for _, id := range in.GetIds() {
+		resp.Results = append(resp.Results, &pb.ProductResult{
+			Id:      id,
+			Found:   true,
+			Product: &pb.Product{Id: id, PriceUsd: &pb.Money{CurrencyCode: "USD", Units: 101, Nanos: 960000000}},
+		})
+	}
+	return resp, nil
+}
+
+type fakeCurrency struct {
+	pb.CurrencyServiceClient
+}
+
+func (fakeCurrency) GetSupportedCurrencies(context.Context, *pb.Empty, ...grpc.CallOption) (*pb.GetSupportedCurrenciesResponse, error) {
+	return &pb.GetSupportedCurrenciesResponse{CurrencyCodes: []string{"USD"}}, nil
+}
+
+// fakeShipping quotes orders and fails to ship them.
+type fakeShipping struct {
+	pb.ShippingServiceClient
+}
+
+func (fakeShipping) GetQuote(context.Context, *pb.GetQuoteRequest, ...grpc.CallOption) (*pb.GetQuoteResponse, error) {
+	return &pb.GetQuoteResponse{CostUsd: &pb.Money{CurrencyCode: "USD", Units: 8, Nanos: 990000000}}, nil
+}
+
+func (fakeShipping) ShipOrder(context.Context, *pb.ShipOrderRequest, ...grpc.CallOption) (*pb.ShipOrderResponse, error) {
+	return nil, status.Error(codes.Unavailable, "shipping is down")
+}
+
+// fakePayment records charges and refunds. Refunds fail with refundErr.
+type fakePayment struct {
+	pb.PaymentServiceClient
+	charges   []*pb.ChargeRequest
+	refunds   []*pb.RefundRequest
+	refundErr error
+}
+
+func (p *fakePayment) Charge(_ context.Context, in *pb.ChargeRequest, _ ...grpc.CallOption) (*pb.ChargeResponse, error) {
+	p.charges = append(p.charges, in)
+	return &pb.ChargeResponse{TransactionId: "txn-1"}, nil
+}
+
+func (p *fakePayment) Refund(_ context.Context, in *pb.RefundRequest, _ ...grpc.CallOption) (*pb.RefundResponse, error) {
+	p.refunds = append(p.refunds, in)
+	if p.refundErr != nil {
+		return nil, p.refundErr
+	}
+	return &pb.RefundResponse{RefundId: "refund-1"}, nil
+}
+
+func newTestCheckout(t *testing.T, payment *fakePayment) *checkout {
+	t.Helper()
+	tracer = noop.NewTracerProvider().Tracer("checkout")
+	logger = slog.New(slog.DiscardHandler)
+
+	rules, err := pricing.ParseRules([]byte(`{}`))
+	if err != nil {
+		t.Fatalf("ParseRules(): unexpected err=\"%v\"", err)
+	}
+	return &checkout{
+		cartSvcClient:           fakeCart{},
+		productCatalogSvcClient: fakeCatalog{},
+		currencySvcClient:       fakeCurrency{},
+		shippingSvcClient:       fakeShipping{},
+		paymentSvcClient:        payment,
+		idempotencyStore:        idempotency.NewMemoryStore(time.Hour),
+		pricingRules:            rules,
+		kafkaConfig:             &kafka.ProducerConfig{},
+	}
+}
+
+func testPlaceOrderRequest() *pb.PlaceOrderRequest {
+	return &pb.PlaceOrderRequest{
+		UserId:         "user",
+		UserCurrency:   "USD",
+		Email:          "someone@example.com",
+		IdempotencyKey: "key",
+		Address: &pb.Address{
+			StreetAddress: "1600 Amphitheatre Parkway",
+			City:          "Mountain View",
+			State:         "CA",
+			Country:       "United States",
+			ZipCode:       "94043",
+		},
+		CreditCard: &pb.CreditCardInfo{
+			CreditCardNumber:          "4432-8015-6152-0454",
+			CreditCardCvv:             672,
+			CreditCardExpirationYear:  2039,
+			CreditCardExpirationMonth: 1,
+		},
+	}
+}
+
+func TestPlaceOrder_refundsWhenShippingFails(t *testing.T) {
+	payment := &fakePayment{}
+	cs := newTestCheckout(t, payment)
+	ctx := context.Background()
+
+	_, err := cs.PlaceOrder(ctx, testPlaceOrderRequest())
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("PlaceOrder(): expected code %v got err=\"%v\"", codes.Unavailable, err)
+	}
+	if len(payment.refunds) != 1 {
+		t.Fatalf("PlaceOrder() issued %d refunds, want 1", len(payment.refunds))
+	}
+	refund := payment.refunds[0]
+	if refund.GetTransactionId() != "txn-1" || !proto.Equal(refund.GetAmount(), payment.charges[0].GetAmount()) {
+		t.Errorf("PlaceOrder() refunded %v of %q, want %v of %q",
+			refund.GetAmount(), refund.GetTransactionId(), payment.charges[0].GetAmount(), "txn-1")
+	}
+
+	// The order was rolled back, so it can be retried with the same key.
+	if _, err := cs.PlaceOrder(ctx, testPlaceOrderRequest()); status.Code(err) != codes.Unavailable {
+		t.Errorf("PlaceOrder() retry: expected code %v got err=\"%v\"", codes.Unavailable, err)
+	}
+	if len(payment.charges) != 2 {
+		t.Errorf("PlaceOrder() retry made %d charges in all, want 2", len(payment.charges))
+	}
+}
+
+func TestPlaceOrder_keepsKeyWhenRefundFails(t *testing.T) {
+	payment := &fakePayment{refundErr: errors.New("payment is down")}
+	cs := newTestCheckout(t, payment)
+	ctx := context.Background()
+
+	_, err := cs.PlaceOrder(ctx, testPlaceOrderRequest())
+	if status.Code(err) != codes.Unavailable {
+		t.Fatalf("PlaceOrder(): expected code %v got err=\"%v\"", codes.Unavailable, err)
+	}
+
+	// The charge was not refunded, so a retry must not charge again.
+	if _, err := cs.PlaceOrder(ctx, testPlaceOrderRequest()); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("PlaceOrder() retry: expected code %v got err=\"%v\"", codes.FailedPrecondition, err)
+	}
+	if len(payment.charges) != 1 {
+		t.Errorf("PlaceOrder() retry made %d charges in all, want 1", len(payment.charges))
+	}
+}
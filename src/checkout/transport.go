@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/protohttp"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/resilience"
+)
+
+// Transports of the dependencies that have both.
+const (
+	transportHTTP = "http"
+	transportGRPC = "grpc"
+)
+
+// transport returns the transport of a dependency, from e.g.
+// CHECKOUT_SHIPPING_TRANSPORT. It is HTTP/JSON by default.
+func transport(dependency string) string {
+	key := "CHECKOUT_" + strings.ToUpper(dependency) + "_TRANSPORT"
+	switch t := os.Getenv(key); t {
+	case "", transportHTTP:
+		return transportHTTP
+	case transportGRPC:
+		return transportGRPC
+	default:
+		logger.Warn(fmt.Sprintf("unknown %s %q, using %s", key, t, transportHTTP))
+		return transportHTTP
+	}
+}
+
+// newProtoHTTPClient returns a client of the HTTP/JSON service at baseURL,
+// applying the policy of dep. Each attempt of a call is traced separately.
+func newProtoHTTPClient(baseURL string, dep *resilience.Dependency) *protohttp.Client {
+	return &protohttp.Client{
+		Service: dep.Name(),
+		BaseURL: baseURL,
+		HTTPClient: &http.Client{
+			Transport: dep.RoundTripper(otelhttp.NewTransport(http.DefaultTransport), idempotentRequests[dep.Name()]),
+		},
+	}
+}
+
+// httpShippingClient calls the shipping service over HTTP/JSON. The call
+// options are ignored.
+type httpShippingClient struct {
+	c *protohttp.Client
+}
+
+func (s *httpShippingClient) GetQuote(ctx context.Context, in *pb.GetQuoteRequest, _ ...grpc.CallOption) (*pb.GetQuoteResponse, error) {
+	out := new(pb.GetQuoteResponse)
+	if err := s.c.Call(ctx, "/get-quote", in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *httpShippingClient) ShipOrder(ctx context.Context, in *pb.ShipOrderRequest, _ ...grpc.CallOption) (*pb.ShipOrderResponse, error) {
+	out := new(pb.ShipOrderResponse)
+	if err := s.c.Call(ctx, "/ship-order", in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// httpEmailClient calls the email service over HTTP/JSON. The call options
+// are ignored.
+type httpEmailClient struct {
+	c *protohttp.Client
+}
+
+func (e *httpEmailClient) SendOrderConfirmation(ctx context.Context, in *pb.SendOrderConfirmationRequest, _ ...grpc.CallOption) (*pb.Empty, error) {
+	out := new(pb.Empty)
+	if err := e.c.Call(ctx, "/send_order_confirmation", in, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
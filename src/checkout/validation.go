@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+	"github.com/open-telemetry/opentelemetry-demo/src/checkout/validation"
+)
+
+// supportedCurrenciesTTL is how long the supported currencies are cached.
+const supportedCurrenciesTTL = 5 * time.Minute
+
+// currencyCache caches the currencies supported by the currency service. The
+// zero value is empty.
+type currencyCache struct {
+	mu      sync.Mutex
+	codes   []string
+	fetched time.Time
+}
+
+// supportedCurrencies returns the currencies supported by the currency
+// service, or nil if it cannot tell.
+func (cs *checkout) supportedCurrencies(ctx context.Context) []string {
+	c := &cs.currencies
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.codes != nil && time.Since(c.fetched) < supportedCurrenciesTTL {
+		return c.codes
+	}
+
+	resp, err := cs.currencySvcClient.GetSupportedCurrencies(ctx, &pb.Empty{})
+	if err != nil {
+		// The codes fetched last are better than none.
+		logger.Warn(fmt.Sprintf("failed to get supported currencies: %v", err))
+		return c.codes
+	}
+	c.codes, c.fetched = resp.GetCurrencyCodes(), time.Now()
+	return c.codes
+}
+
+// validatePlaceOrder checks req before anything is done with it, returning
+// a *validation.Error for invalid requests.
+func (cs *checkout) validatePlaceOrder(ctx context.Context, req *pb.PlaceOrderRequest) error {
+	err := validation.PlaceOrder(req, cs.supportedCurrencies(ctx), time.Now())
+
+	var invalid *validation.Error
+	if errors.As(err, &invalid) {
+		fields := make([]string, len(invalid.Violations))
+		for i, v := range invalid.Violations {
+			fields[i] = v.GetField()
+		}
+		trace.SpanFromContext(ctx).SetAttributes(attribute.StringSlice("app.order.invalid_fields", fields))
+		logger.LogAttrs(
+			ctx,
+			slog.LevelWarn, "invalid order request",
+			slog.Any("app.order.invalid_fields", fields),
+		)
+	}
+	return err
+}
@@ -0,0 +1,184 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package validation checks requests before checkout acts on them, so that
+// invalid input is reported per field instead of failing in a downstream
+// service.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+// Reasons of field violations, for clients to tell them apart.
+const (
+	ReasonRequired            = "REQUIRED"
+	ReasonInvalidFormat       = "INVALID_FORMAT"
+	ReasonUnsupportedCurrency = "UNSUPPORTED_CURRENCY"
+	ReasonInvalidCardNumber   = "INVALID_CARD_NUMBER"
+	ReasonCardExpired         = "CARD_EXPIRED"
+)
+
+// Error is an invalid request. It is an InvalidArgument status with a
+// BadRequest detail listing the violations, so it can be returned from a
+// gRPC handler as is.
+type Error struct {
+	Violations []*errdetails.BadRequest_FieldViolation
+}
+
+func (e *Error) Error() string {
+	fields := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		fields[i] = v.GetField() + ": " + v.GetDescription()
+	}
+	return "invalid request: " + strings.Join(fields, "; ")
+}
+
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: e.Violations}); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// violations accumulates the field violations of a request.
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field, reason, format string, args ...any) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Reason:      reason,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *violations) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, ReasonRequired, "must not be empty")
+		return false
+	}
+	return true
+}
+
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &Error{Violations: v}
+}
+
+// PlaceOrder checks req, returning an *Error listing every invalid field.
+// The currency must be one of currencies, unless currencies is nil, in
+// which case only its format is checked. Cards expiring before now are
+// rejected.
+func PlaceOrder(req *pb.PlaceOrderRequest, currencies []string, now time.Time) error {
+	var v violations
+	v.required("user_id", req.GetUserId())
+	checkCurrency(&v, "user_currency", req.GetUserCurrency(), currencies)
+	checkEmail(&v, "email", req.GetEmail())
+	checkAddress(&v, "address", req.GetAddress())
+	checkCreditCard(&v, "credit_card", req.GetCreditCard(), now)
+	return v.err()
+}
+
+func checkCurrency(v *violations, field, code string, currencies []string) {
+	if !v.required(field, code) {
+		return
+	}
+	if currencies == nil {
+		if len(code) != 3 || strings.ToUpper(code) != code {
+			v.add(field, ReasonInvalidFormat, "%q is not an ISO 4217 currency code", code)
+		}
+		return
+	}
+	if !slices.Contains(currencies, code) {
+		v.add(field, ReasonUnsupportedCurrency, "%q is not a supported currency", code)
+	}
+}
+
+func checkEmail(v *violations, field, email string) {
+	if !v.required(field, email) {
+		return
+	}
+	// Display names and comments are valid in addresses, but not here.
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		v.add(field, ReasonInvalidFormat, "%q is not an email address", email)
+	}
+}
+
+func checkAddress(v *violations, field string, a *pb.Address) {
+	if a == nil {
+		v.add(field, ReasonRequired, "must be set")
+		return
+	}
+	// The state is optional, as not every country has them.
+	v.required(field+".street_address", a.GetStreetAddress())
+	v.required(field+".city", a.GetCity())
+	v.required(field+".country", a.GetCountry())
+	v.required(field+".zip_code", a.GetZipCode())
+}
+
+func checkCreditCard(v *violations, field string, c *pb.CreditCardInfo, now time.Time) {
+	if c == nil {
+		v.add(field, ReasonRequired, "must be set")
+		return
+	}
+
+	if number := c.GetCreditCardNumber(); v.required(field+".credit_card_number", number) {
+		// Groups of digits may be separated by spaces or dashes.
+		digits := strings.NewReplacer(" ", "", "-", "").Replace(number)
+		switch {
+		case len(digits) < 12 || len(digits) > 19 || strings.Trim(digits, "0123456789") != "":
+			v.add(field+".credit_card_number", ReasonInvalidFormat, "must be 12 to 19 digits")
+		case !Luhn(digits):
+			v.add(field+".credit_card_number", ReasonInvalidCardNumber, "fails the Luhn check")
+		}
+	}
+
+	// The CVV is an int32, so a missing one is 0.
+	if cvv := c.GetCreditCardCvv(); cvv <= 0 || cvv > 9999 {
+		v.add(field+".credit_card_cvv", ReasonInvalidFormat, "must be 3 or 4 digits")
+	}
+
+	// A card is valid through the end of its expiration month.
+	year, month := c.GetCreditCardExpirationYear(), c.GetCreditCardExpirationMonth()
+	switch {
+	case month < 1 || month > 12:
+		v.add(field+".credit_card_expiration_month", ReasonInvalidFormat, "must be from 1 to 12")
+	case year < int32(now.Year()):
+		v.add(field+".credit_card_expiration_year", ReasonCardExpired, "the card expired in %d", year)
+	case year == int32(now.Year()) && month < int32(now.Month()):
+		v.add(field+".credit_card_expiration_month", ReasonCardExpired, "the card expired in %d/%d", month, year)
+	}
+}
+
+// Luhn reports whether digits, a string of decimal digits, has a valid Luhn
+// check digit.
+func Luhn(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/open-telemetry/opentelemetry-demo/src/checkout/genproto/oteldemo"
+)
+
+var now = time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+func validRequest() *pb.PlaceOrderRequest {
+	return &pb.PlaceOrderRequest{
+		UserId:       "user",
+		UserCurrency: "USD",
+		Email:        "larry_sergei@example.com",
+		Address: &pb.Address{
+			StreetAddress: "1600 Amphitheatre Parkway",
+			City:          "Mountain View",
+			State:         "CA",
+			Country:       "United States",
+			ZipCode:       "94043",
+		},
+		CreditCard: &pb.CreditCardInfo{
+			CreditCardNumber:          "4432-8015-6152-0454",
+			CreditCardCvv:             672,
+			CreditCardExpirationYear:  2039,
+			CreditCardExpirationMonth: 1,
+		},
+	}
+}
+
+// fields returns the fields and reasons of the violations of err.
+func fields(t *testing.T, err error) map[string]string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var invalid *Error
+	if !errors.As(err, &invalid) {
+		t.Fatalf("PlaceOrder(): err=\"%v\" is not an *Error", err)
+	}
+	got := make(map[string]string)
+	for _, v := range invalid.Violations {
+		got[v.GetField()] = v.GetReason()
+	}
+	return got
+}
+
+func TestPlaceOrder(t *testing.T) {
+	currencies := []string{"EUR", "USD"}
+	tests := []struct {
+		name       string
+		change     func(r *pb.PlaceOrderRequest)
+		currencies []string
+		want       map[string]string
+	}{
+		{"valid", func(r *pb.PlaceOrderRequest) {}, currencies, nil},
+		{"valid without currencies", func(r *pb.PlaceOrderRequest) {}, nil, nil},
+		{"valid without state", func(r *pb.PlaceOrderRequest) { r.Address.State = "" }, currencies, nil},
+		{"valid card with spaces", func(r *pb.PlaceOrderRequest) { r.CreditCard.CreditCardNumber = "4532 4211 7434 1278" }, currencies, nil},
+		{"expires this month", func(r *pb.PlaceOrderRequest) {
+			r.CreditCard.CreditCardExpirationYear, r.CreditCard.CreditCardExpirationMonth = 2026, 3
+		}, currencies, nil},
+		{"empty", func(r *pb.PlaceOrderRequest) { proto.Reset(r) }, currencies, map[string]string{
+			"user_id":       ReasonRequired,
+			"user_currency": ReasonRequired,
+			"email":         ReasonRequired,
+			"address":       ReasonRequired,
+			"credit_card":   ReasonRequired,
+		}},
+		{"unsupported currency", func(r *pb.PlaceOrderRequest) { r.UserCurrency = "JPY" }, currencies, map[string]string{
+			"user_currency": ReasonUnsupportedCurrency,
+		}},
+		{"malformed currency", func(r *pb.PlaceOrderRequest) { r.UserCurrency = "usd" }, nil, map[string]string{
+			"user_currency": ReasonInvalidFormat,
+		}},
+		{"malformed email", func(r *pb.PlaceOrderRequest) { r.Email = "larry at example.com" }, currencies, map[string]string{
+			"email": ReasonInvalidFormat,
+		}},
+		{"email with name", func(r *pb.PlaceOrderRequest) { r.Email = "Larry <larry@example.com>" }, currencies, map[string]string{
+			"email": ReasonInvalidFormat,
+		}},
+		{"incomplete address", func(r *pb.PlaceOrderRequest) { r.Address.City, r.Address.ZipCode = " ", "" }, currencies, map[string]string{
+			"address.city":     ReasonRequired,
+			"address.zip_code": ReasonRequired,
+		}},
+		{"luhn", func(r *pb.PlaceOrderRequest) { r.CreditCard.CreditCardNumber = "4432-8015-6152-0455" }, currencies, map[string]string{
+			"credit_card.credit_card_number": ReasonInvalidCardNumber,
+		}},
+		{"short card number", func(r *pb.PlaceOrderRequest) { r.CreditCard.CreditCardNumber = "4242" }, currencies, map[string]string{
+			"credit_card.credit_card_number": ReasonInvalidFormat,
+		}},
+		{"card number with letters", func(r *pb.PlaceOrderRequest) { r.CreditCard.CreditCardNumber = "4432-8015-6152-04a4" }, currencies, map[string]string{
+			"credit_card.credit_card_number": ReasonInvalidFormat,
+		}},
+		{"missing cvv", func(r *pb.PlaceOrderRequest) { r.CreditCard.CreditCardCvv = 0 }, currencies, map[string]string{
+			"credit_card.credit_card_cvv": ReasonInvalidFormat,
+		}},
+		{"invalid month", func(r *pb.PlaceOrderRequest) { r.CreditCard.CreditCardExpirationMonth = 13 }, currencies, map[string]string{
+			"credit_card.credit_card_expiration_month": ReasonInvalidFormat,
+		}},
+		{"expired last month", func(r *pb.PlaceOrderRequest) {
+			r.CreditCard.CreditCardExpirationYear, r.CreditCard.CreditCardExpirationMonth = 2026, 2
+		}, currencies, map[string]string{
+			"credit_card.credit_card_expiration_month": ReasonCardExpired,
+		}},
+		{"expired last year", func(r *pb.PlaceOrderRequest) { r.CreditCard.CreditCardExpirationYear = 2025 }, currencies, map[string]string{
+			"credit_card.credit_card_expiration_year": ReasonCardExpired,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := validRequest()
+			tt.change(req)
+			got := fields(t, PlaceOrder(req, tt.currencies, now))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlaceOrder() violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError_GRPCStatus(t *testing.T) {
+	req := validRequest()
+	req.Email = "nope"
+	err := PlaceOrder(req, nil, now)
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("status.FromError() = %v, %t, want InvalidArgument", st, ok)
+	}
+	details := st.Details()
+	if len(details) != 1 {
+		t.Fatalf("Details() = %v, want a BadRequest", details)
+	}
+	br, ok := details[0].(*errdetails.BadRequest)
+	if !ok || len(br.GetFieldViolations()) != 1 || br.GetFieldViolations()[0].GetField() != "email" {
+		t.Errorf("Details() = %v, want an email violation", details)
+	}
+}
+
+func TestLuhn(t *testing.T) {
+	for _, digits := range []string{"4432801561520454", "4532617827991951", "79927398713", "0"} {
+		if !Luhn(digits) {
+			t.Errorf("Luhn(%q) = false, want true", digits)
+		}
+	}
+	for _, digits := range []string{"4432801561520455", "79927398710", "1"} {
+		if Luhn(digits) {
+			t.Errorf("Luhn(%q) = true, want false", digits)
+		}
+	}
+}
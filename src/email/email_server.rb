@@ -56,7 +56,7 @@ def send_email(data)
     memory_leak_multiplier = client.fetch_number_value(flag_key: "emailMemoryLeak", default_value: 0)
 
     # To speed up the memory leak we create a long email body
-    confirmation_content = erb(:confirmation, locals: { order: data.order })
+    confirmation_content = erb(:confirmation, locals: { order: data.order, total: data.total })
     whitespace_length = [0, confirmation_content.length * (memory_leak_multiplier-1)].max
 
     Pony.mail(
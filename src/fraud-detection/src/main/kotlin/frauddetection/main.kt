@@ -5,7 +5,9 @@
 
 package frauddetection
 
+import com.google.protobuf.util.JsonFormat
 import org.apache.kafka.clients.consumer.ConsumerConfig.*
+import org.apache.kafka.clients.consumer.ConsumerRecord
 import org.apache.kafka.clients.consumer.KafkaConsumer
 import org.apache.kafka.common.serialization.ByteArrayDeserializer
 import org.apache.kafka.common.serialization.StringDeserializer
@@ -61,7 +63,7 @@ fun main() {
                         logger.info("FeatureFlag 'kafkaQueueProblems' is enabled, sleeping 1 second")
                         Thread.sleep(1000)
                     }
-                    val orders = OrderResult.parseFrom(record.value())
+                    val orders = parseOrder(record)
                     logger.info("Consumed record with orderId: ${orders.orderId}, and updated total count to: $newCount")
                     newCount
                 }
@@ -69,6 +71,23 @@ fun main() {
     }
 }
 
+/**
+* Parses the order of an order event, which is protobuf, or JSON if its
+* content-type header says so. Events without the header are protobuf.
+*
+* @param record The order event.
+* @return The order.
+*/
+fun parseOrder(record: ConsumerRecord<String, ByteArray>): OrderResult {
+    val contentType = record.headers().lastHeader("content-type")?.value()?.let { String(it, Charsets.UTF_8) }
+    if (contentType != null && contentType.startsWith("application/json", ignoreCase = true)) {
+        val builder = OrderResult.newBuilder()
+        JsonFormat.parser().ignoringUnknownFields().merge(String(record.value(), Charsets.UTF_8), builder)
+        return builder.build()
+    }
+    return OrderResult.parseFrom(record.value())
+}
+
 /**
 * Retrieves the status of a feature flag from the Feature Flag service.
 *
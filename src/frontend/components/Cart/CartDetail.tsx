@@ -3,6 +3,7 @@
 
 import { useRouter } from 'next/router';
 import { useCallback } from 'react';
+import { v4 } from 'uuid';
 import CartItems from '../CartItems';
 import CheckoutForm from '../CheckoutForm';
 import { IFormData } from '../CheckoutForm/CheckoutForm';
@@ -52,6 +53,8 @@ const CartDetail = () => {
           creditCardExpirationYear,
           creditCardNumber,
         },
+        idempotencyKey: v4(),
+        couponCodes: [],
       });
 
       push({
@@ -2,7 +2,7 @@
 // SPDX-License-Identifier: Apache-2.0
 
 import { ChannelCredentials } from '@grpc/grpc-js';
-import { ListProductsResponse, Product, ProductCatalogServiceClient } from '../../protos/demo';
+import { ListProductsRequest, ListProductsResponse, Product, ProductCatalogServiceClient } from '../../protos/demo';
 
 const { PRODUCT_CATALOG_ADDR = '' } = process.env;
 
@@ -10,8 +10,10 @@ const client = new ProductCatalogServiceClient(PRODUCT_CATALOG_ADDR, ChannelCred
 
 const ProductCatalogGateway = () => ({
   listProducts() {
+    // An empty request returns all products in a single page.
+    const request = ListProductsRequest.create();
     return new Promise<ListProductsResponse>((resolve, reject) =>
-      client.listProducts({}, (error, response) => (error ? reject(error) : resolve(response)))
+      client.listProducts(request, (error, response) => (error ? reject(error) : resolve(response)))
     );
   },
   getProduct(id: string) {
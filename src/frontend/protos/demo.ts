@@ -21,6 +21,68 @@ import {
 
 export const protobufPackage = "oteldemo";
 
+export enum ProductSortOrder {
+  /**
+   * PRODUCT_SORT_ORDER_UNSPECIFIED - PRODUCT_SORT_ORDER_NAME_ASC for ListProducts, and
+   * PRODUCT_SORT_ORDER_RELEVANCE for SearchProducts.
+   */
+  PRODUCT_SORT_ORDER_UNSPECIFIED = 0,
+  PRODUCT_SORT_ORDER_NAME_ASC = 1,
+  PRODUCT_SORT_ORDER_NAME_DESC = 2,
+  PRODUCT_SORT_ORDER_PRICE_ASC = 3,
+  PRODUCT_SORT_ORDER_PRICE_DESC = 4,
+  /** PRODUCT_SORT_ORDER_RELEVANCE - Best search matches first. Only valid for SearchProducts. */
+  PRODUCT_SORT_ORDER_RELEVANCE = 5,
+  UNRECOGNIZED = -1,
+}
+
+export function productSortOrderFromJSON(object: any): ProductSortOrder {
+  switch (object) {
+    case 0:
+    case "PRODUCT_SORT_ORDER_UNSPECIFIED":
+      return ProductSortOrder.PRODUCT_SORT_ORDER_UNSPECIFIED;
+    case 1:
+    case "PRODUCT_SORT_ORDER_NAME_ASC":
+      return ProductSortOrder.PRODUCT_SORT_ORDER_NAME_ASC;
+    case 2:
+    case "PRODUCT_SORT_ORDER_NAME_DESC":
+      return ProductSortOrder.PRODUCT_SORT_ORDER_NAME_DESC;
+    case 3:
+    case "PRODUCT_SORT_ORDER_PRICE_ASC":
+      return ProductSortOrder.PRODUCT_SORT_ORDER_PRICE_ASC;
+    case 4:
+    case "PRODUCT_SORT_ORDER_PRICE_DESC":
+      return ProductSortOrder.PRODUCT_SORT_ORDER_PRICE_DESC;
+    case 5:
+    case "PRODUCT_SORT_ORDER_RELEVANCE":
+      return ProductSortOrder.PRODUCT_SORT_ORDER_RELEVANCE;
+    case -1:
+    case "UNRECOGNIZED":
+    default:
+      return ProductSortOrder.UNRECOGNIZED;
+  }
+}
+
+export function productSortOrderToJSON(object: ProductSortOrder): string {
+  switch (object) {
+    case ProductSortOrder.PRODUCT_SORT_ORDER_UNSPECIFIED:
+      return "PRODUCT_SORT_ORDER_UNSPECIFIED";
+    case ProductSortOrder.PRODUCT_SORT_ORDER_NAME_ASC:
+      return "PRODUCT_SORT_ORDER_NAME_ASC";
+    case ProductSortOrder.PRODUCT_SORT_ORDER_NAME_DESC:
+      return "PRODUCT_SORT_ORDER_NAME_DESC";
+    case ProductSortOrder.PRODUCT_SORT_ORDER_PRICE_ASC:
+      return "PRODUCT_SORT_ORDER_PRICE_ASC";
+    case ProductSortOrder.PRODUCT_SORT_ORDER_PRICE_DESC:
+      return "PRODUCT_SORT_ORDER_PRICE_DESC";
+    case ProductSortOrder.PRODUCT_SORT_ORDER_RELEVANCE:
+      return "PRODUCT_SORT_ORDER_RELEVANCE";
+    case ProductSortOrder.UNRECOGNIZED:
+    default:
+      return "UNRECOGNIZED";
+  }
+}
+
 export interface CartItem {
   productId: string;
   quantity: number;
@@ -71,20 +133,92 @@ export interface Product {
   categories: string[];
 }
 
+/**
+ * ProductFilter restricts the products returned by ListProducts and
+ * SearchProducts. Unset fields do not filter.
+ */
+export interface ProductFilter {
+  /** Only return products in at least one of these categories. */
+  categories: string[];
+  /**
+   * Only return products priced at or above min_price_usd, and at or below
+   * max_price_usd. Both must be in USD.
+   */
+  minPriceUsd: Money | undefined;
+  maxPriceUsd: Money | undefined;
+}
+
+export interface ListProductsRequest {
+  /**
+   * Maximum number of products to return. If zero, all matching products
+   * are returned. Larger values are capped by the server.
+   */
+  pageSize: number;
+  /**
+   * next_page_token of a previous response, to fetch the following page.
+   * The filter and sort order must be the same as in that request.
+   */
+  pageToken: string;
+  filter: ProductFilter | undefined;
+  sort: ProductSortOrder;
+}
+
 export interface ListProductsResponse {
   products: Product[];
+  /** Token for the next page, empty on the last page. */
+  nextPageToken: string;
 }
 
 export interface GetProductRequest {
   id: string;
 }
 
+export interface GetProductsRequest {
+  ids: string[];
+}
+
+export interface GetProductsResponse {
+  /** One result per requested id, in request order. */
+  results: ProductResult[];
+}
+
+export interface ProductResult {
+  id: string;
+  /** Whether a product with this id exists. If not, product is unset. */
+  found: boolean;
+  product: Product | undefined;
+}
+
 export interface SearchProductsRequest {
+  /**
+   * Products match if their name or description contains all words of the
+   * query, after stemming and ignoring English stop words.
+   */
   query: string;
+  /** Paging, filtering and ordering, as in ListProductsRequest. */
+  pageSize: number;
+  pageToken: string;
+  filter: ProductFilter | undefined;
+  sort: ProductSortOrder;
 }
 
 export interface SearchProductsResponse {
   results: Product[];
+  /** Token for the next page, empty on the last page. */
+  nextPageToken: string;
+  /** One hit per result, in the same order. */
+  hits: SearchHit[];
+}
+
+export interface SearchHit {
+  productId: string;
+  /** Relevance of the product to the query. Higher is better. */
+  score: number;
+  /**
+   * Excerpt of the description with the matched words wrapped in
+   * <b></b>.
+   */
+  snippet: string;
 }
 
 export interface GetQuoteRequest {
@@ -162,6 +296,20 @@ export interface ChargeResponse {
   transactionId: string;
 }
 
+export interface RefundRequest {
+  /** The transaction_id returned by a previous Charge. */
+  transactionId: string;
+  amount:
+    | Money
+    | undefined;
+  /** Human readable reason, e.g. the failed checkout stage. */
+  reason: string;
+}
+
+export interface RefundResponse {
+  refundId: string;
+}
+
 export interface OrderItem {
   item: CartItem | undefined;
   cost: Money | undefined;
@@ -173,11 +321,50 @@ export interface OrderResult {
   shippingCost: Money | undefined;
   shippingAddress: Address | undefined;
   items: OrderItem[];
+  /** How the order was priced, in the user currency. */
+  pricing: OrderPricing | undefined;
+}
+
+/** OrderPricing breaks the total of an order down. */
+export interface OrderPricing {
+  /** Sum of the item costs times their quantities. */
+  subtotal:
+    | Money
+    | undefined;
+  /** Sum of the item and order discounts, as a positive amount. */
+  discount:
+    | Money
+    | undefined;
+  /** Shipping cost after shipping discounts. */
+  shipping: Money | undefined;
+  tax:
+    | Money
+    | undefined;
+  /** Subtotal - discount + shipping + tax. */
+  total: Money | undefined;
+  adjustments: PriceAdjustment[];
+}
+
+/** PriceAdjustment is a discount or tax applied to an order. */
+export interface PriceAdjustment {
+  /** "promotion", "coupon" or "tax". */
+  kind: string;
+  /** Promotion id, coupon code or tax region. */
+  id: string;
+  description: string;
+  /** Set if the adjustment applies to a single item. */
+  productId: string;
+  /** Negative for discounts, positive for tax. */
+  amount: Money | undefined;
 }
 
 export interface SendOrderConfirmationRequest {
   email: string;
-  order: OrderResult | undefined;
+  order:
+    | OrderResult
+    | undefined;
+  /** Order total formatted for display, e.g. "$12.50". */
+  total: string;
 }
 
 export interface PlaceOrderRequest {
@@ -185,7 +372,20 @@ export interface PlaceOrderRequest {
   userCurrency: string;
   address: Address | undefined;
   email: string;
-  creditCard: CreditCardInfo | undefined;
+  creditCard:
+    | CreditCardInfo
+    | undefined;
+  /**
+   * Client-supplied key that makes retries of the same order safe. When
+   * set (or sent as the "idempotency-key" request metadata), a replayed
+   * request returns the original order instead of placing a new one.
+   */
+  idempotencyKey: string;
+  /**
+   * Coupons to apply to the order. Unknown codes fail the order with
+   * INVALID_ARGUMENT.
+   */
+  couponCodes: string[];
 }
 
 export interface PlaceOrderResponse {
@@ -925,8 +1125,216 @@ export const Product: MessageFns<Product> = {
   },
 };
 
+function createBaseProductFilter(): ProductFilter {
+  return { categories: [], minPriceUsd: undefined, maxPriceUsd: undefined };
+}
+
+export const ProductFilter: MessageFns<ProductFilter> = {
+  encode(message: ProductFilter, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    for (const v of message.categories) {
+      writer.uint32(10).string(v!);
+    }
+    if (message.minPriceUsd !== undefined) {
+      Money.encode(message.minPriceUsd, writer.uint32(18).fork()).join();
+    }
+    if (message.maxPriceUsd !== undefined) {
+      Money.encode(message.maxPriceUsd, writer.uint32(26).fork()).join();
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): ProductFilter {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseProductFilter();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.categories.push(reader.string());
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.minPriceUsd = Money.decode(reader, reader.uint32());
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.maxPriceUsd = Money.decode(reader, reader.uint32());
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): ProductFilter {
+    return {
+      categories: globalThis.Array.isArray(object?.categories)
+        ? object.categories.map((e: any) => globalThis.String(e))
+        : [],
+      minPriceUsd: isSet(object.minPriceUsd) ? Money.fromJSON(object.minPriceUsd) : undefined,
+      maxPriceUsd: isSet(object.maxPriceUsd) ? Money.fromJSON(object.maxPriceUsd) : undefined,
+    };
+  },
+
+  toJSON(message: ProductFilter): unknown {
+    const obj: any = {};
+    if (message.categories?.length) {
+      obj.categories = message.categories;
+    }
+    if (message.minPriceUsd !== undefined) {
+      obj.minPriceUsd = Money.toJSON(message.minPriceUsd);
+    }
+    if (message.maxPriceUsd !== undefined) {
+      obj.maxPriceUsd = Money.toJSON(message.maxPriceUsd);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<ProductFilter>, I>>(base?: I): ProductFilter {
+    return ProductFilter.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<ProductFilter>, I>>(object: I): ProductFilter {
+    const message = createBaseProductFilter();
+    message.categories = object.categories?.map((e) => e) || [];
+    message.minPriceUsd = (object.minPriceUsd !== undefined && object.minPriceUsd !== null)
+      ? Money.fromPartial(object.minPriceUsd)
+      : undefined;
+    message.maxPriceUsd = (object.maxPriceUsd !== undefined && object.maxPriceUsd !== null)
+      ? Money.fromPartial(object.maxPriceUsd)
+      : undefined;
+    return message;
+  },
+};
+
+function createBaseListProductsRequest(): ListProductsRequest {
+  return { pageSize: 0, pageToken: "", filter: undefined, sort: 0 };
+}
+
+export const ListProductsRequest: MessageFns<ListProductsRequest> = {
+  encode(message: ListProductsRequest, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.pageSize !== 0) {
+      writer.uint32(8).int32(message.pageSize);
+    }
+    if (message.pageToken !== "") {
+      writer.uint32(18).string(message.pageToken);
+    }
+    if (message.filter !== undefined) {
+      ProductFilter.encode(message.filter, writer.uint32(26).fork()).join();
+    }
+    if (message.sort !== 0) {
+      writer.uint32(32).int32(message.sort);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): ListProductsRequest {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseListProductsRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 8) {
+            break;
+          }
+
+          message.pageSize = reader.int32();
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.pageToken = reader.string();
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.filter = ProductFilter.decode(reader, reader.uint32());
+          continue;
+        }
+        case 4: {
+          if (tag !== 32) {
+            break;
+          }
+
+          message.sort = reader.int32() as any;
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): ListProductsRequest {
+    return {
+      pageSize: isSet(object.pageSize) ? globalThis.Number(object.pageSize) : 0,
+      pageToken: isSet(object.pageToken) ? globalThis.String(object.pageToken) : "",
+      filter: isSet(object.filter) ? ProductFilter.fromJSON(object.filter) : undefined,
+      sort: isSet(object.sort) ? productSortOrderFromJSON(object.sort) : 0,
+    };
+  },
+
+  toJSON(message: ListProductsRequest): unknown {
+    const obj: any = {};
+    if (message.pageSize !== 0) {
+      obj.pageSize = Math.round(message.pageSize);
+    }
+    if (message.pageToken !== "") {
+      obj.pageToken = message.pageToken;
+    }
+    if (message.filter !== undefined) {
+      obj.filter = ProductFilter.toJSON(message.filter);
+    }
+    if (message.sort !== 0) {
+      obj.sort = productSortOrderToJSON(message.sort);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<ListProductsRequest>, I>>(base?: I): ListProductsRequest {
+    return ListProductsRequest.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<ListProductsRequest>, I>>(object: I): ListProductsRequest {
+    const message = createBaseListProductsRequest();
+    message.pageSize = object.pageSize ?? 0;
+    message.pageToken = object.pageToken ?? "";
+    message.filter = (object.filter !== undefined && object.filter !== null)
+      ? ProductFilter.fromPartial(object.filter)
+      : undefined;
+    message.sort = object.sort ?? 0;
+    return message;
+  },
+};
+
 function createBaseListProductsResponse(): ListProductsResponse {
-  return { products: [] };
+  return { products: [], nextPageToken: "" };
 }
 
 export const ListProductsResponse: MessageFns<ListProductsResponse> = {
@@ -934,6 +1342,9 @@ export const ListProductsResponse: MessageFns<ListProductsResponse> = {
     for (const v of message.products) {
       Product.encode(v!, writer.uint32(10).fork()).join();
     }
+    if (message.nextPageToken !== "") {
+      writer.uint32(18).string(message.nextPageToken);
+    }
     return writer;
   },
 
@@ -952,6 +1363,14 @@ export const ListProductsResponse: MessageFns<ListProductsResponse> = {
           message.products.push(Product.decode(reader, reader.uint32()));
           continue;
         }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.nextPageToken = reader.string();
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -964,6 +1383,7 @@ export const ListProductsResponse: MessageFns<ListProductsResponse> = {
   fromJSON(object: any): ListProductsResponse {
     return {
       products: globalThis.Array.isArray(object?.products) ? object.products.map((e: any) => Product.fromJSON(e)) : [],
+      nextPageToken: isSet(object.nextPageToken) ? globalThis.String(object.nextPageToken) : "",
     };
   },
 
@@ -972,6 +1392,9 @@ export const ListProductsResponse: MessageFns<ListProductsResponse> = {
     if (message.products?.length) {
       obj.products = message.products.map((e) => Product.toJSON(e));
     }
+    if (message.nextPageToken !== "") {
+      obj.nextPageToken = message.nextPageToken;
+    }
     return obj;
   },
 
@@ -981,6 +1404,7 @@ export const ListProductsResponse: MessageFns<ListProductsResponse> = {
   fromPartial<I extends Exact<DeepPartial<ListProductsResponse>, I>>(object: I): ListProductsResponse {
     const message = createBaseListProductsResponse();
     message.products = object.products?.map((e) => Product.fromPartial(e)) || [];
+    message.nextPageToken = object.nextPageToken ?? "";
     return message;
   },
 };
@@ -1043,22 +1467,22 @@ export const GetProductRequest: MessageFns<GetProductRequest> = {
   },
 };
 
-function createBaseSearchProductsRequest(): SearchProductsRequest {
-  return { query: "" };
+function createBaseGetProductsRequest(): GetProductsRequest {
+  return { ids: [] };
 }
 
-export const SearchProductsRequest: MessageFns<SearchProductsRequest> = {
-  encode(message: SearchProductsRequest, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
-    if (message.query !== "") {
-      writer.uint32(10).string(message.query);
+export const GetProductsRequest: MessageFns<GetProductsRequest> = {
+  encode(message: GetProductsRequest, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    for (const v of message.ids) {
+      writer.uint32(10).string(v!);
     }
     return writer;
   },
 
-  decode(input: BinaryReader | Uint8Array, length?: number): SearchProductsRequest {
+  decode(input: BinaryReader | Uint8Array, length?: number): GetProductsRequest {
     const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
     const end = length === undefined ? reader.len : reader.pos + length;
-    const message = createBaseSearchProductsRequest();
+    const message = createBaseGetProductsRequest();
     while (reader.pos < end) {
       const tag = reader.uint32();
       switch (tag >>> 3) {
@@ -1067,7 +1491,7 @@ export const SearchProductsRequest: MessageFns<SearchProductsRequest> = {
             break;
           }
 
-          message.query = reader.string();
+          message.ids.push(reader.string());
           continue;
         }
       }
@@ -1079,44 +1503,44 @@ export const SearchProductsRequest: MessageFns<SearchProductsRequest> = {
     return message;
   },
 
-  fromJSON(object: any): SearchProductsRequest {
-    return { query: isSet(object.query) ? globalThis.String(object.query) : "" };
+  fromJSON(object: any): GetProductsRequest {
+    return { ids: globalThis.Array.isArray(object?.ids) ? object.ids.map((e: any) => globalThis.String(e)) : [] };
   },
 
-  toJSON(message: SearchProductsRequest): unknown {
+  toJSON(message: GetProductsRequest): unknown {
     const obj: any = {};
-    if (message.query !== "") {
-      obj.query = message.query;
+    if (message.ids?.length) {
+      obj.ids = message.ids;
     }
     return obj;
   },
 
-  create<I extends Exact<DeepPartial<SearchProductsRequest>, I>>(base?: I): SearchProductsRequest {
-    return SearchProductsRequest.fromPartial(base ?? ({} as any));
+  create<I extends Exact<DeepPartial<GetProductsRequest>, I>>(base?: I): GetProductsRequest {
+    return GetProductsRequest.fromPartial(base ?? ({} as any));
   },
-  fromPartial<I extends Exact<DeepPartial<SearchProductsRequest>, I>>(object: I): SearchProductsRequest {
-    const message = createBaseSearchProductsRequest();
-    message.query = object.query ?? "";
+  fromPartial<I extends Exact<DeepPartial<GetProductsRequest>, I>>(object: I): GetProductsRequest {
+    const message = createBaseGetProductsRequest();
+    message.ids = object.ids?.map((e) => e) || [];
     return message;
   },
 };
 
-function createBaseSearchProductsResponse(): SearchProductsResponse {
+function createBaseGetProductsResponse(): GetProductsResponse {
   return { results: [] };
 }
 
-export const SearchProductsResponse: MessageFns<SearchProductsResponse> = {
-  encode(message: SearchProductsResponse, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+export const GetProductsResponse: MessageFns<GetProductsResponse> = {
+  encode(message: GetProductsResponse, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
     for (const v of message.results) {
-      Product.encode(v!, writer.uint32(10).fork()).join();
+      ProductResult.encode(v!, writer.uint32(10).fork()).join();
     }
     return writer;
   },
 
-  decode(input: BinaryReader | Uint8Array, length?: number): SearchProductsResponse {
+  decode(input: BinaryReader | Uint8Array, length?: number): GetProductsResponse {
     const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
     const end = length === undefined ? reader.len : reader.pos + length;
-    const message = createBaseSearchProductsResponse();
+    const message = createBaseGetProductsResponse();
     while (reader.pos < end) {
       const tag = reader.uint32();
       switch (tag >>> 3) {
@@ -1125,7 +1549,7 @@ export const SearchProductsResponse: MessageFns<SearchProductsResponse> = {
             break;
           }
 
-          message.results.push(Product.decode(reader, reader.uint32()));
+          message.results.push(ProductResult.decode(reader, reader.uint32()));
           continue;
         }
       }
@@ -1137,41 +1561,447 @@ export const SearchProductsResponse: MessageFns<SearchProductsResponse> = {
     return message;
   },
 
-  fromJSON(object: any): SearchProductsResponse {
+  fromJSON(object: any): GetProductsResponse {
     return {
-      results: globalThis.Array.isArray(object?.results) ? object.results.map((e: any) => Product.fromJSON(e)) : [],
+      results: globalThis.Array.isArray(object?.results)
+        ? object.results.map((e: any) => ProductResult.fromJSON(e))
+        : [],
     };
   },
 
-  toJSON(message: SearchProductsResponse): unknown {
+  toJSON(message: GetProductsResponse): unknown {
     const obj: any = {};
     if (message.results?.length) {
-      obj.results = message.results.map((e) => Product.toJSON(e));
+      obj.results = message.results.map((e) => ProductResult.toJSON(e));
     }
     return obj;
   },
 
-  create<I extends Exact<DeepPartial<SearchProductsResponse>, I>>(base?: I): SearchProductsResponse {
-    return SearchProductsResponse.fromPartial(base ?? ({} as any));
+  create<I extends Exact<DeepPartial<GetProductsResponse>, I>>(base?: I): GetProductsResponse {
+    return GetProductsResponse.fromPartial(base ?? ({} as any));
   },
-  fromPartial<I extends Exact<DeepPartial<SearchProductsResponse>, I>>(object: I): SearchProductsResponse {
-    const message = createBaseSearchProductsResponse();
-    message.results = object.results?.map((e) => Product.fromPartial(e)) || [];
+  fromPartial<I extends Exact<DeepPartial<GetProductsResponse>, I>>(object: I): GetProductsResponse {
+    const message = createBaseGetProductsResponse();
+    message.results = object.results?.map((e) => ProductResult.fromPartial(e)) || [];
     return message;
   },
 };
 
-function createBaseGetQuoteRequest(): GetQuoteRequest {
-  return { address: undefined, items: [] };
+function createBaseProductResult(): ProductResult {
+  return { id: "", found: false, product: undefined };
 }
 
-export const GetQuoteRequest: MessageFns<GetQuoteRequest> = {
-  encode(message: GetQuoteRequest, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
-    if (message.address !== undefined) {
-      Address.encode(message.address, writer.uint32(10).fork()).join();
+export const ProductResult: MessageFns<ProductResult> = {
+  encode(message: ProductResult, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.id !== "") {
+      writer.uint32(10).string(message.id);
     }
-    for (const v of message.items) {
-      CartItem.encode(v!, writer.uint32(18).fork()).join();
+    if (message.found !== false) {
+      writer.uint32(16).bool(message.found);
+    }
+    if (message.product !== undefined) {
+      Product.encode(message.product, writer.uint32(26).fork()).join();
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): ProductResult {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseProductResult();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.id = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 16) {
+            break;
+          }
+
+          message.found = reader.bool();
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.product = Product.decode(reader, reader.uint32());
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): ProductResult {
+    return {
+      id: isSet(object.id) ? globalThis.String(object.id) : "",
+      found: isSet(object.found) ? globalThis.Boolean(object.found) : false,
+      product: isSet(object.product) ? Product.fromJSON(object.product) : undefined,
+    };
+  },
+
+  toJSON(message: ProductResult): unknown {
+    const obj: any = {};
+    if (message.id !== "") {
+      obj.id = message.id;
+    }
+    if (message.found !== false) {
+      obj.found = message.found;
+    }
+    if (message.product !== undefined) {
+      obj.product = Product.toJSON(message.product);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<ProductResult>, I>>(base?: I): ProductResult {
+    return ProductResult.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<ProductResult>, I>>(object: I): ProductResult {
+    const message = createBaseProductResult();
+    message.id = object.id ?? "";
+    message.found = object.found ?? false;
+    message.product = (object.product !== undefined && object.product !== null)
+      ? Product.fromPartial(object.product)
+      : undefined;
+    return message;
+  },
+};
+
+function createBaseSearchProductsRequest(): SearchProductsRequest {
+  return { query: "", pageSize: 0, pageToken: "", filter: undefined, sort: 0 };
+}
+
+export const SearchProductsRequest: MessageFns<SearchProductsRequest> = {
+  encode(message: SearchProductsRequest, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.query !== "") {
+      writer.uint32(10).string(message.query);
+    }
+    if (message.pageSize !== 0) {
+      writer.uint32(16).int32(message.pageSize);
+    }
+    if (message.pageToken !== "") {
+      writer.uint32(26).string(message.pageToken);
+    }
+    if (message.filter !== undefined) {
+      ProductFilter.encode(message.filter, writer.uint32(34).fork()).join();
+    }
+    if (message.sort !== 0) {
+      writer.uint32(40).int32(message.sort);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): SearchProductsRequest {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseSearchProductsRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.query = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 16) {
+            break;
+          }
+
+          message.pageSize = reader.int32();
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.pageToken = reader.string();
+          continue;
+        }
+        case 4: {
+          if (tag !== 34) {
+            break;
+          }
+
+          message.filter = ProductFilter.decode(reader, reader.uint32());
+          continue;
+        }
+        case 5: {
+          if (tag !== 40) {
+            break;
+          }
+
+          message.sort = reader.int32() as any;
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): SearchProductsRequest {
+    return {
+      query: isSet(object.query) ? globalThis.String(object.query) : "",
+      pageSize: isSet(object.pageSize) ? globalThis.Number(object.pageSize) : 0,
+      pageToken: isSet(object.pageToken) ? globalThis.String(object.pageToken) : "",
+      filter: isSet(object.filter) ? ProductFilter.fromJSON(object.filter) : undefined,
+      sort: isSet(object.sort) ? productSortOrderFromJSON(object.sort) : 0,
+    };
+  },
+
+  toJSON(message: SearchProductsRequest): unknown {
+    const obj: any = {};
+    if (message.query !== "") {
+      obj.query = message.query;
+    }
+    if (message.pageSize !== 0) {
+      obj.pageSize = Math.round(message.pageSize);
+    }
+    if (message.pageToken !== "") {
+      obj.pageToken = message.pageToken;
+    }
+    if (message.filter !== undefined) {
+      obj.filter = ProductFilter.toJSON(message.filter);
+    }
+    if (message.sort !== 0) {
+      obj.sort = productSortOrderToJSON(message.sort);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<SearchProductsRequest>, I>>(base?: I): SearchProductsRequest {
+    return SearchProductsRequest.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<SearchProductsRequest>, I>>(object: I): SearchProductsRequest {
+    const message = createBaseSearchProductsRequest();
+    message.query = object.query ?? "";
+    message.pageSize = object.pageSize ?? 0;
+    message.pageToken = object.pageToken ?? "";
+    message.filter = (object.filter !== undefined && object.filter !== null)
+      ? ProductFilter.fromPartial(object.filter)
+      : undefined;
+    message.sort = object.sort ?? 0;
+    return message;
+  },
+};
+
+function createBaseSearchProductsResponse(): SearchProductsResponse {
+  return { results: [], nextPageToken: "", hits: [] };
+}
+
+export const SearchProductsResponse: MessageFns<SearchProductsResponse> = {
+  encode(message: SearchProductsResponse, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    for (const v of message.results) {
+      Product.encode(v!, writer.uint32(10).fork()).join();
+    }
+    if (message.nextPageToken !== "") {
+      writer.uint32(18).string(message.nextPageToken);
+    }
+    for (const v of message.hits) {
+      SearchHit.encode(v!, writer.uint32(26).fork()).join();
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): SearchProductsResponse {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseSearchProductsResponse();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.results.push(Product.decode(reader, reader.uint32()));
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.nextPageToken = reader.string();
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.hits.push(SearchHit.decode(reader, reader.uint32()));
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): SearchProductsResponse {
+    return {
+      results: globalThis.Array.isArray(object?.results) ? object.results.map((e: any) => Product.fromJSON(e)) : [],
+      nextPageToken: isSet(object.nextPageToken) ? globalThis.String(object.nextPageToken) : "",
+      hits: globalThis.Array.isArray(object?.hits) ? object.hits.map((e: any) => SearchHit.fromJSON(e)) : [],
+    };
+  },
+
+  toJSON(message: SearchProductsResponse): unknown {
+    const obj: any = {};
+    if (message.results?.length) {
+      obj.results = message.results.map((e) => Product.toJSON(e));
+    }
+    if (message.nextPageToken !== "") {
+      obj.nextPageToken = message.nextPageToken;
+    }
+    if (message.hits?.length) {
+      obj.hits = message.hits.map((e) => SearchHit.toJSON(e));
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<SearchProductsResponse>, I>>(base?: I): SearchProductsResponse {
+    return SearchProductsResponse.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<SearchProductsResponse>, I>>(object: I): SearchProductsResponse {
+    const message = createBaseSearchProductsResponse();
+    message.results = object.results?.map((e) => Product.fromPartial(e)) || [];
+    message.nextPageToken = object.nextPageToken ?? "";
+    message.hits = object.hits?.map((e) => SearchHit.fromPartial(e)) || [];
+    return message;
+  },
+};
+
+function createBaseSearchHit(): SearchHit {
+  return { productId: "", score: 0, snippet: "" };
+}
+
+export const SearchHit: MessageFns<SearchHit> = {
+  encode(message: SearchHit, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.productId !== "") {
+      writer.uint32(10).string(message.productId);
+    }
+    if (message.score !== 0) {
+      writer.uint32(21).float(message.score);
+    }
+    if (message.snippet !== "") {
+      writer.uint32(26).string(message.snippet);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): SearchHit {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseSearchHit();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.productId = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 21) {
+            break;
+          }
+
+          message.score = reader.float();
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.snippet = reader.string();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): SearchHit {
+    return {
+      productId: isSet(object.productId) ? globalThis.String(object.productId) : "",
+      score: isSet(object.score) ? globalThis.Number(object.score) : 0,
+      snippet: isSet(object.snippet) ? globalThis.String(object.snippet) : "",
+    };
+  },
+
+  toJSON(message: SearchHit): unknown {
+    const obj: any = {};
+    if (message.productId !== "") {
+      obj.productId = message.productId;
+    }
+    if (message.score !== 0) {
+      obj.score = message.score;
+    }
+    if (message.snippet !== "") {
+      obj.snippet = message.snippet;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<SearchHit>, I>>(base?: I): SearchHit {
+    return SearchHit.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<SearchHit>, I>>(object: I): SearchHit {
+    const message = createBaseSearchHit();
+    message.productId = object.productId ?? "";
+    message.score = object.score ?? 0;
+    message.snippet = object.snippet ?? "";
+    return message;
+  },
+};
+
+function createBaseGetQuoteRequest(): GetQuoteRequest {
+  return { address: undefined, items: [] };
+}
+
+export const GetQuoteRequest: MessageFns<GetQuoteRequest> = {
+  encode(message: GetQuoteRequest, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.address !== undefined) {
+      Address.encode(message.address, writer.uint32(10).fork()).join();
+    }
+    for (const v of message.items) {
+      CartItem.encode(v!, writer.uint32(18).fork()).join();
     }
     return writer;
   },
@@ -2031,12 +2861,164 @@ export const ChargeResponse: MessageFns<ChargeResponse> = {
     return obj;
   },
 
-  create<I extends Exact<DeepPartial<ChargeResponse>, I>>(base?: I): ChargeResponse {
-    return ChargeResponse.fromPartial(base ?? ({} as any));
+  create<I extends Exact<DeepPartial<ChargeResponse>, I>>(base?: I): ChargeResponse {
+    return ChargeResponse.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<ChargeResponse>, I>>(object: I): ChargeResponse {
+    const message = createBaseChargeResponse();
+    message.transactionId = object.transactionId ?? "";
+    return message;
+  },
+};
+
+function createBaseRefundRequest(): RefundRequest {
+  return { transactionId: "", amount: undefined, reason: "" };
+}
+
+export const RefundRequest: MessageFns<RefundRequest> = {
+  encode(message: RefundRequest, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.transactionId !== "") {
+      writer.uint32(10).string(message.transactionId);
+    }
+    if (message.amount !== undefined) {
+      Money.encode(message.amount, writer.uint32(18).fork()).join();
+    }
+    if (message.reason !== "") {
+      writer.uint32(26).string(message.reason);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): RefundRequest {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseRefundRequest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.transactionId = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.amount = Money.decode(reader, reader.uint32());
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.reason = reader.string();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): RefundRequest {
+    return {
+      transactionId: isSet(object.transactionId) ? globalThis.String(object.transactionId) : "",
+      amount: isSet(object.amount) ? Money.fromJSON(object.amount) : undefined,
+      reason: isSet(object.reason) ? globalThis.String(object.reason) : "",
+    };
+  },
+
+  toJSON(message: RefundRequest): unknown {
+    const obj: any = {};
+    if (message.transactionId !== "") {
+      obj.transactionId = message.transactionId;
+    }
+    if (message.amount !== undefined) {
+      obj.amount = Money.toJSON(message.amount);
+    }
+    if (message.reason !== "") {
+      obj.reason = message.reason;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<RefundRequest>, I>>(base?: I): RefundRequest {
+    return RefundRequest.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<RefundRequest>, I>>(object: I): RefundRequest {
+    const message = createBaseRefundRequest();
+    message.transactionId = object.transactionId ?? "";
+    message.amount = (object.amount !== undefined && object.amount !== null)
+      ? Money.fromPartial(object.amount)
+      : undefined;
+    message.reason = object.reason ?? "";
+    return message;
+  },
+};
+
+function createBaseRefundResponse(): RefundResponse {
+  return { refundId: "" };
+}
+
+export const RefundResponse: MessageFns<RefundResponse> = {
+  encode(message: RefundResponse, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.refundId !== "") {
+      writer.uint32(10).string(message.refundId);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): RefundResponse {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseRefundResponse();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.refundId = reader.string();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): RefundResponse {
+    return { refundId: isSet(object.refundId) ? globalThis.String(object.refundId) : "" };
+  },
+
+  toJSON(message: RefundResponse): unknown {
+    const obj: any = {};
+    if (message.refundId !== "") {
+      obj.refundId = message.refundId;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<RefundResponse>, I>>(base?: I): RefundResponse {
+    return RefundResponse.fromPartial(base ?? ({} as any));
   },
-  fromPartial<I extends Exact<DeepPartial<ChargeResponse>, I>>(object: I): ChargeResponse {
-    const message = createBaseChargeResponse();
-    message.transactionId = object.transactionId ?? "";
+  fromPartial<I extends Exact<DeepPartial<RefundResponse>, I>>(object: I): RefundResponse {
+    const message = createBaseRefundResponse();
+    message.refundId = object.refundId ?? "";
     return message;
   },
 };
@@ -2118,7 +3100,14 @@ export const OrderItem: MessageFns<OrderItem> = {
 };
 
 function createBaseOrderResult(): OrderResult {
-  return { orderId: "", shippingTrackingId: "", shippingCost: undefined, shippingAddress: undefined, items: [] };
+  return {
+    orderId: "",
+    shippingTrackingId: "",
+    shippingCost: undefined,
+    shippingAddress: undefined,
+    items: [],
+    pricing: undefined,
+  };
 }
 
 export const OrderResult: MessageFns<OrderResult> = {
@@ -2138,6 +3127,9 @@ export const OrderResult: MessageFns<OrderResult> = {
     for (const v of message.items) {
       OrderItem.encode(v!, writer.uint32(42).fork()).join();
     }
+    if (message.pricing !== undefined) {
+      OrderPricing.encode(message.pricing, writer.uint32(50).fork()).join();
+    }
     return writer;
   },
 
@@ -2188,6 +3180,14 @@ export const OrderResult: MessageFns<OrderResult> = {
           message.items.push(OrderItem.decode(reader, reader.uint32()));
           continue;
         }
+        case 6: {
+          if (tag !== 50) {
+            break;
+          }
+
+          message.pricing = OrderPricing.decode(reader, reader.uint32());
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -2204,6 +3204,7 @@ export const OrderResult: MessageFns<OrderResult> = {
       shippingCost: isSet(object.shippingCost) ? Money.fromJSON(object.shippingCost) : undefined,
       shippingAddress: isSet(object.shippingAddress) ? Address.fromJSON(object.shippingAddress) : undefined,
       items: globalThis.Array.isArray(object?.items) ? object.items.map((e: any) => OrderItem.fromJSON(e)) : [],
+      pricing: isSet(object.pricing) ? OrderPricing.fromJSON(object.pricing) : undefined,
     };
   },
 
@@ -2224,6 +3225,9 @@ export const OrderResult: MessageFns<OrderResult> = {
     if (message.items?.length) {
       obj.items = message.items.map((e) => OrderItem.toJSON(e));
     }
+    if (message.pricing !== undefined) {
+      obj.pricing = OrderPricing.toJSON(message.pricing);
+    }
     return obj;
   },
 
@@ -2241,12 +3245,296 @@ export const OrderResult: MessageFns<OrderResult> = {
       ? Address.fromPartial(object.shippingAddress)
       : undefined;
     message.items = object.items?.map((e) => OrderItem.fromPartial(e)) || [];
+    message.pricing = (object.pricing !== undefined && object.pricing !== null)
+      ? OrderPricing.fromPartial(object.pricing)
+      : undefined;
+    return message;
+  },
+};
+
+function createBaseOrderPricing(): OrderPricing {
+  return {
+    subtotal: undefined,
+    discount: undefined,
+    shipping: undefined,
+    tax: undefined,
+    total: undefined,
+    adjustments: [],
+  };
+}
+
+export const OrderPricing: MessageFns<OrderPricing> = {
+  encode(message: OrderPricing, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.subtotal !== undefined) {
+      Money.encode(message.subtotal, writer.uint32(10).fork()).join();
+    }
+    if (message.discount !== undefined) {
+      Money.encode(message.discount, writer.uint32(18).fork()).join();
+    }
+    if (message.shipping !== undefined) {
+      Money.encode(message.shipping, writer.uint32(26).fork()).join();
+    }
+    if (message.tax !== undefined) {
+      Money.encode(message.tax, writer.uint32(34).fork()).join();
+    }
+    if (message.total !== undefined) {
+      Money.encode(message.total, writer.uint32(42).fork()).join();
+    }
+    for (const v of message.adjustments) {
+      PriceAdjustment.encode(v!, writer.uint32(50).fork()).join();
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): OrderPricing {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseOrderPricing();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.subtotal = Money.decode(reader, reader.uint32());
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.discount = Money.decode(reader, reader.uint32());
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.shipping = Money.decode(reader, reader.uint32());
+          continue;
+        }
+        case 4: {
+          if (tag !== 34) {
+            break;
+          }
+
+          message.tax = Money.decode(reader, reader.uint32());
+          continue;
+        }
+        case 5: {
+          if (tag !== 42) {
+            break;
+          }
+
+          message.total = Money.decode(reader, reader.uint32());
+          continue;
+        }
+        case 6: {
+          if (tag !== 50) {
+            break;
+          }
+
+          message.adjustments.push(PriceAdjustment.decode(reader, reader.uint32()));
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): OrderPricing {
+    return {
+      subtotal: isSet(object.subtotal) ? Money.fromJSON(object.subtotal) : undefined,
+      discount: isSet(object.discount) ? Money.fromJSON(object.discount) : undefined,
+      shipping: isSet(object.shipping) ? Money.fromJSON(object.shipping) : undefined,
+      tax: isSet(object.tax) ? Money.fromJSON(object.tax) : undefined,
+      total: isSet(object.total) ? Money.fromJSON(object.total) : undefined,
+      adjustments: globalThis.Array.isArray(object?.adjustments)
+        ? object.adjustments.map((e: any) => PriceAdjustment.fromJSON(e))
+        : [],
+    };
+  },
+
+  toJSON(message: OrderPricing): unknown {
+    const obj: any = {};
+    if (message.subtotal !== undefined) {
+      obj.subtotal = Money.toJSON(message.subtotal);
+    }
+    if (message.discount !== undefined) {
+      obj.discount = Money.toJSON(message.discount);
+    }
+    if (message.shipping !== undefined) {
+      obj.shipping = Money.toJSON(message.shipping);
+    }
+    if (message.tax !== undefined) {
+      obj.tax = Money.toJSON(message.tax);
+    }
+    if (message.total !== undefined) {
+      obj.total = Money.toJSON(message.total);
+    }
+    if (message.adjustments?.length) {
+      obj.adjustments = message.adjustments.map((e) => PriceAdjustment.toJSON(e));
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<OrderPricing>, I>>(base?: I): OrderPricing {
+    return OrderPricing.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<OrderPricing>, I>>(object: I): OrderPricing {
+    const message = createBaseOrderPricing();
+    message.subtotal = (object.subtotal !== undefined && object.subtotal !== null)
+      ? Money.fromPartial(object.subtotal)
+      : undefined;
+    message.discount = (object.discount !== undefined && object.discount !== null)
+      ? Money.fromPartial(object.discount)
+      : undefined;
+    message.shipping = (object.shipping !== undefined && object.shipping !== null)
+      ? Money.fromPartial(object.shipping)
+      : undefined;
+    message.tax = (object.tax !== undefined && object.tax !== null) ? Money.fromPartial(object.tax) : undefined;
+    message.total = (object.total !== undefined && object.total !== null) ? Money.fromPartial(object.total) : undefined;
+    message.adjustments = object.adjustments?.map((e) => PriceAdjustment.fromPartial(e)) || [];
+    return message;
+  },
+};
+
+function createBasePriceAdjustment(): PriceAdjustment {
+  return { kind: "", id: "", description: "", productId: "", amount: undefined };
+}
+
+export const PriceAdjustment: MessageFns<PriceAdjustment> = {
+  encode(message: PriceAdjustment, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.kind !== "") {
+      writer.uint32(10).string(message.kind);
+    }
+    if (message.id !== "") {
+      writer.uint32(18).string(message.id);
+    }
+    if (message.description !== "") {
+      writer.uint32(26).string(message.description);
+    }
+    if (message.productId !== "") {
+      writer.uint32(34).string(message.productId);
+    }
+    if (message.amount !== undefined) {
+      Money.encode(message.amount, writer.uint32(42).fork()).join();
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): PriceAdjustment {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBasePriceAdjustment();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.kind = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.id = reader.string();
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.description = reader.string();
+          continue;
+        }
+        case 4: {
+          if (tag !== 34) {
+            break;
+          }
+
+          message.productId = reader.string();
+          continue;
+        }
+        case 5: {
+          if (tag !== 42) {
+            break;
+          }
+
+          message.amount = Money.decode(reader, reader.uint32());
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): PriceAdjustment {
+    return {
+      kind: isSet(object.kind) ? globalThis.String(object.kind) : "",
+      id: isSet(object.id) ? globalThis.String(object.id) : "",
+      description: isSet(object.description) ? globalThis.String(object.description) : "",
+      productId: isSet(object.productId) ? globalThis.String(object.productId) : "",
+      amount: isSet(object.amount) ? Money.fromJSON(object.amount) : undefined,
+    };
+  },
+
+  toJSON(message: PriceAdjustment): unknown {
+    const obj: any = {};
+    if (message.kind !== "") {
+      obj.kind = message.kind;
+    }
+    if (message.id !== "") {
+      obj.id = message.id;
+    }
+    if (message.description !== "") {
+      obj.description = message.description;
+    }
+    if (message.productId !== "") {
+      obj.productId = message.productId;
+    }
+    if (message.amount !== undefined) {
+      obj.amount = Money.toJSON(message.amount);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<PriceAdjustment>, I>>(base?: I): PriceAdjustment {
+    return PriceAdjustment.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<PriceAdjustment>, I>>(object: I): PriceAdjustment {
+    const message = createBasePriceAdjustment();
+    message.kind = object.kind ?? "";
+    message.id = object.id ?? "";
+    message.description = object.description ?? "";
+    message.productId = object.productId ?? "";
+    message.amount = (object.amount !== undefined && object.amount !== null)
+      ? Money.fromPartial(object.amount)
+      : undefined;
     return message;
   },
 };
 
 function createBaseSendOrderConfirmationRequest(): SendOrderConfirmationRequest {
-  return { email: "", order: undefined };
+  return { email: "", order: undefined, total: "" };
 }
 
 export const SendOrderConfirmationRequest: MessageFns<SendOrderConfirmationRequest> = {
@@ -2257,6 +3545,9 @@ export const SendOrderConfirmationRequest: MessageFns<SendOrderConfirmationReque
     if (message.order !== undefined) {
       OrderResult.encode(message.order, writer.uint32(18).fork()).join();
     }
+    if (message.total !== "") {
+      writer.uint32(26).string(message.total);
+    }
     return writer;
   },
 
@@ -2283,6 +3574,14 @@ export const SendOrderConfirmationRequest: MessageFns<SendOrderConfirmationReque
           message.order = OrderResult.decode(reader, reader.uint32());
           continue;
         }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.total = reader.string();
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -2296,6 +3595,7 @@ export const SendOrderConfirmationRequest: MessageFns<SendOrderConfirmationReque
     return {
       email: isSet(object.email) ? globalThis.String(object.email) : "",
       order: isSet(object.order) ? OrderResult.fromJSON(object.order) : undefined,
+      total: isSet(object.total) ? globalThis.String(object.total) : "",
     };
   },
 
@@ -2307,6 +3607,9 @@ export const SendOrderConfirmationRequest: MessageFns<SendOrderConfirmationReque
     if (message.order !== undefined) {
       obj.order = OrderResult.toJSON(message.order);
     }
+    if (message.total !== "") {
+      obj.total = message.total;
+    }
     return obj;
   },
 
@@ -2319,12 +3622,21 @@ export const SendOrderConfirmationRequest: MessageFns<SendOrderConfirmationReque
     message.order = (object.order !== undefined && object.order !== null)
       ? OrderResult.fromPartial(object.order)
       : undefined;
+    message.total = object.total ?? "";
     return message;
   },
 };
 
 function createBasePlaceOrderRequest(): PlaceOrderRequest {
-  return { userId: "", userCurrency: "", address: undefined, email: "", creditCard: undefined };
+  return {
+    userId: "",
+    userCurrency: "",
+    address: undefined,
+    email: "",
+    creditCard: undefined,
+    idempotencyKey: "",
+    couponCodes: [],
+  };
 }
 
 export const PlaceOrderRequest: MessageFns<PlaceOrderRequest> = {
@@ -2344,6 +3656,12 @@ export const PlaceOrderRequest: MessageFns<PlaceOrderRequest> = {
     if (message.creditCard !== undefined) {
       CreditCardInfo.encode(message.creditCard, writer.uint32(50).fork()).join();
     }
+    if (message.idempotencyKey !== "") {
+      writer.uint32(58).string(message.idempotencyKey);
+    }
+    for (const v of message.couponCodes) {
+      writer.uint32(66).string(v!);
+    }
     return writer;
   },
 
@@ -2394,6 +3712,22 @@ export const PlaceOrderRequest: MessageFns<PlaceOrderRequest> = {
           message.creditCard = CreditCardInfo.decode(reader, reader.uint32());
           continue;
         }
+        case 7: {
+          if (tag !== 58) {
+            break;
+          }
+
+          message.idempotencyKey = reader.string();
+          continue;
+        }
+        case 8: {
+          if (tag !== 66) {
+            break;
+          }
+
+          message.couponCodes.push(reader.string());
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -2410,6 +3744,10 @@ export const PlaceOrderRequest: MessageFns<PlaceOrderRequest> = {
       address: isSet(object.address) ? Address.fromJSON(object.address) : undefined,
       email: isSet(object.email) ? globalThis.String(object.email) : "",
       creditCard: isSet(object.creditCard) ? CreditCardInfo.fromJSON(object.creditCard) : undefined,
+      idempotencyKey: isSet(object.idempotencyKey) ? globalThis.String(object.idempotencyKey) : "",
+      couponCodes: globalThis.Array.isArray(object?.couponCodes)
+        ? object.couponCodes.map((e: any) => globalThis.String(e))
+        : [],
     };
   },
 
@@ -2430,6 +3768,12 @@ export const PlaceOrderRequest: MessageFns<PlaceOrderRequest> = {
     if (message.creditCard !== undefined) {
       obj.creditCard = CreditCardInfo.toJSON(message.creditCard);
     }
+    if (message.idempotencyKey !== "") {
+      obj.idempotencyKey = message.idempotencyKey;
+    }
+    if (message.couponCodes?.length) {
+      obj.couponCodes = message.couponCodes;
+    }
     return obj;
   },
 
@@ -2447,6 +3791,8 @@ export const PlaceOrderRequest: MessageFns<PlaceOrderRequest> = {
     message.creditCard = (object.creditCard !== undefined && object.creditCard !== null)
       ? CreditCardInfo.fromPartial(object.creditCard)
       : undefined;
+    message.idempotencyKey = object.idempotencyKey ?? "";
+    message.couponCodes = object.couponCodes?.map((e) => e) || [];
     return message;
   },
 };
@@ -3526,8 +4872,8 @@ export const ProductCatalogServiceService = {
     path: "/oteldemo.ProductCatalogService/ListProducts",
     requestStream: false,
     responseStream: false,
-    requestSerialize: (value: Empty): Buffer => Buffer.from(Empty.encode(value).finish()),
-    requestDeserialize: (value: Buffer): Empty => Empty.decode(value),
+    requestSerialize: (value: ListProductsRequest): Buffer => Buffer.from(ListProductsRequest.encode(value).finish()),
+    requestDeserialize: (value: Buffer): ListProductsRequest => ListProductsRequest.decode(value),
     responseSerialize: (value: ListProductsResponse): Buffer =>
       Buffer.from(ListProductsResponse.encode(value).finish()),
     responseDeserialize: (value: Buffer): ListProductsResponse => ListProductsResponse.decode(value),
@@ -3541,6 +4887,15 @@ export const ProductCatalogServiceService = {
     responseSerialize: (value: Product): Buffer => Buffer.from(Product.encode(value).finish()),
     responseDeserialize: (value: Buffer): Product => Product.decode(value),
   },
+  getProducts: {
+    path: "/oteldemo.ProductCatalogService/GetProducts",
+    requestStream: false,
+    responseStream: false,
+    requestSerialize: (value: GetProductsRequest): Buffer => Buffer.from(GetProductsRequest.encode(value).finish()),
+    requestDeserialize: (value: Buffer): GetProductsRequest => GetProductsRequest.decode(value),
+    responseSerialize: (value: GetProductsResponse): Buffer => Buffer.from(GetProductsResponse.encode(value).finish()),
+    responseDeserialize: (value: Buffer): GetProductsResponse => GetProductsResponse.decode(value),
+  },
   searchProducts: {
     path: "/oteldemo.ProductCatalogService/SearchProducts",
     requestStream: false,
@@ -3555,23 +4910,24 @@ export const ProductCatalogServiceService = {
 } as const;
 
 export interface ProductCatalogServiceServer extends UntypedServiceImplementation {
-  listProducts: handleUnaryCall<Empty, ListProductsResponse>;
+  listProducts: handleUnaryCall<ListProductsRequest, ListProductsResponse>;
   getProduct: handleUnaryCall<GetProductRequest, Product>;
+  getProducts: handleUnaryCall<GetProductsRequest, GetProductsResponse>;
   searchProducts: handleUnaryCall<SearchProductsRequest, SearchProductsResponse>;
 }
 
 export interface ProductCatalogServiceClient extends Client {
   listProducts(
-    request: Empty,
+    request: ListProductsRequest,
     callback: (error: ServiceError | null, response: ListProductsResponse) => void,
   ): ClientUnaryCall;
   listProducts(
-    request: Empty,
+    request: ListProductsRequest,
     metadata: Metadata,
     callback: (error: ServiceError | null, response: ListProductsResponse) => void,
   ): ClientUnaryCall;
   listProducts(
-    request: Empty,
+    request: ListProductsRequest,
     metadata: Metadata,
     options: Partial<CallOptions>,
     callback: (error: ServiceError | null, response: ListProductsResponse) => void,
@@ -3591,6 +4947,21 @@ export interface ProductCatalogServiceClient extends Client {
     options: Partial<CallOptions>,
     callback: (error: ServiceError | null, response: Product) => void,
   ): ClientUnaryCall;
+  getProducts(
+    request: GetProductsRequest,
+    callback: (error: ServiceError | null, response: GetProductsResponse) => void,
+  ): ClientUnaryCall;
+  getProducts(
+    request: GetProductsRequest,
+    metadata: Metadata,
+    callback: (error: ServiceError | null, response: GetProductsResponse) => void,
+  ): ClientUnaryCall;
+  getProducts(
+    request: GetProductsRequest,
+    metadata: Metadata,
+    options: Partial<CallOptions>,
+    callback: (error: ServiceError | null, response: GetProductsResponse) => void,
+  ): ClientUnaryCall;
   searchProducts(
     request: SearchProductsRequest,
     callback: (error: ServiceError | null, response: SearchProductsResponse) => void,
@@ -3769,10 +5140,20 @@ export const PaymentServiceService = {
     responseSerialize: (value: ChargeResponse): Buffer => Buffer.from(ChargeResponse.encode(value).finish()),
     responseDeserialize: (value: Buffer): ChargeResponse => ChargeResponse.decode(value),
   },
+  refund: {
+    path: "/oteldemo.PaymentService/Refund",
+    requestStream: false,
+    responseStream: false,
+    requestSerialize: (value: RefundRequest): Buffer => Buffer.from(RefundRequest.encode(value).finish()),
+    requestDeserialize: (value: Buffer): RefundRequest => RefundRequest.decode(value),
+    responseSerialize: (value: RefundResponse): Buffer => Buffer.from(RefundResponse.encode(value).finish()),
+    responseDeserialize: (value: Buffer): RefundResponse => RefundResponse.decode(value),
+  },
 } as const;
 
 export interface PaymentServiceServer extends UntypedServiceImplementation {
   charge: handleUnaryCall<ChargeRequest, ChargeResponse>;
+  refund: handleUnaryCall<RefundRequest, RefundResponse>;
 }
 
 export interface PaymentServiceClient extends Client {
@@ -3791,6 +5172,21 @@ export interface PaymentServiceClient extends Client {
     options: Partial<CallOptions>,
     callback: (error: ServiceError | null, response: ChargeResponse) => void,
   ): ClientUnaryCall;
+  refund(
+    request: RefundRequest,
+    callback: (error: ServiceError | null, response: RefundResponse) => void,
+  ): ClientUnaryCall;
+  refund(
+    request: RefundRequest,
+    metadata: Metadata,
+    callback: (error: ServiceError | null, response: RefundResponse) => void,
+  ): ClientUnaryCall;
+  refund(
+    request: RefundRequest,
+    metadata: Metadata,
+    options: Partial<CallOptions>,
+    callback: (error: ServiceError | null, response: RefundResponse) => void,
+  ): ClientUnaryCall;
 }
 
 export const PaymentServiceClient = makeGenericClientConstructor(
@@ -12,6 +12,7 @@ const logger = require('./logger');
 const tracer = trace.getTracer('payment');
 const meter = metrics.getMeter('payment');
 const transactionsCounter = meter.createCounter('app.payment.transactions');
+const refundsCounter = meter.createCounter('app.payment.refunds');
 
 const LOYALTY_LEVEL = ['platinum', 'gold', 'silver', 'bronze'];
 
@@ -86,3 +87,28 @@ module.exports.charge = async request => {
 
   return { transactionId };
 };
+
+module.exports.refund = async request => {
+  const span = tracer.startSpan('refund');
+
+  const { transactionId, reason } = request;
+  if (!transactionId) {
+    span.end();
+    throw new Error('Refund request is missing the transaction id.');
+  }
+
+  const refundId = uuidv4();
+  const { units, nanos, currencyCode } = request.amount || {};
+
+  span.setAttributes({
+    'app.payment.transaction.id': transactionId,
+    'app.payment.refund.id': refundId,
+    'app.payment.refund.reason': reason
+  });
+
+  logger.info({ transactionId, refundId, reason, amount: { units, nanos, currencyCode } }, 'Refund complete.');
+  refundsCounter.add(1, { 'app.payment.currency': currencyCode });
+  span.end();
+
+  return { refundId };
+};
@@ -30,6 +30,27 @@ async function chargeServiceHandler(call, callback) {
   }
 }
 
+async function refundServiceHandler(call, callback) {
+  const span = opentelemetry.trace.getActiveSpan();
+
+  try {
+    span?.setAttributes({
+      'app.payment.transaction.id': call.request.transactionId
+    })
+    logger.info({ request: call.request }, "Refund request received.")
+
+    const response = await charge.refund(call.request)
+    callback(null, response)
+
+  } catch (err) {
+    logger.warn({ err })
+
+    span?.recordException(err)
+    span?.setStatus({ code: opentelemetry.SpanStatusCode.ERROR })
+    callback(err)
+  }
+}
+
 async function closeGracefully(signal) {
   server.forceShutdown()
   process.kill(process.pid, signal)
@@ -42,7 +63,7 @@ server.addService(health.service, new health.Implementation({
   '': health.servingStatus.SERVING
 }))
 
-server.addService(otelDemoPackage.oteldemo.PaymentService.service, { charge: chargeServiceHandler })
+server.addService(otelDemoPackage.oteldemo.PaymentService.service, { charge: chargeServiceHandler, refund: refundServiceHandler })
 
 
 let ip = "0.0.0.0";
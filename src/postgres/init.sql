@@ -34,6 +34,31 @@ CREATE TABLE orderitem (
     FOREIGN KEY (order_id) REFERENCES "order"(order_id) ON DELETE CASCADE
 );
 
+-- Idempotency keys for checkout PlaceOrder retries
+CREATE TABLE checkout_idempotency (
+    idempotency_key TEXT PRIMARY KEY,
+    status TEXT NOT NULL,
+    order_result BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_checkout_idempotency_created_at ON checkout_idempotency(created_at);
+
+-- Outbox of checkout order events awaiting publication to Kafka
+CREATE TABLE checkout_outbox (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    order_id TEXT NOT NULL,
+    message_key TEXT NOT NULL DEFAULT '',
+    payload BYTEA NOT NULL,
+    headers JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+CREATE INDEX idx_checkout_outbox_pending ON checkout_outbox(id) WHERE delivered_at IS NULL;
+
 -- Products table for product-catalog service
 CREATE TABLE products (
     id VARCHAR(10) PRIMARY KEY,
@@ -44,12 +69,19 @@ CREATE TABLE products (
     price_units BIGINT,
     price_nanos INTEGER,
     categories TEXT[],
+    -- Full-text search document for SearchProducts, names ranking above descriptions
+    search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED,
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW()
 );
 
-CREATE INDEX idx_products_name ON products(name);
-CREATE INDEX idx_products_description ON products(description);
+-- Keyset pagination indexes for ListProducts and SearchProducts sort orders
+CREATE INDEX idx_products_name ON products(name, id);
+CREATE INDEX idx_products_price ON products(price_units, price_nanos, id);
+CREATE INDEX idx_products_search ON products USING GIN(search_vector);
 CREATE INDEX idx_products_categories ON products USING GIN(categories);
 
 -- Seed products data
@@ -66,6 +98,10 @@ INSERT INTO products (id, name, description, picture, price_currency_code, price
 ('HQTGWGPNH4', 'The Comet Book', 'A 16th-century treatise on comets, created anonymously in Flanders (now northern France) and now held at the Universitätsbibliothek Kassel. Commonly known as The Comet Book (or Kometenbuch in German), its full title translates as "Comets and their General and Particular Meanings, According to Ptolomeé, Albumasar, Haly, Aliquind and other Astrologers". The image is from https://publicdomainreview.org/collection/the-comet-book, made available by the Universitätsbibliothek Kassel under a CC-BY SA 4.0 license (https://creativecommons.org/licenses/by-sa/4.0/)', 'TheCometBook.jpg', 'USD', 0, 990000000, '{"books"}');
 
 GRANT SELECT, INSERT, UPDATE ON ALL TABLES IN SCHEMA public TO otelu;
+-- Delivered outbox events are pruned after their retention period
+GRANT DELETE ON checkout_outbox TO otelu;
+-- Expired idempotency keys are swept by checkout
+GRANT DELETE ON checkout_idempotency TO otelu;
 
 -- Database is ready for the accounting service and product-catalog service
 -- For IOPS demo with pre-seeded data, see: postgres-seed-for-iops.md
@@ -9,21 +9,18 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"io/fs"
 	"log/slog"
 	"net"
 	"os"
 	"os/signal"
 	"runtime/debug"
-	"strconv"
-	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/XSAM/otelsql"
-	"github.com/lib/pq"   // For pq.StringArray type
 	_ "github.com/lib/pq" // Register postgres driver
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -54,20 +51,14 @@ import (
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/encoding/protojson"
 )
 
 var (
 	logger            *slog.Logger
-	catalog           []*pb.Product
-	db                *sql.DB
-	useDatabase       bool
 	resource          *sdkresource.Resource
 	initResourcesOnce sync.Once
 )
 
-const DEFAULT_RELOAD_INTERVAL = 10
-
 func init() {
 	defer func() {
 		if r := recover(); r != nil {
@@ -156,7 +147,7 @@ func initDatabase() (*sql.DB, error) {
 	// Check if database usage is enabled
 	useDatabaseEnv := os.Getenv("USE_DATABASE")
 	fmt.Fprintf(os.Stderr, "[DB] USE_DATABASE=%s\n", useDatabaseEnv)
-	useDatabase = useDatabaseEnv == "true" || useDatabaseEnv == "1"
+	useDatabase := useDatabaseEnv == "true" || useDatabaseEnv == "1"
 
 	if !useDatabase {
 		fmt.Fprintf(os.Stderr, "[DB] Database mode disabled, using JSON file catalog\n")
@@ -168,7 +159,6 @@ func initDatabase() (*sql.DB, error) {
 	if connStr == "" {
 		fmt.Fprintf(os.Stderr, "[DB] WARNING: DB_CONNECTION_STRING not set, falling back to JSON file catalog\n")
 		logger.Warn("DB_CONNECTION_STRING not set, falling back to JSON file catalog")
-		useDatabase = false
 		return nil, nil
 	}
 
@@ -254,9 +244,6 @@ func main() {
 	logger = otelslog.NewLogger("product-catalog")
 	logger.Info("Logger initialized successfully")
 
-	// Load product catalog now (moved from init() to avoid crashes)
-	loadProductCatalog()
-
 	tp := initTracerProvider()
 	logger.Info("Tracer provider initialized")
 	defer func() {
@@ -289,16 +276,16 @@ func main() {
 	_, dbSpan := tracer.Start(initCtx, "product-catalog.init.database")
 	defer dbSpan.End()
 
-	var err error
-	db, err = initDatabase()
+	var repo ProductRepository
+	db, err := initDatabase()
 	if err != nil {
 		// Log error and fall back to JSON file catalog instead of exiting
 		dbSpan.SetStatus(otelcodes.Error, err.Error())
 		dbSpan.RecordError(err)
 		logger.Error(fmt.Sprintf("Failed to initialize database: %v. Falling back to JSON file catalog.", err))
-		useDatabase = false
 		// Don't exit - allow service to start with JSON fallback
 	} else if db != nil {
+		repo = newPostgresRepository(db)
 		dbSpan.SetAttributes(attribute.String("db.connection.status", "success"))
 		dbSpan.AddEvent("Database connection established")
 		logger.Info("Database connection established successfully")
@@ -313,6 +300,24 @@ func main() {
 		logger.Info("Database disabled, using JSON file catalog")
 	}
 
+	if repo == nil {
+		// Load product catalog now (moved from init() to avoid crashes)
+		fmt.Fprintf(os.Stderr, "[LOAD] Loading Product Catalog...\n")
+		logger.Info("Loading Product Catalog...")
+		jsonRepo, err := newJSONRepository("./products")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[LOAD] ERROR: %v\n", err)
+			logger.Error(fmt.Sprintf("Failed to load product catalog: %v", err))
+			os.Exit(1)
+		}
+		defer func() {
+			if err := jsonRepo.Close(); err != nil {
+				logger.Error(fmt.Sprintf("Error closing product catalog: %v", err))
+			}
+		}()
+		repo = jsonRepo
+	}
+
 	_, featureSpan := tracer.Start(initCtx, "product-catalog.init.feature-flags")
 	openfeature.AddHooks(otelhooks.NewTracesHook())
 	provider, err := flagd.NewProvider()
@@ -339,7 +344,7 @@ func main() {
 	runtimeSpan.End()
 	logger.Info("Runtime instrumentation started")
 
-	svc := &productCatalog{}
+	svc := &productCatalog{repo: repo}
 
 	var port string
 	mustMapEnv(&port, "PRODUCT_CATALOG_PORT")
@@ -387,93 +392,7 @@ func main() {
 
 type productCatalog struct {
 	pb.UnimplementedProductCatalogServiceServer
-}
-
-func loadProductCatalog() {
-	fmt.Fprintf(os.Stderr, "[LOAD] Loading Product Catalog...\n")
-	logger.Info("Loading Product Catalog...")
-	var err error
-	catalog, err = readProductFiles()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "[LOAD] ERROR: Error reading product files: %v. Will use database if available.\n", err)
-		logger.Warn(fmt.Sprintf("Error reading product files: %v. Will use database if available.", err))
-		catalog = []*pb.Product{} // Initialize empty catalog instead of exiting
-		return
-	}
-	fmt.Fprintf(os.Stderr, "[LOAD] Successfully loaded %d products\n", len(catalog))
-
-	// Default reload interval is 10 seconds
-	interval := DEFAULT_RELOAD_INTERVAL
-	si := os.Getenv("PRODUCT_CATALOG_RELOAD_INTERVAL")
-	if si != "" {
-		interval, _ = strconv.Atoi(si)
-		if interval <= 0 {
-			interval = DEFAULT_RELOAD_INTERVAL
-		}
-	}
-	logger.Info(fmt.Sprintf("Product Catalog reload interval: %d", interval))
-
-	ticker := time.NewTicker(time.Duration(interval) * time.Second)
-
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				logger.Info("Reloading Product Catalog...")
-				catalog, err = readProductFiles()
-				if err != nil {
-					logger.Error(fmt.Sprintf("Error reading product files: %v", err))
-					continue
-				}
-			}
-		}
-	}()
-}
-
-func readProductFiles() ([]*pb.Product, error) {
-
-	// find all .json files in the products directory
-	entries, err := os.ReadDir("./products")
-	if err != nil {
-		return nil, err
-	}
-
-	jsonFiles := make([]fs.FileInfo, 0, len(entries))
-	for _, entry := range entries {
-		if strings.HasSuffix(entry.Name(), ".json") {
-			info, err := entry.Info()
-			if err != nil {
-				return nil, err
-			}
-			jsonFiles = append(jsonFiles, info)
-		}
-	}
-
-	// read the contents of each .json file and unmarshal into a ListProductsResponse
-	// then append the products to the catalog
-	var products []*pb.Product
-	for _, f := range jsonFiles {
-		jsonData, err := os.ReadFile("./products/" + f.Name())
-		if err != nil {
-			return nil, err
-		}
-
-		var res pb.ListProductsResponse
-		if err := protojson.Unmarshal(jsonData, &res); err != nil {
-			return nil, err
-		}
-
-		products = append(products, res.Products...)
-	}
-
-	logger.LogAttrs(
-		context.Background(),
-		slog.LevelInfo,
-		fmt.Sprintf("Loaded %d products\n", len(products)),
-		slog.Int("products", len(products)),
-	)
-
-	return products, nil
+	repo ProductRepository
 }
 
 func mustMapEnv(target *string, key string) {
@@ -492,498 +411,188 @@ func (p *productCatalog) Watch(req *healthpb.HealthCheckRequest, ws healthpb.Hea
 	return status.Errorf(codes.Unimplemented, "health check via Watch not implemented")
 }
 
-func listProductsFromDB(ctx context.Context) ([]*pb.Product, error) {
-	tracer := otel.Tracer("product-catalog")
-	ctx, span := tracer.Start(ctx, "db.products.list")
-	defer span.End()
-
-	span.SetAttributes(
-		semconv.DBSystemPostgreSQL,
-		attribute.String("db.operation", "SELECT"),
-		attribute.String("db.sql.table", "products"),
-	)
-
-	query := `SELECT id, name, description, picture, price_currency_code, price_units, price_nanos, categories
-	          FROM products ORDER BY name`
-
-	span.SetAttributes(attribute.String("db.statement", query))
-
-	// Add 30-second timeout for statement execution
-	queryCtx, queryCancel := context.WithTimeout(ctx, 30*time.Second)
-	defer queryCancel()
+func (p *productCatalog) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
+	span := trace.SpanFromContext(ctx)
 
-	// Execute query with explicit span for visibility
-	queryCtx, querySpan := tracer.Start(queryCtx, "db.query.execute")
-	querySpan.SetAttributes(
-		attribute.String("db.operation", "SELECT"),
-		attribute.String("db.sql.table", "products"),
-		attribute.String("db.statement.timeout", "30s"),
-	)
-	rows, err := db.QueryContext(queryCtx, query)
+	q, err := newProductQuery(req, pb.ProductSortOrder_PRODUCT_SORT_ORDER_NAME_ASC)
 	if err != nil {
-		querySpan.RecordError(err)
-		querySpan.SetStatus(otelcodes.Error, "Query execution failed")
-		querySpan.End()
-	} else {
-		querySpan.SetStatus(otelcodes.Ok, "Query executed successfully")
-		querySpan.End()
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
 	}
+	setProductQueryAttributes(span, req, q)
 
+	products, err := p.repo.ListProducts(ctx, q)
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(otelcodes.Error, fmt.Sprintf("Database query failed: %v", err))
-		span.SetAttributes(
-			attribute.Bool("db.query.error", true),
-			attribute.String("db.error.message", err.Error()),
-		)
-		return nil, fmt.Errorf("failed to query products: %w", err)
-	}
-	defer rows.Close()
-
-	span.SetAttributes(attribute.Bool("db.query.success", true))
-
-	// Scan rows with explicit span for visibility
-	var products []*pb.Product
-	ctx, scanSpan := tracer.Start(ctx, "db.rows.scan")
-	scanSpan.SetAttributes(
-		attribute.String("db.operation", "scan"),
-	)
-	scanCount := 0
-
-	for rows.Next() {
-		var product pb.Product
-		product.PriceUsd = &pb.Money{}
-		var categories pq.StringArray
-
-		err := rows.Scan(
-			&product.Id,
-			&product.Name,
-			&product.Description,
-			&product.Picture,
-			&product.PriceUsd.CurrencyCode,
-			&product.PriceUsd.Units,
-			&product.PriceUsd.Nanos,
-			&categories,
-		)
-
-		if err != nil {
-			scanSpan.RecordError(err)
-			scanSpan.SetStatus(otelcodes.Error, "Row scan failed")
-			scanSpan.SetAttributes(
-				attribute.Int("db.rows.scanned", scanCount),
-				attribute.Bool("db.scan.error", true),
-				attribute.String("db.error.message", err.Error()),
-			)
-			scanSpan.End()
-			span.RecordError(err)
-			span.SetStatus(otelcodes.Error, fmt.Sprintf("Failed to scan product row: %v", err))
-			return nil, fmt.Errorf("failed to scan product row: %w", err)
-		}
-
-		product.Categories = categories
-		products = append(products, &product)
-		scanCount++
-	}
-
-	if err = rows.Err(); err != nil {
-		scanSpan.RecordError(err)
-		scanSpan.SetStatus(otelcodes.Error, "Row iteration error")
-		scanSpan.SetAttributes(
-			attribute.Int("db.rows.scanned", scanCount),
-			attribute.Bool("db.iteration.error", true),
-			attribute.String("db.error.message", err.Error()),
-		)
-		scanSpan.End()
-		span.RecordError(err)
-		span.SetStatus(otelcodes.Error, fmt.Sprintf("Error iterating product rows: %v", err))
-		return nil, fmt.Errorf("error iterating product rows: %w", err)
+		p.recordRepositoryError(ctx, "list products", err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		logger.Error(fmt.Sprintf("Failed to list products from %s: %v", p.repo.Source(), err))
+		return nil, status.Errorf(codes.Internal, "Failed to list products: %v", err)
 	}
 
-	scanSpan.SetAttributes(
-		attribute.Int("db.rows.scanned", scanCount),
-		attribute.Bool("db.scan.success", true),
-	)
-	scanSpan.SetStatus(otelcodes.Ok, fmt.Sprintf("Scanned %d rows successfully", scanCount))
-	scanSpan.End()
-
+	products, nextPageToken := nextPage(products, q, func(p *pb.Product) *productCursor {
+		return newProductCursor(q.Sort, p, 0)
+	})
 	span.SetAttributes(
-		attribute.Int("db.rows_returned", len(products)),
-		attribute.Bool("db.operation.success", true),
+		attribute.Int("app.products.count", len(products)),
+		attribute.String("app.products.source", p.repo.Source()),
+		attribute.Bool("app.products.has_next_page", nextPageToken != ""),
 	)
-	span.SetStatus(otelcodes.Ok, "Products retrieved successfully")
-	return products, nil
+	return &pb.ListProductsResponse{Products: products, NextPageToken: nextPageToken}, nil
 }
 
-func getProductFromDB(ctx context.Context, id string) (*pb.Product, error) {
-	tracer := otel.Tracer("product-catalog")
-	ctx, span := tracer.Start(ctx, "db.product.get")
-	defer span.End()
-
+func (p *productCatalog) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.Product, error) {
+	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(
-		semconv.DBSystemPostgreSQL,
-		attribute.String("db.operation", "SELECT"),
-		attribute.String("db.sql.table", "products"),
-		attribute.String("app.product.id", id),
-	)
-
-	query := `SELECT id, name, description, picture, price_currency_code, price_units, price_nanos, categories
-	          FROM products WHERE id = $1`
-
-	span.SetAttributes(attribute.String("db.statement", query))
-
-	// Add 30-second timeout for statement execution
-	queryCtx, queryCancel := context.WithTimeout(ctx, 30*time.Second)
-	defer queryCancel()
-
-	// Execute query with explicit span for visibility
-	queryCtx, querySpan := tracer.Start(queryCtx, "db.query.execute")
-	querySpan.SetAttributes(
-		attribute.String("db.operation", "SELECT"),
-		attribute.String("db.sql.table", "products"),
-		attribute.String("db.statement.timeout", "30s"),
-	)
-	row := db.QueryRowContext(queryCtx, query, id)
-	querySpan.SetStatus(otelcodes.Ok, "Query executed successfully")
-	querySpan.End()
-
-	var product pb.Product
-	product.PriceUsd = &pb.Money{}
-	var categories pq.StringArray
-
-	// Scan with explicit span for visibility
-	ctx, scanSpan := tracer.Start(ctx, "db.row.scan")
-	scanSpan.SetAttributes(
-		attribute.String("db.operation", "scan"),
-	)
-	err := row.Scan(
-		&product.Id,
-		&product.Name,
-		&product.Description,
-		&product.Picture,
-		&product.PriceUsd.CurrencyCode,
-		&product.PriceUsd.Units,
-		&product.PriceUsd.Nanos,
-		&categories,
+		attribute.String("app.product.id", req.Id),
 	)
 
-	if err == sql.ErrNoRows {
-		scanSpan.SetStatus(otelcodes.Error, "Product not found")
-		scanSpan.SetAttributes(
-			attribute.Bool("db.query.not_found", true),
-		)
-		scanSpan.End()
-		span.SetAttributes(
-			attribute.Bool("db.query.not_found", true),
-			attribute.Bool("db.operation.success", false),
-		)
-		span.SetStatus(otelcodes.Error, fmt.Sprintf("Product not found: %s", id))
-		return nil, status.Errorf(codes.NotFound, "Product Not Found: %s", id)
+	// GetProduct will fail on a specific product when feature flag is enabled
+	if p.checkProductFailure(ctx, req.Id) {
+		msg := "Error: Product Catalog Fail Feature Flag Enabled"
+		span.SetStatus(otelcodes.Error, msg)
+		span.SetAttributes(attribute.String("error.message", msg))
+		return nil, status.Error(codes.Internal, msg)
 	}
 
+	found, err := p.repo.GetProduct(ctx, req.Id)
+	span.SetAttributes(attribute.String("app.products.source", p.repo.Source()))
+	if errors.Is(err, errProductNotFound) {
+		msg := fmt.Sprintf("Product Not Found: %s", req.Id)
+		span.SetStatus(otelcodes.Error, msg)
+		span.SetAttributes(attribute.String("error.message", msg))
+		return nil, status.Error(codes.NotFound, msg)
+	}
 	if err != nil {
-		scanSpan.RecordError(err)
-		scanSpan.SetStatus(otelcodes.Error, "Row scan failed")
-		scanSpan.SetAttributes(
-			attribute.Bool("db.scan.error", true),
-			attribute.String("db.error.message", err.Error()),
-		)
-		scanSpan.End()
-		span.RecordError(err)
-		span.SetStatus(otelcodes.Error, fmt.Sprintf("Database query failed: %v", err))
-		span.SetAttributes(
-			attribute.Bool("db.query.error", true),
-			attribute.String("db.error.message", err.Error()),
-		)
-		return nil, fmt.Errorf("failed to query product: %w", err)
+		p.recordRepositoryError(ctx, "get product", err, attribute.String("app.product.id", req.Id))
+		span.SetStatus(otelcodes.Error, err.Error())
+		logger.Error(fmt.Sprintf("Failed to get product from %s: %v", p.repo.Source(), err))
+		return nil, status.Errorf(codes.Internal, "Failed to get product: %v", err)
 	}
 
-	scanSpan.SetStatus(otelcodes.Ok, "Row scanned successfully")
-	scanSpan.End()
-
-	product.Categories = categories
-
 	span.SetAttributes(
-		attribute.Bool("db.operation.success", true),
-		attribute.String("app.product.name", product.Name),
+		attribute.String("app.product.id", req.Id),
+		attribute.String("app.product.name", found.Name),
+		attribute.Bool("app.product.found", true),
 	)
-	span.SetStatus(otelcodes.Ok, "Product retrieved successfully")
-	return &product, nil
-}
-
-func searchProductsFromDB(ctx context.Context, query string) ([]*pb.Product, error) {
-	tracer := otel.Tracer("product-catalog")
-	ctx, span := tracer.Start(ctx, "db.products.search")
-	defer span.End()
 
-	span.SetAttributes(
-		semconv.DBSystemPostgreSQL,
-		attribute.String("db.operation", "SELECT"),
-		attribute.String("db.sql.table", "products"),
-		attribute.String("app.search.query", query),
+	logger.LogAttrs(
+		ctx,
+		slog.LevelInfo, "Product Found",
+		slog.String("app.product.name", found.Name),
+		slog.String("app.product.id", req.Id),
 	)
 
-	sqlQuery := `SELECT id, name, description, picture, price_currency_code, price_units, price_nanos, categories
-	             FROM products
-	             WHERE name ILIKE $1 OR description ILIKE $1
-	             ORDER BY name`
+	return found, nil
+}
 
-	searchPattern := "%" + query + "%"
+func (p *productCatalog) GetProducts(ctx context.Context, req *pb.GetProductsRequest) (*pb.GetProductsResponse, error) {
+	span := trace.SpanFromContext(ctx)
 	span.SetAttributes(
-		attribute.String("db.statement", sqlQuery),
-		attribute.String("db.query.parameter", searchPattern),
+		attribute.StringSlice("app.product.ids", req.Ids),
+		attribute.Int("app.products.requested", len(req.Ids)),
 	)
 
-	// Add 30-second timeout for statement execution
-	queryCtx, queryCancel := context.WithTimeout(ctx, 30*time.Second)
-	defer queryCancel()
-
-	// Execute query with explicit span for visibility
-	queryCtx, querySpan := tracer.Start(queryCtx, "db.query.execute")
-	querySpan.SetAttributes(
-		attribute.String("db.operation", "SELECT"),
-		attribute.String("db.sql.table", "products"),
-		attribute.String("db.statement.timeout", "30s"),
-	)
-	rows, err := db.QueryContext(queryCtx, sqlQuery, searchPattern)
-	if err != nil {
-		querySpan.RecordError(err)
-		querySpan.SetStatus(otelcodes.Error, "Query execution failed")
-		querySpan.End()
-	} else {
-		querySpan.SetStatus(otelcodes.Ok, "Query executed successfully")
-		querySpan.End()
+	// GetProducts fails like GetProduct when the failing product is requested
+	for _, id := range req.Ids {
+		if p.checkProductFailure(ctx, id) {
+			msg := "Error: Product Catalog Fail Feature Flag Enabled"
+			span.SetStatus(otelcodes.Error, msg)
+			span.SetAttributes(attribute.String("error.message", msg))
+			return nil, status.Error(codes.Internal, msg)
+		}
 	}
 
+	found, err := p.repo.GetProducts(ctx, req.Ids)
+	span.SetAttributes(attribute.String("app.products.source", p.repo.Source()))
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(otelcodes.Error, fmt.Sprintf("Database query failed: %v", err))
-		span.SetAttributes(
-			attribute.Bool("db.query.error", true),
-			attribute.String("db.error.message", err.Error()),
-		)
-		return nil, fmt.Errorf("failed to search products: %w", err)
-	}
-	defer rows.Close()
-
-	span.SetAttributes(attribute.Bool("db.query.success", true))
-
-	// Scan rows with explicit span for visibility
-	var products []*pb.Product
-	ctx, scanSpan := tracer.Start(ctx, "db.rows.scan")
-	scanSpan.SetAttributes(
-		attribute.String("db.operation", "scan"),
-	)
-	scanCount := 0
-
-	for rows.Next() {
-		var product pb.Product
-		product.PriceUsd = &pb.Money{}
-		var categories pq.StringArray
-
-		err := rows.Scan(
-			&product.Id,
-			&product.Name,
-			&product.Description,
-			&product.Picture,
-			&product.PriceUsd.CurrencyCode,
-			&product.PriceUsd.Units,
-			&product.PriceUsd.Nanos,
-			&categories,
-		)
-
-		if err != nil {
-			scanSpan.RecordError(err)
-			scanSpan.SetStatus(otelcodes.Error, "Row scan failed")
-			scanSpan.SetAttributes(
-				attribute.Int("db.rows.scanned", scanCount),
-				attribute.Bool("db.scan.error", true),
-				attribute.String("db.error.message", err.Error()),
-			)
-			scanSpan.End()
-			span.RecordError(err)
-			span.SetStatus(otelcodes.Error, fmt.Sprintf("Failed to scan product row: %v", err))
-			return nil, fmt.Errorf("failed to scan product row: %w", err)
+		p.recordRepositoryError(ctx, "get products", err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		logger.Error(fmt.Sprintf("Failed to get products from %s: %v", p.repo.Source(), err))
+		return nil, status.Errorf(codes.Internal, "Failed to get products: %v", err)
+	}
+
+	resp := &pb.GetProductsResponse{Results: make([]*pb.ProductResult, len(req.Ids))}
+	missing := 0
+	for i, id := range req.Ids {
+		product, ok := found[id]
+		if !ok {
+			missing++
 		}
-
-		product.Categories = categories
-		products = append(products, &product)
-		scanCount++
-	}
-
-	if err = rows.Err(); err != nil {
-		scanSpan.RecordError(err)
-		scanSpan.SetStatus(otelcodes.Error, "Row iteration error")
-		scanSpan.SetAttributes(
-			attribute.Int("db.rows.scanned", scanCount),
-			attribute.Bool("db.iteration.error", true),
-			attribute.String("db.error.message", err.Error()),
-		)
-		scanSpan.End()
-		span.RecordError(err)
-		span.SetStatus(otelcodes.Error, fmt.Sprintf("Error iterating product rows: %v", err))
-		return nil, fmt.Errorf("error iterating product rows: %w", err)
+		resp.Results[i] = &pb.ProductResult{Id: id, Found: ok, Product: product}
 	}
 
-	scanSpan.SetAttributes(
-		attribute.Int("db.rows.scanned", scanCount),
-		attribute.Bool("db.scan.success", true),
-	)
-	scanSpan.SetStatus(otelcodes.Ok, fmt.Sprintf("Scanned %d rows successfully", scanCount))
-	scanSpan.End()
-
 	span.SetAttributes(
-		attribute.Int("db.rows_returned", len(products)),
-		attribute.Bool("db.operation.success", true),
+		attribute.Int("app.products.found", len(req.Ids)-missing),
+		attribute.Int("app.products.missing", missing),
 	)
-	span.SetStatus(otelcodes.Ok, "Products search completed successfully")
-	return products, nil
+	return resp, nil
 }
 
-func (p *productCatalog) ListProducts(ctx context.Context, req *pb.Empty) (*pb.ListProductsResponse, error) {
+func (p *productCatalog) SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
 	span := trace.SpanFromContext(ctx)
 
-	if useDatabase && db != nil {
-		products, err := listProductsFromDB(ctx)
-		if err != nil {
-			// Create explicit error span for visibility in traces
-			tracer := otel.Tracer("product-catalog")
-			_, errorSpan := tracer.Start(ctx, "ERROR: Failed to list products from database")
-			errorSpan.SetAttributes(
-				attribute.String("error.type", "database_query_failure"),
-				attribute.String("error.message", err.Error()),
-			)
-			errorSpan.RecordError(err)
-			errorSpan.SetStatus(otelcodes.Error, "Failed to list products from database")
-			errorSpan.End()
-
-			span.SetStatus(otelcodes.Error, err.Error())
-			logger.Error(fmt.Sprintf("Failed to list products from database: %v", err))
-			return nil, status.Errorf(codes.Internal, "Failed to list products: %v", err)
-		}
-		span.SetAttributes(
-			attribute.Int("app.products.count", len(products)),
-			attribute.String("app.products.source", "database"),
-		)
-		return &pb.ListProductsResponse{Products: products}, nil
+	q, err := newProductQuery(req, pb.ProductSortOrder_PRODUCT_SORT_ORDER_RELEVANCE)
+	if err != nil {
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
 	}
+	setProductQueryAttributes(span, req, q)
 
-	span.SetAttributes(
-		attribute.Int("app.products.count", len(catalog)),
-		attribute.String("app.products.source", "json"),
-	)
-	return &pb.ListProductsResponse{Products: catalog}, nil
-}
-
-func (p *productCatalog) GetProduct(ctx context.Context, req *pb.GetProductRequest) (*pb.Product, error) {
-	span := trace.SpanFromContext(ctx)
-	span.SetAttributes(
-		attribute.String("app.product.id", req.Id),
-	)
-
-	// GetProduct will fail on a specific product when feature flag is enabled
-	if p.checkProductFailure(ctx, req.Id) {
-		msg := "Error: Product Catalog Fail Feature Flag Enabled"
-		span.SetStatus(otelcodes.Error, msg)
-		span.SetAttributes(attribute.String("error.message", msg))
-		return nil, status.Errorf(codes.Internal, msg)
+	hits, err := p.repo.SearchProducts(ctx, req.Query, q)
+	span.SetAttributes(attribute.String("app.products.source", p.repo.Source()))
+	if err != nil {
+		p.recordRepositoryError(ctx, "search products", err, attribute.String("app.search.query", req.Query))
+		span.SetStatus(otelcodes.Error, err.Error())
+		logger.Error(fmt.Sprintf("Failed to search products from %s: %v", p.repo.Source(), err))
+		return nil, status.Errorf(codes.Internal, "Failed to search products: %v", err)
 	}
 
-	var found *pb.Product
-	var err error
-
-	if useDatabase && db != nil {
-		found, err = getProductFromDB(ctx, req.Id)
-		if err != nil {
-			// Create explicit error span for visibility in traces
-			tracer := otel.Tracer("product-catalog")
-			_, errorSpan := tracer.Start(ctx, "ERROR: Failed to get product from database")
-			errorSpan.SetAttributes(
-				attribute.String("error.type", "database_query_failure"),
-				attribute.String("error.message", err.Error()),
-				attribute.String("app.product.id", req.Id),
-			)
-			errorSpan.RecordError(err)
-			errorSpan.SetStatus(otelcodes.Error, "Failed to get product from database")
-			errorSpan.End()
-
-			span.SetStatus(otelcodes.Error, err.Error())
-			logger.Error(fmt.Sprintf("Failed to get product from database: %v", err))
-			return nil, err
-		}
-		span.SetAttributes(attribute.String("app.products.source", "database"))
-	} else {
-		for _, product := range catalog {
-			if req.Id == product.Id {
-				found = product
-				break
-			}
-		}
-		span.SetAttributes(attribute.String("app.products.source", "json"))
+	hits, nextPageToken := nextPage(hits, q, func(h ProductHit) *productCursor {
+		return newProductCursor(q.Sort, h.Product, h.Score)
+	})
 
-		if found == nil {
-			msg := fmt.Sprintf("Product Not Found: %s", req.Id)
-			span.SetStatus(otelcodes.Error, msg)
-			span.SetAttributes(attribute.String("error.message", msg))
-			return nil, status.Errorf(codes.NotFound, msg)
-		}
+	resp := &pb.SearchProductsResponse{
+		Results:       make([]*pb.Product, len(hits)),
+		Hits:          make([]*pb.SearchHit, len(hits)),
+		NextPageToken: nextPageToken,
+	}
+	for i, h := range hits {
+		resp.Results[i] = h.Product
+		resp.Hits[i] = &pb.SearchHit{ProductId: h.Product.Id, Score: h.Score, Snippet: h.Snippet}
 	}
 
 	span.SetAttributes(
-		attribute.String("app.product.id", req.Id),
-		attribute.String("app.product.name", found.Name),
-		attribute.Bool("app.product.found", true),
-	)
-
-	logger.LogAttrs(
-		ctx,
-		slog.LevelInfo, "Product Found",
-		slog.String("app.product.name", found.Name),
-		slog.String("app.product.id", req.Id),
+		attribute.Int("app.products_search.count", len(hits)),
+		attribute.Bool("app.products.has_next_page", nextPageToken != ""),
 	)
-
-	return found, nil
+	return resp, nil
 }
 
-func (p *productCatalog) SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
-	span := trace.SpanFromContext(ctx)
-
-	var result []*pb.Product
-	var err error
-
-	if useDatabase && db != nil {
-		result, err = searchProductsFromDB(ctx, req.Query)
-		if err != nil {
-			// Create explicit error span for visibility in traces
-			tracer := otel.Tracer("product-catalog")
-			_, errorSpan := tracer.Start(ctx, "ERROR: Failed to search products from database")
-			errorSpan.SetAttributes(
-				attribute.String("error.type", "database_query_failure"),
-				attribute.String("error.message", err.Error()),
-				attribute.String("app.search.query", req.Query),
-			)
-			errorSpan.RecordError(err)
-			errorSpan.SetStatus(otelcodes.Error, "Failed to search products from database")
-			errorSpan.End()
-
-			span.SetStatus(otelcodes.Error, err.Error())
-			logger.Error(fmt.Sprintf("Failed to search products from database: %v", err))
-			return nil, status.Errorf(codes.Internal, "Failed to search products: %v", err)
-		}
-		span.SetAttributes(attribute.String("app.products.source", "database"))
-	} else {
-		for _, product := range catalog {
-			if strings.Contains(strings.ToLower(product.Name), strings.ToLower(req.Query)) ||
-				strings.Contains(strings.ToLower(product.Description), strings.ToLower(req.Query)) {
-				result = append(result, product)
-			}
-		}
-		span.SetAttributes(attribute.String("app.products.source", "json"))
+func setProductQueryAttributes(span trace.Span, req pageRequest, q ProductQuery) {
+	span.SetAttributes(
+		attribute.Int("app.products.page_size", int(req.GetPageSize())),
+		attribute.Bool("app.products.page_token", q.After != nil),
+		attribute.String("app.products.sort", q.Sort.String()),
+	)
+	if len(q.Categories) > 0 {
+		span.SetAttributes(attribute.StringSlice("app.products.categories", q.Categories))
 	}
+}
 
-	span.SetAttributes(
-		attribute.Int("app.products_search.count", len(result)),
+// recordRepositoryError creates an explicit error span for visibility in
+// traces when the storage backend fails.
+func (p *productCatalog) recordRepositoryError(ctx context.Context, operation string, err error, attrs ...attribute.KeyValue) {
+	tracer := otel.Tracer("product-catalog")
+	name := fmt.Sprintf("Failed to %s from %s", operation, p.repo.Source())
+	_, errorSpan := tracer.Start(ctx, "ERROR: "+name)
+	errorSpan.SetAttributes(
+		attribute.String("error.type", p.repo.Source()+"_query_failure"),
+		attribute.String("error.message", err.Error()),
 	)
-	return &pb.SearchProductsResponse{Results: result}, nil
+	errorSpan.SetAttributes(attrs...)
+	errorSpan.RecordError(err)
+	errorSpan.SetStatus(otelcodes.Error, name)
+	errorSpan.End()
 }
 
 func (p *productCatalog) checkProductFailure(ctx context.Context, id string) bool {
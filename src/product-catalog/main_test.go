@@ -0,0 +1,244 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/product-catalog/genproto/oteldemo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var testProducts = []*pb.Product{
+	{Id: "TELESCOPE1", Name: "Refractor Telescope", Description: "A small telescope.", Categories: []string{"telescopes"}, PriceUsd: usd(349, 950000000)},
+	{Id: "BINOCULAR1", Name: "Roof Binoculars", Description: "Bird watching binoculars.", Categories: []string{"binoculars"}, PriceUsd: usd(209, 950000000)},
+	{Id: "LENSKIT1", Name: "Lens Cleaning Kit", Description: "Wipes and fluid for telescope lenses.", Categories: []string{"accessories", "telescopes"}, PriceUsd: usd(21, 950000000)},
+}
+
+func usd(units int64, nanos int32) *pb.Money {
+	return &pb.Money{CurrencyCode: "USD", Units: units, Nanos: nanos}
+}
+
+func productIDs(products []*pb.Product) []string {
+	var ids []string
+	for _, p := range products {
+		ids = append(ids, p.GetId())
+	}
+	return ids
+}
+
+func newTestCatalog() *productCatalog {
+	return &productCatalog{repo: newMemoryRepository(testProducts)}
+}
+
+func TestGetProduct(t *testing.T) {
+	p := newTestCatalog()
+
+	got, err := p.GetProduct(context.Background(), &pb.GetProductRequest{Id: "TELESCOPE1"})
+	if err != nil {
+		t.Fatalf("GetProduct() err = %v", err)
+	}
+	if got.GetName() != "Refractor Telescope" {
+		t.Errorf("GetProduct() name = %q, want %q", got.GetName(), "Refractor Telescope")
+	}
+
+	_, err = p.GetProduct(context.Background(), &pb.GetProductRequest{Id: "MISSING"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetProduct(MISSING) code = %v, want %v", status.Code(err), codes.NotFound)
+	}
+}
+
+func TestGetProducts(t *testing.T) {
+	p := newTestCatalog()
+
+	resp, err := p.GetProducts(context.Background(), &pb.GetProductsRequest{Ids: []string{"BINOCULAR1", "MISSING", "TELESCOPE1"}})
+	if err != nil {
+		t.Fatalf("GetProducts() err = %v", err)
+	}
+
+	want := []struct {
+		id    string
+		found bool
+	}{{"BINOCULAR1", true}, {"MISSING", false}, {"TELESCOPE1", true}}
+	if len(resp.GetResults()) != len(want) {
+		t.Fatalf("GetProducts() returned %d results, want %d", len(resp.GetResults()), len(want))
+	}
+	for i, w := range want {
+		r := resp.GetResults()[i]
+		if r.GetId() != w.id || r.GetFound() != w.found || (r.GetProduct() != nil) != w.found {
+			t.Errorf("result[%d] = {%q, found=%v, product=%v}, want {%q, found=%v}", i, r.GetId(), r.GetFound(), r.GetProduct(), w.id, w.found)
+		}
+	}
+}
+
+func TestSearchProducts(t *testing.T) {
+	p := newTestCatalog()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"telescope", []string{"TELESCOPE1", "LENSKIT1"}},
+		{"BIRD", []string{"BINOCULAR1"}},
+		{"watches", []string{"BINOCULAR1"}},
+		{"telescope lenses", []string{"LENSKIT1"}},
+		{"the", nil},
+		{"nothing", nil},
+		// Blank queries match all products, which tie on relevance.
+		{"", []string{"BINOCULAR1", "LENSKIT1", "TELESCOPE1"}},
+		{"  ", []string{"BINOCULAR1", "LENSKIT1", "TELESCOPE1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			resp, err := p.SearchProducts(context.Background(), &pb.SearchProductsRequest{Query: tt.query})
+			if err != nil {
+				t.Fatalf("SearchProducts() err = %v", err)
+			}
+			var got []string
+			for _, r := range resp.GetResults() {
+				got = append(got, r.GetId())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("SearchProducts(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("SearchProducts(%q) = %v, want %v", tt.query, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestListProductsPaging(t *testing.T) {
+	p := newTestCatalog()
+
+	tests := []struct {
+		sort pb.ProductSortOrder
+		want []string
+	}{
+		{pb.ProductSortOrder_PRODUCT_SORT_ORDER_UNSPECIFIED, []string{"LENSKIT1", "TELESCOPE1", "BINOCULAR1"}},
+		{pb.ProductSortOrder_PRODUCT_SORT_ORDER_NAME_DESC, []string{"BINOCULAR1", "TELESCOPE1", "LENSKIT1"}},
+		{pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_ASC, []string{"LENSKIT1", "BINOCULAR1", "TELESCOPE1"}},
+		{pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_DESC, []string{"TELESCOPE1", "BINOCULAR1", "LENSKIT1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort.String(), func(t *testing.T) {
+			var got []string
+			req := &pb.ListProductsRequest{PageSize: 2, Sort: tt.sort}
+			for pages := 0; ; pages++ {
+				if pages > len(tt.want) {
+					t.Fatalf("ListProducts() did not stop paging, got %v", got)
+				}
+				resp, err := p.ListProducts(context.Background(), req)
+				if err != nil {
+					t.Fatalf("ListProducts() err = %v", err)
+				}
+				got = append(got, productIDs(resp.GetProducts())...)
+				if resp.GetNextPageToken() == "" {
+					break
+				}
+				req.PageToken = resp.GetNextPageToken()
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListProducts() pages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListProductsFilter(t *testing.T) {
+	p := newTestCatalog()
+
+	tests := []struct {
+		name   string
+		filter *pb.ProductFilter
+		want   []string
+	}{
+		{"category", &pb.ProductFilter{Categories: []string{"telescopes"}}, []string{"LENSKIT1", "TELESCOPE1"}},
+		{"any category", &pb.ProductFilter{Categories: []string{"binoculars", "accessories"}}, []string{"LENSKIT1", "BINOCULAR1"}},
+		{"min price", &pb.ProductFilter{MinPriceUsd: usd(209, 950000000)}, []string{"TELESCOPE1", "BINOCULAR1"}},
+		{"max price", &pb.ProductFilter{MaxPriceUsd: usd(209, 0)}, []string{"LENSKIT1"}},
+		{"price range", &pb.ProductFilter{MinPriceUsd: usd(100, 0), MaxPriceUsd: usd(300, 0)}, []string{"BINOCULAR1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := p.ListProducts(context.Background(), &pb.ListProductsRequest{Filter: tt.filter})
+			if err != nil {
+				t.Fatalf("ListProducts() err = %v", err)
+			}
+			if got := productIDs(resp.GetProducts()); !slices.Equal(got, tt.want) {
+				t.Errorf("ListProducts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListProductsInvalidArgument(t *testing.T) {
+	p := newTestCatalog()
+
+	first, err := p.ListProducts(context.Background(), &pb.ListProductsRequest{PageSize: 1})
+	if err != nil {
+		t.Fatalf("ListProducts() err = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  *pb.ListProductsRequest
+	}{
+		{"negative page size", &pb.ListProductsRequest{PageSize: -1}},
+		{"malformed token", &pb.ListProductsRequest{PageToken: "not a token"}},
+		{"token for another sort", &pb.ListProductsRequest{PageToken: first.GetNextPageToken(), Sort: pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_ASC}},
+		{"unknown sort", &pb.ListProductsRequest{Sort: pb.ProductSortOrder(99)}},
+		{"non-USD price", &pb.ListProductsRequest{Filter: &pb.ProductFilter{MinPriceUsd: &pb.Money{CurrencyCode: "EUR", Units: 1}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.ListProducts(context.Background(), tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("ListProducts() code = %v, want %v", status.Code(err), codes.InvalidArgument)
+			}
+		})
+	}
+}
+
+func TestSearchProductsPaging(t *testing.T) {
+	p := newTestCatalog()
+
+	req := &pb.SearchProductsRequest{Query: "telescopes", PageSize: 1}
+	var got []string
+	for pages := 0; ; pages++ {
+		if pages > 2 {
+			t.Fatalf("SearchProducts() did not stop paging, got %v", got)
+		}
+		resp, err := p.SearchProducts(context.Background(), req)
+		if err != nil {
+			t.Fatalf("SearchProducts() err = %v", err)
+		}
+		if len(resp.GetHits()) != len(resp.GetResults()) {
+			t.Fatalf("SearchProducts() returned %d hits for %d results", len(resp.GetHits()), len(resp.GetResults()))
+		}
+		for _, h := range resp.GetHits() {
+			if !strings.Contains(h.GetSnippet(), "<b>telescope</b>") {
+				t.Errorf("hit %s snippet = %q, want a highlighted match", h.GetProductId(), h.GetSnippet())
+			}
+		}
+		got = append(got, productIDs(resp.GetResults())...)
+		if resp.GetNextPageToken() == "" {
+			break
+		}
+		req.PageToken = resp.GetNextPageToken()
+	}
+	if want := []string{"TELESCOPE1", "LENSKIT1"}; !slices.Equal(got, want) {
+		t.Errorf("SearchProducts() pages = %v, want %v", got, want)
+	}
+
+	_, err := p.ListProducts(context.Background(), &pb.ListProductsRequest{Sort: pb.ProductSortOrder_PRODUCT_SORT_ORDER_RELEVANCE})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListProducts(RELEVANCE) code = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+}
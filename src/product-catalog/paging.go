@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/product-catalog/genproto/oteldemo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxPageSize caps the page_size of ListProducts and SearchProducts.
+const maxPageSize = 1000
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+// pageRequest is the paging, filtering and ordering common to
+// ListProductsRequest and SearchProductsRequest.
+type pageRequest interface {
+	GetPageSize() int32
+	GetPageToken() string
+	GetFilter() *pb.ProductFilter
+	GetSort() pb.ProductSortOrder
+}
+
+// newProductQuery validates req and returns the query for its page, sorted
+// by defaultSort unless req says otherwise. The query fetches one product
+// more than the page size, which tells nextPage whether another page follows.
+func newProductQuery(req pageRequest, defaultSort pb.ProductSortOrder) (ProductQuery, error) {
+	q := ProductQuery{Sort: req.GetSort()}
+	if q.Sort == pb.ProductSortOrder_PRODUCT_SORT_ORDER_UNSPECIFIED {
+		q.Sort = defaultSort
+	}
+	if _, ok := pb.ProductSortOrder_name[int32(q.Sort)]; !ok {
+		return q, status.Errorf(codes.InvalidArgument, "unknown sort order %d", q.Sort)
+	}
+	if q.Sort == pb.ProductSortOrder_PRODUCT_SORT_ORDER_RELEVANCE && defaultSort != q.Sort {
+		return q, status.Error(codes.InvalidArgument, "relevance sort order is only valid for searches")
+	}
+
+	switch size := req.GetPageSize(); {
+	case size < 0:
+		return q, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	case size > 0:
+		q.Limit = int(min(size, maxPageSize)) + 1
+	}
+
+	if token := req.GetPageToken(); token != "" {
+		c, err := decodeCursor(token)
+		if err != nil {
+			return q, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if c.Sort != q.Sort {
+			return q, status.Error(codes.InvalidArgument, "page_token was issued for a different sort order")
+		}
+		q.After = c
+	}
+
+	filter := req.GetFilter()
+	q.Categories = filter.GetCategories()
+	for _, price := range []*pb.Money{filter.GetMinPriceUsd(), filter.GetMaxPriceUsd()} {
+		if price != nil && price.GetCurrencyCode() != "USD" {
+			return q, status.Errorf(codes.InvalidArgument, "price filters must be in USD, got %q", price.GetCurrencyCode())
+		}
+	}
+	q.MinPrice = filter.GetMinPriceUsd()
+	q.MaxPrice = filter.GetMaxPriceUsd()
+	return q, nil
+}
+
+// nextPage trims results fetched for q to the page size, and returns the
+// token for the following page, if any. cursor gives the position of a
+// result.
+func nextPage[T any](results []T, q ProductQuery, cursor func(T) *productCursor) ([]T, string) {
+	if q.Limit == 0 || len(results) < q.Limit {
+		return results, ""
+	}
+	results = results[:q.Limit-1]
+	return results, cursor(results[len(results)-1]).encode()
+}
+
+// productCursor is the position of a product in a sort order. Page tokens
+// are encoded cursors, so the next page can be fetched with keyset
+// pagination rather than an offset.
+type productCursor struct {
+	Sort  pb.ProductSortOrder `json:"s"`
+	Name  string              `json:"n,omitempty"`
+	Units int64               `json:"u,omitempty"`
+	Nanos int32               `json:"c,omitempty"`
+	Score float32             `json:"r,omitempty"`
+	ID    string              `json:"i"`
+}
+
+// newProductCursor returns the position of p when sorted by sort. score is
+// the relevance of p, for search results.
+func newProductCursor(sort pb.ProductSortOrder, p *pb.Product, score float32) *productCursor {
+	c := &productCursor{Sort: sort, ID: p.GetId()}
+	switch sort {
+	case pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_ASC, pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_DESC:
+		c.Units = p.GetPriceUsd().GetUnits()
+		c.Nanos = p.GetPriceUsd().GetNanos()
+	case pb.ProductSortOrder_PRODUCT_SORT_ORDER_RELEVANCE:
+		c.Score = score
+	default:
+		c.Name = p.GetName()
+	}
+	return c
+}
+
+// encode returns c as an opaque page token.
+func (c *productCursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor parses a page token returned by encode.
+func decodeCursor(token string) (*productCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+	var c productCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == "" {
+		return nil, errInvalidPageToken
+	}
+	return &c, nil
+}
+
+// compare orders c relative to o in their sort order.
+func (c *productCursor) compare(o *productCursor) int {
+	var r int
+	switch c.Sort {
+	case pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_ASC, pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_DESC:
+		r = cmp.Or(cmp.Compare(c.Units, o.Units), cmp.Compare(c.Nanos, o.Nanos))
+	case pb.ProductSortOrder_PRODUCT_SORT_ORDER_RELEVANCE:
+		r = cmp.Compare(o.Score, c.Score)
+	default:
+		r = strings.Compare(c.Name, o.Name)
+	}
+	r = cmp.Or(r, strings.Compare(c.ID, o.ID))
+
+	if c.Sort == pb.ProductSortOrder_PRODUCT_SORT_ORDER_NAME_DESC || c.Sort == pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_DESC {
+		return -r
+	}
+	return r
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/product-catalog/genproto/oteldemo"
+)
+
+// errProductNotFound is returned by ProductRepository.GetProduct when no
+// product has the requested id.
+var errProductNotFound = errors.New("product not found")
+
+// ProductQuery filters, orders and pages the products returned by
+// ProductRepository.ListProducts and ProductRepository.SearchProducts.
+type ProductQuery struct {
+	// Categories, if set, matches products in at least one of them.
+	Categories []string
+
+	// MinPrice and MaxPrice, if set, bound price_usd inclusively.
+	MinPrice, MaxPrice *pb.Money
+
+	// Sort orders the products, ties being broken by id. It is never
+	// PRODUCT_SORT_ORDER_UNSPECIFIED.
+	Sort pb.ProductSortOrder
+
+	// After, if set, skips the products up to and including the one the
+	// cursor was taken from.
+	After *productCursor
+
+	// Limit caps the number of products returned. Zero means no limit.
+	Limit int
+}
+
+// ProductHit is a product found by ProductRepository.SearchProducts.
+type ProductHit struct {
+	Product *pb.Product
+
+	// Score is the relevance of the product, comparable between the
+	// results of one query.
+	Score float32
+
+	// Snippet is an excerpt of the description with the matched words
+	// highlighted.
+	Snippet string
+}
+
+// ProductRepository is a storage backend for the product catalog.
+type ProductRepository interface {
+	// ListProducts returns the products matching q.
+	ListProducts(ctx context.Context, q ProductQuery) ([]*pb.Product, error)
+
+	// GetProduct returns the product with the given id, or
+	// errProductNotFound.
+	GetProduct(ctx context.Context, id string) (*pb.Product, error)
+
+	// GetProducts returns the products with the given ids, keyed by id.
+	// Unknown ids are absent from the result.
+	GetProducts(ctx context.Context, ids []string) (map[string]*pb.Product, error)
+
+	// SearchProducts returns the products matching q whose name or
+	// description contains all words of query, after stemming and
+	// ignoring stop words. A blank query matches all products, with a zero
+	// score and no snippet.
+	SearchProducts(ctx context.Context, query string, q ProductQuery) ([]ProductHit, error)
+
+	// Source names the backend, and is recorded as app.products.source.
+	Source() string
+}
@@ -0,0 +1,262 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/product-catalog/genproto/oteldemo"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// debounceDelay coalesces the burst of file-system events caused by a single
+// change, e.g. an editor save or a Kubernetes ConfigMap update.
+const debounceDelay = 500 * time.Millisecond
+
+// catalogSnapshot is an immutable, validated version of the JSON catalog.
+type catalogSnapshot struct {
+	generation int64
+	*memoryRepository
+}
+
+// jsonRepository serves the products defined in the .json files of a
+// directory. The catalog is reloaded when the directory changes, and swapped
+// atomically so readers always see a complete snapshot.
+type jsonRepository struct {
+	dir      string
+	snapshot atomic.Pointer[catalogSnapshot]
+
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+	stopped chan struct{}
+
+	reloads metric.Int64Counter
+}
+
+func newJSONRepository(dir string) (*jsonRepository, error) {
+	r := &jsonRepository{
+		dir:     dir,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	r.snapshot.Store(&catalogSnapshot{memoryRepository: newMemoryRepository(nil)})
+
+	meter := otel.Meter("product-catalog")
+	var err error
+	r.reloads, err = meter.Int64Counter(
+		"app.product_catalog.reloads",
+		metric.WithDescription("Number of JSON product catalog reloads, by result."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reload counter: %w", err)
+	}
+	_, err = meter.Int64ObservableGauge(
+		"app.product_catalog.generation",
+		metric.WithDescription("Generation of the JSON product catalog currently served."),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(r.snapshot.Load().generation)
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create generation gauge: %w", err)
+	}
+
+	if err := r.reload("startup"); err != nil {
+		fmt.Fprintf(os.Stderr, "[LOAD] ERROR: Error reading product files: %v. Serving an empty catalog.\n", err)
+	} else {
+		fmt.Fprintf(os.Stderr, "[LOAD] Successfully loaded %d products\n", len(r.snapshot.Load().products))
+	}
+
+	r.watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	if err := r.watcher.Add(dir); err != nil {
+		r.watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+	go r.watch()
+
+	return r, nil
+}
+
+// watch reloads the catalog after file-system events settle, until Close.
+func (r *jsonRepository) watch() {
+	defer close(r.stopped)
+
+	debounce := time.NewTimer(debounceDelay)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Chmod) && !event.Has(fsnotify.Write) {
+				continue
+			}
+			debounce.Reset(debounceDelay)
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Error(fmt.Sprintf("Product catalog watcher error: %v", err))
+		case <-debounce.C:
+			// The previous snapshot keeps being served if the reload fails.
+			_ = r.reload("file change")
+		}
+	}
+}
+
+// reload reads and validates the catalog files, then swaps them in.
+func (r *jsonRepository) reload(reason string) error {
+	ctx := context.Background()
+	current := r.snapshot.Load()
+
+	products, err := readProductFiles(r.dir)
+	if err == nil {
+		err = validateCatalog(products)
+	}
+	if err != nil {
+		r.reloads.Add(ctx, 1, metric.WithAttributes(attribute.String("app.product_catalog.reload.result", "failure")))
+		logger.LogAttrs(
+			ctx,
+			slog.LevelError, fmt.Sprintf("Product catalog reload failed, keeping generation %d: %v", current.generation, err),
+			slog.String("app.product_catalog.reload.reason", reason),
+			slog.Int64("app.product_catalog.generation", current.generation),
+		)
+		return err
+	}
+
+	next := &catalogSnapshot{
+		generation:       current.generation + 1,
+		memoryRepository: newMemoryRepository(products),
+	}
+	r.snapshot.Store(next)
+
+	r.reloads.Add(ctx, 1, metric.WithAttributes(attribute.String("app.product_catalog.reload.result", "success")))
+	logger.LogAttrs(
+		ctx,
+		slog.LevelInfo, fmt.Sprintf("Product catalog generation %d loaded", next.generation),
+		slog.String("app.product_catalog.reload.reason", reason),
+		slog.Int64("app.product_catalog.generation", next.generation),
+		slog.Int("products", len(products)),
+	)
+	return nil
+}
+
+// Close stops watching the catalog directory.
+func (r *jsonRepository) Close() error {
+	close(r.done)
+	err := r.watcher.Close()
+	<-r.stopped
+	return err
+}
+
+func (r *jsonRepository) Source() string { return "json" }
+
+func (r *jsonRepository) ListProducts(ctx context.Context, q ProductQuery) ([]*pb.Product, error) {
+	return r.snapshot.Load().ListProducts(ctx, q)
+}
+
+func (r *jsonRepository) GetProduct(ctx context.Context, id string) (*pb.Product, error) {
+	return r.snapshot.Load().GetProduct(ctx, id)
+}
+
+func (r *jsonRepository) GetProducts(ctx context.Context, ids []string) (map[string]*pb.Product, error) {
+	return r.snapshot.Load().GetProducts(ctx, ids)
+}
+
+func (r *jsonRepository) SearchProducts(ctx context.Context, query string, q ProductQuery) ([]ProductHit, error) {
+	return r.snapshot.Load().SearchProducts(ctx, query, q)
+}
+
+// validateCatalog rejects catalogs that would break the handlers, so a
+// half-written or malformed file never replaces a working catalog.
+func validateCatalog(products []*pb.Product) error {
+	if len(products) == 0 {
+		return errors.New("catalog contains no products")
+	}
+	seen := make(map[string]bool, len(products))
+	for i, p := range products {
+		switch {
+		case p.GetId() == "":
+			return fmt.Errorf("product #%d has no id", i)
+		case seen[p.GetId()]:
+			return fmt.Errorf("duplicate product id %q", p.GetId())
+		case p.GetName() == "":
+			return fmt.Errorf("product %q has no name", p.GetId())
+		case p.GetPriceUsd() == nil || p.GetPriceUsd().GetCurrencyCode() == "":
+			return fmt.Errorf("product %q has no price", p.GetId())
+		case p.GetPriceUsd().GetUnits() < 0 || p.GetPriceUsd().GetNanos() < 0 || p.GetPriceUsd().GetNanos() > 999999999:
+			return fmt.Errorf("product %q has an invalid price", p.GetId())
+		}
+		seen[p.GetId()] = true
+	}
+	return nil
+}
+
+func readProductFiles(dir string) ([]*pb.Product, error) {
+
+	// find all .json files in the products directory
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonFiles := make([]fs.FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".json") {
+			info, err := entry.Info()
+			if err != nil {
+				return nil, err
+			}
+			jsonFiles = append(jsonFiles, info)
+		}
+	}
+
+	// read the contents of each .json file and unmarshal into a ListProductsResponse
+	// then append the products to the catalog
+	var products []*pb.Product
+	for _, f := range jsonFiles {
+		jsonData, err := os.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var res pb.ListProductsResponse
+		if err := protojson.Unmarshal(jsonData, &res); err != nil {
+			return nil, err
+		}
+
+		products = append(products, res.Products...)
+	}
+
+	logger.LogAttrs(
+		context.Background(),
+		slog.LevelInfo,
+		fmt.Sprintf("Loaded %d products\n", len(products)),
+		slog.Int("products", len(products)),
+	)
+
+	return products, nil
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/product-catalog/genproto/oteldemo"
+)
+
+const catalogV1 = `{"products": [{"id": "TELESCOPE1", "name": "Refractor Telescope", "priceUsd": {"currencyCode": "USD", "units": 100}}]}`
+
+const catalogV2 = `{"products": [
+	{"id": "TELESCOPE1", "name": "Refractor Telescope", "priceUsd": {"currencyCode": "USD", "units": 100}},
+	{"id": "BINOCULAR1", "name": "Roof Binoculars", "priceUsd": {"currencyCode": "USD", "units": 50}}
+]}`
+
+func writeCatalog(t *testing.T, dir, contents string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "products.json"), []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitForGeneration polls until the repository serves generation want.
+func waitForGeneration(t *testing.T, r *jsonRepository, want int64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for r.snapshot.Load().generation != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("generation = %d, want %d", r.snapshot.Load().generation, want)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestJSONRepositoryReload(t *testing.T) {
+	dir := t.TempDir()
+	writeCatalog(t, dir, catalogV1)
+
+	r, err := newJSONRepository(dir)
+	if err != nil {
+		t.Fatalf("newJSONRepository() err = %v", err)
+	}
+	defer r.Close()
+	waitForGeneration(t, r, 1)
+
+	writeCatalog(t, dir, catalogV2)
+	waitForGeneration(t, r, 2)
+	products, _ := r.ListProducts(context.Background(), ProductQuery{})
+	if len(products) != 2 {
+		t.Fatalf("ListProducts() returned %d products, want 2", len(products))
+	}
+
+	// An invalid catalog must not replace the current one.
+	writeCatalog(t, dir, `{"products": [{"id": "", "name": "Nameless"}]}`)
+	time.Sleep(3 * debounceDelay)
+	waitForGeneration(t, r, 2)
+	if _, err := r.GetProduct(context.Background(), "BINOCULAR1"); err != nil {
+		t.Errorf("GetProduct() after invalid reload err = %v", err)
+	}
+}
+
+func TestValidateCatalog(t *testing.T) {
+	price := func(units int64, nanos int32) *pb.Money {
+		return &pb.Money{CurrencyCode: "USD", Units: units, Nanos: nanos}
+	}
+	tests := []struct {
+		name     string
+		products []*pb.Product
+		wantErr  bool
+	}{
+		{"valid", []*pb.Product{{Id: "A", Name: "a", PriceUsd: price(1, 0)}}, false},
+		{"empty", nil, true},
+		{"missing id", []*pb.Product{{Name: "a", PriceUsd: price(1, 0)}}, true},
+		{"duplicate id", []*pb.Product{{Id: "A", Name: "a", PriceUsd: price(1, 0)}, {Id: "A", Name: "b", PriceUsd: price(1, 0)}}, true},
+		{"missing name", []*pb.Product{{Id: "A", PriceUsd: price(1, 0)}}, true},
+		{"missing price", []*pb.Product{{Id: "A", Name: "a"}}, true},
+		{"negative price", []*pb.Product{{Id: "A", Name: "a", PriceUsd: price(-1, 0)}}, true},
+		{"nanos out of range", []*pb.Product{{Id: "A", Name: "a", PriceUsd: price(1, 1_000_000_000)}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateCatalog(tt.products); (err != nil) != tt.wantErr {
+				t.Errorf("validateCatalog() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/product-catalog/genproto/oteldemo"
+)
+
+// memoryRepository serves a fixed set of products from memory.
+type memoryRepository struct {
+	products []*pb.Product
+	byID     map[string]*pb.Product
+	index    *searchIndex
+}
+
+func newMemoryRepository(products []*pb.Product) *memoryRepository {
+	byID := make(map[string]*pb.Product, len(products))
+	for _, p := range products {
+		byID[p.Id] = p
+	}
+	return &memoryRepository{products: products, byID: byID, index: newSearchIndex(products)}
+}
+
+func (r *memoryRepository) Source() string { return "memory" }
+
+func (r *memoryRepository) ListProducts(_ context.Context, q ProductQuery) ([]*pb.Product, error) {
+	hits := make([]ProductHit, len(r.products))
+	for i, p := range r.products {
+		hits[i] = ProductHit{Product: p}
+	}
+
+	hits = applyQuery(hits, q)
+	products := make([]*pb.Product, len(hits))
+	for i, h := range hits {
+		products[i] = h.Product
+	}
+	return products, nil
+}
+
+func (r *memoryRepository) GetProduct(_ context.Context, id string) (*pb.Product, error) {
+	if p, ok := r.byID[id]; ok {
+		return p, nil
+	}
+	return nil, errProductNotFound
+}
+
+func (r *memoryRepository) GetProducts(_ context.Context, ids []string) (map[string]*pb.Product, error) {
+	found := make(map[string]*pb.Product, len(ids))
+	for _, id := range ids {
+		if p, ok := r.byID[id]; ok {
+			found[id] = p
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryRepository) SearchProducts(_ context.Context, query string, q ProductQuery) ([]ProductHit, error) {
+	if strings.TrimSpace(query) == "" {
+		hits := make([]ProductHit, len(r.products))
+		for i, p := range r.products {
+			hits[i] = ProductHit{Product: p}
+		}
+		return applyQuery(hits, q), nil
+	}
+
+	matches := r.index.search(query)
+	hits := make([]ProductHit, 0, len(matches))
+	for _, m := range matches {
+		hits = append(hits, ProductHit{Product: r.products[m.doc], Score: m.score})
+	}
+
+	hits = applyQuery(hits, q)
+	// Snippets are only worth building for the page returned.
+	for i := range hits {
+		hits[i].Snippet = snippet(hits[i].Product.Description, query)
+	}
+	return hits, nil
+}
+
+// applyQuery filters, sorts and pages hits in memory.
+func applyQuery(hits []ProductHit, q ProductQuery) []ProductHit {
+	type positioned struct {
+		hit    ProductHit
+		cursor *productCursor
+	}
+
+	var matched []positioned
+	for _, h := range hits {
+		if !matchesQuery(h.Product, q) {
+			continue
+		}
+		c := newProductCursor(q.Sort, h.Product, h.Score)
+		if q.After != nil && c.compare(q.After) <= 0 {
+			continue
+		}
+		matched = append(matched, positioned{h, c})
+	}
+
+	slices.SortFunc(matched, func(a, b positioned) int { return a.cursor.compare(b.cursor) })
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+	}
+
+	result := make([]ProductHit, len(matched))
+	for i, m := range matched {
+		result[i] = m.hit
+	}
+	return result
+}
+
+// matchesQuery reports whether p passes the category and price filters of q.
+func matchesQuery(p *pb.Product, q ProductQuery) bool {
+	if len(q.Categories) > 0 && !slices.ContainsFunc(p.Categories, func(c string) bool {
+		return slices.Contains(q.Categories, c)
+	}) {
+		return false
+	}
+	if q.MinPrice != nil && compareMoney(p.PriceUsd, q.MinPrice) < 0 {
+		return false
+	}
+	if q.MaxPrice != nil && compareMoney(p.PriceUsd, q.MaxPrice) > 0 {
+		return false
+	}
+	return true
+}
+
+func compareMoney(a, b *pb.Money) int {
+	return cmp.Or(cmp.Compare(a.GetUnits(), b.GetUnits()), cmp.Compare(a.GetNanos(), b.GetNanos()))
+}
@@ -0,0 +1,357 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/product-catalog/genproto/oteldemo"
+)
+
+const productColumns = `id, name, description, picture, price_currency_code, price_units, price_nanos, categories`
+
+// searchRank ranks matches of the tsquery tsq against the search_vector
+// column, normalized by document length like the JSON backend's searchIndex.
+const searchRank = `ts_rank(search_vector, tsq, 1)`
+
+// searchColumns are selected after productColumns by SearchProducts.
+var searchColumns = fmt.Sprintf(`%s AS rank, ts_headline('english', coalesce(description, ''), tsq, 'StartSel=%s, StopSel=%s, MaxWords=%d')`,
+	searchRank, snippetStartSel, snippetStopSel, snippetMaxWords)
+
+// blankSearchRank and blankSearchColumns replace searchRank and
+// searchColumns for blank queries, which match all products.
+const (
+	blankSearchRank    = `0::real`
+	blankSearchColumns = blankSearchRank + ` AS rank, ''`
+)
+
+// postgresRepository serves products from the products table.
+type postgresRepository struct {
+	db *sql.DB
+}
+
+func newPostgresRepository(db *sql.DB) *postgresRepository {
+	return &postgresRepository{db: db}
+}
+
+func (r *postgresRepository) Source() string { return "database" }
+
+func (r *postgresRepository) ListProducts(ctx context.Context, q ProductQuery) ([]*pb.Product, error) {
+	query, args := selectProducts(productSelect{}, q)
+
+	products, err := r.queryProducts(ctx, "db.products.list", query, productQueryAttributes(q), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query products: %w", err)
+	}
+	return products, nil
+}
+
+func (r *postgresRepository) GetProduct(ctx context.Context, id string) (*pb.Product, error) {
+	query := `SELECT ` + productColumns + `
+	          FROM products WHERE id = $1`
+
+	products, err := r.queryProducts(ctx, "db.product.get", query,
+		[]attribute.KeyValue{attribute.String("app.product.id", id)}, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query product: %w", err)
+	}
+	if len(products) == 0 {
+		return nil, errProductNotFound
+	}
+	return products[0], nil
+}
+
+func (r *postgresRepository) GetProducts(ctx context.Context, ids []string) (map[string]*pb.Product, error) {
+	query := `SELECT ` + productColumns + `
+	          FROM products WHERE id = ANY($1)`
+
+	products, err := r.queryProducts(ctx, "db.products.get_batch", query,
+		[]attribute.KeyValue{attribute.Int("app.products.requested", len(ids))}, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query products: %w", err)
+	}
+
+	found := make(map[string]*pb.Product, len(products))
+	for _, p := range products {
+		found[p.Id] = p
+	}
+	return found, nil
+}
+
+func (r *postgresRepository) SearchProducts(ctx context.Context, query string, q ProductQuery) ([]ProductHit, error) {
+	s := productSelect{
+		columns: searchColumns,
+		from:    `plainto_tsquery('english', $1) tsq`,
+		where:   []string{"search_vector @@ tsq"},
+		args:    []any{query},
+		rank:    searchRank,
+	}
+	if strings.TrimSpace(query) == "" {
+		// All products match, equally.
+		s = productSelect{columns: blankSearchColumns, rank: blankSearchRank}
+	}
+	sqlQuery, args := selectProducts(s, q)
+
+	hits, err := queryRows(ctx, r.db, "db.products.search", sqlQuery,
+		append(productQueryAttributes(q),
+			attribute.String("app.search.query", query),
+			attribute.String("db.query.parameter", query),
+		), scanHit, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+	return hits, nil
+}
+
+// productSelect is a SELECT of productColumns from products, extended by
+// the caller.
+type productSelect struct {
+	// columns are selected after productColumns.
+	columns string
+
+	// from is joined with products.
+	from string
+
+	// where are conditions on the rows, with parameters args.
+	where []string
+	args  []any
+
+	// rank is the relevance of a row, for PRODUCT_SORT_ORDER_RELEVANCE.
+	rank string
+}
+
+// selectProducts builds the query for s with the filters, keyset and
+// ordering of q. Parameters for q are numbered after s.args.
+func selectProducts(s productSelect, q ProductQuery) (string, []any) {
+	where, args := s.where, s.args
+	param := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if len(q.Categories) > 0 {
+		where = append(where, "categories && "+param(pq.Array(q.Categories)))
+	}
+	if q.MinPrice != nil {
+		where = append(where, fmt.Sprintf("(price_units, price_nanos) >= (%s, %s)",
+			param(q.MinPrice.GetUnits()), param(q.MinPrice.GetNanos())))
+	}
+	if q.MaxPrice != nil {
+		where = append(where, fmt.Sprintf("(price_units, price_nanos) <= (%s, %s)",
+			param(q.MaxPrice.GetUnits()), param(q.MaxPrice.GetNanos())))
+	}
+
+	keys := []string{"name", "id"}
+	if q.Sort == pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_ASC || q.Sort == pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_DESC {
+		keys = []string{"price_units", "price_nanos", "id"}
+	}
+	desc := q.Sort == pb.ProductSortOrder_PRODUCT_SORT_ORDER_NAME_DESC || q.Sort == pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_DESC
+	byRank := q.Sort == pb.ProductSortOrder_PRODUCT_SORT_ORDER_RELEVANCE && s.rank != ""
+
+	// Keyset pagination: continue strictly after the cursor in sort order,
+	// which the row comparison can serve from the (keys...) index. Ranks
+	// descend while ids ascend, so they cannot use a row comparison.
+	if byRank && q.After != nil {
+		rank, id := param(q.After.Score), param(q.After.ID)
+		where = append(where, fmt.Sprintf("(%s < %s OR (%s = %s AND id > %s))", s.rank, rank, s.rank, rank, id))
+	} else if q.After != nil {
+		var values []string
+		if keys[0] == "name" {
+			values = []string{param(q.After.Name), param(q.After.ID)}
+		} else {
+			values = []string{param(q.After.Units), param(q.After.Nanos), param(q.After.ID)}
+		}
+		op := ">"
+		if desc {
+			op = "<"
+		}
+		where = append(where, fmt.Sprintf("(%s) %s (%s)", strings.Join(keys, ", "), op, strings.Join(values, ", ")))
+	}
+
+	query := `SELECT ` + productColumns
+	if s.columns != "" {
+		query += `, ` + s.columns
+	}
+	query += ` FROM products`
+	if s.from != "" {
+		query += `, ` + s.from
+	}
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+
+	order := make([]string, len(keys))
+	for i, k := range keys {
+		order[i] = k
+		if desc {
+			order[i] += " DESC"
+		}
+	}
+	if byRank {
+		order = []string{s.rank + " DESC", "id"}
+	}
+	query += ` ORDER BY ` + strings.Join(order, ", ")
+
+	if q.Limit > 0 {
+		query += ` LIMIT ` + param(q.Limit)
+	}
+	return query, args
+}
+
+func productQueryAttributes(q ProductQuery) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("app.products.sort", q.Sort.String()),
+		attribute.Int("app.products.limit", q.Limit),
+		attribute.Bool("app.products.keyset", q.After != nil),
+	}
+}
+
+// queryProducts runs a SELECT of productColumns and scans the resulting rows.
+func (r *postgresRepository) queryProducts(ctx context.Context, spanName, query string, attrs []attribute.KeyValue, args ...any) ([]*pb.Product, error) {
+	return queryRows(ctx, r.db, spanName, query, attrs, scanProduct, args...)
+}
+
+// queryRows runs a SELECT on products and scans the resulting rows with
+// scan, recording the query and the scan as separate spans.
+func queryRows[T any](ctx context.Context, db *sql.DB, spanName, query string, attrs []attribute.KeyValue, scan func(*sql.Rows) (T, error), args ...any) ([]T, error) {
+	tracer := otel.Tracer("product-catalog")
+	ctx, span := tracer.Start(ctx, spanName)
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBSystemPostgreSQL,
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.sql.table", "products"),
+		attribute.String("db.statement", query),
+	)
+	span.SetAttributes(attrs...)
+
+	// Add 30-second timeout for statement execution
+	queryCtx, queryCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer queryCancel()
+
+	// Execute query with explicit span for visibility
+	queryCtx, querySpan := tracer.Start(queryCtx, "db.query.execute")
+	querySpan.SetAttributes(
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.sql.table", "products"),
+		attribute.String("db.statement.timeout", "30s"),
+	)
+	rows, err := db.QueryContext(queryCtx, query, args...)
+	if err != nil {
+		querySpan.RecordError(err)
+		querySpan.SetStatus(otelcodes.Error, "Query execution failed")
+		querySpan.End()
+
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, fmt.Sprintf("Database query failed: %v", err))
+		span.SetAttributes(
+			attribute.Bool("db.query.error", true),
+			attribute.String("db.error.message", err.Error()),
+		)
+		return nil, err
+	}
+	querySpan.SetStatus(otelcodes.Ok, "Query executed successfully")
+	querySpan.End()
+	defer rows.Close()
+
+	span.SetAttributes(attribute.Bool("db.query.success", true))
+
+	// Scan rows with explicit span for visibility
+	_, scanSpan := tracer.Start(ctx, "db.rows.scan")
+	scanSpan.SetAttributes(
+		attribute.String("db.operation", "scan"),
+	)
+	defer scanSpan.End()
+
+	var products []T
+	for rows.Next() {
+		product, err := scan(rows)
+		if err != nil {
+			scanSpan.RecordError(err)
+			scanSpan.SetStatus(otelcodes.Error, "Row scan failed")
+			scanSpan.SetAttributes(
+				attribute.Int("db.rows.scanned", len(products)),
+				attribute.Bool("db.scan.error", true),
+				attribute.String("db.error.message", err.Error()),
+			)
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, fmt.Sprintf("Failed to scan product row: %v", err))
+			return nil, fmt.Errorf("failed to scan product row: %w", err)
+		}
+		products = append(products, product)
+	}
+
+	if err = rows.Err(); err != nil {
+		scanSpan.RecordError(err)
+		scanSpan.SetStatus(otelcodes.Error, "Row iteration error")
+		scanSpan.SetAttributes(
+			attribute.Int("db.rows.scanned", len(products)),
+			attribute.Bool("db.iteration.error", true),
+			attribute.String("db.error.message", err.Error()),
+		)
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, fmt.Sprintf("Error iterating product rows: %v", err))
+		return nil, fmt.Errorf("error iterating product rows: %w", err)
+	}
+
+	scanSpan.SetAttributes(
+		attribute.Int("db.rows.scanned", len(products)),
+		attribute.Bool("db.scan.success", true),
+	)
+	scanSpan.SetStatus(otelcodes.Ok, fmt.Sprintf("Scanned %d rows successfully", len(products)))
+
+	span.SetAttributes(
+		attribute.Int("db.rows_returned", len(products)),
+		attribute.Bool("db.operation.success", true),
+	)
+	span.SetStatus(otelcodes.Ok, "Products retrieved successfully")
+	return products, nil
+}
+
+// scanProduct scans a row of productColumns.
+func scanProduct(rows *sql.Rows) (*pb.Product, error) {
+	return scanProductWith(rows)
+}
+
+// scanHit scans a row of productColumns and searchColumns.
+func scanHit(rows *sql.Rows) (ProductHit, error) {
+	var hit ProductHit
+	var err error
+	hit.Product, err = scanProductWith(rows, &hit.Score, &hit.Snippet)
+	return hit, err
+}
+
+// scanProductWith scans a row of productColumns followed by columns scanned
+// into extra.
+func scanProductWith(rows *sql.Rows, extra ...any) (*pb.Product, error) {
+	var product pb.Product
+	product.PriceUsd = &pb.Money{}
+	var categories pq.StringArray
+
+	dest := []any{
+		&product.Id,
+		&product.Name,
+		&product.Description,
+		&product.Picture,
+		&product.PriceUsd.CurrencyCode,
+		&product.PriceUsd.Units,
+		&product.PriceUsd.Nanos,
+		&categories,
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	product.Categories = categories
+	return &product, nil
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"testing"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/product-catalog/genproto/oteldemo"
+)
+
+func TestSelectProducts(t *testing.T) {
+	const selectAll = `SELECT ` + productColumns + ` FROM products`
+
+	tests := []struct {
+		name     string
+		s        productSelect
+		q        ProductQuery
+		want     string
+		wantArgs int
+	}{
+		{
+			name: "all",
+			q:    ProductQuery{Sort: pb.ProductSortOrder_PRODUCT_SORT_ORDER_NAME_ASC},
+			want: selectAll + ` ORDER BY name, id`,
+		},
+		{
+			name: "first page",
+			q:    ProductQuery{Sort: pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_ASC, Limit: 11},
+			want: selectAll + ` ORDER BY price_units, price_nanos, id LIMIT $1`, wantArgs: 1,
+		},
+		{
+			name: "next page descending",
+			q: ProductQuery{
+				Sort:  pb.ProductSortOrder_PRODUCT_SORT_ORDER_NAME_DESC,
+				After: &productCursor{Sort: pb.ProductSortOrder_PRODUCT_SORT_ORDER_NAME_DESC, Name: "Lens", ID: "L9ECAV7KIM"},
+				Limit: 11,
+			},
+			want:     selectAll + ` WHERE (name, id) < ($1, $2) ORDER BY name DESC, id DESC LIMIT $3`,
+			wantArgs: 3,
+		},
+		{
+			name: "filters",
+			s:    productSelect{where: []string{"picture <> $1"}, args: []any{""}},
+			q: ProductQuery{
+				Categories: []string{"telescopes"},
+				MinPrice:   &pb.Money{CurrencyCode: "USD", Units: 100},
+				MaxPrice:   &pb.Money{CurrencyCode: "USD", Units: 200},
+				Sort:       pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_DESC,
+				After:      &productCursor{Sort: pb.ProductSortOrder_PRODUCT_SORT_ORDER_PRICE_DESC, Units: 175, ID: "0PUK6V6EV0"},
+			},
+			want: selectAll + ` WHERE picture <> $1 AND categories && $2` +
+				` AND (price_units, price_nanos) >= ($3, $4) AND (price_units, price_nanos) <= ($5, $6)` +
+				` AND (price_units, price_nanos, id) < ($7, $8, $9)` +
+				` ORDER BY price_units DESC, price_nanos DESC, id DESC`,
+			wantArgs: 9,
+		},
+		{
+			name: "search by relevance",
+			s: productSelect{
+				columns: "rank",
+				from:    "tsq",
+				where:   []string{"search_vector @@ tsq"},
+				args:    []any{"telescope"},
+				rank:    "rank",
+			},
+			q: ProductQuery{
+				Sort:  pb.ProductSortOrder_PRODUCT_SORT_ORDER_RELEVANCE,
+				After: &productCursor{Sort: pb.ProductSortOrder_PRODUCT_SORT_ORDER_RELEVANCE, Score: 0.5, ID: "0PUK6V6EV0"},
+				Limit: 11,
+			},
+			want: `SELECT ` + productColumns + `, rank FROM products, tsq` +
+				` WHERE search_vector @@ tsq AND (rank < $2 OR (rank = $2 AND id > $3))` +
+				` ORDER BY rank DESC, id LIMIT $4`,
+			wantArgs: 4,
+		},
+		{
+			name: "blank search",
+			s:    productSelect{columns: blankSearchColumns, rank: blankSearchRank},
+			q: ProductQuery{
+				Sort:  pb.ProductSortOrder_PRODUCT_SORT_ORDER_RELEVANCE,
+				After: &productCursor{Sort: pb.ProductSortOrder_PRODUCT_SORT_ORDER_RELEVANCE, ID: "0PUK6V6EV0"},
+			},
+			want: `SELECT ` + productColumns + `, 0::real AS rank, '' FROM products` +
+				` WHERE (0::real < $1 OR (0::real = $1 AND id > $2))` +
+				` ORDER BY 0::real DESC, id`,
+			wantArgs: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args := selectProducts(tt.s, tt.q)
+			if got != tt.want {
+				t.Errorf("selectProducts() query =\n%s\nwant\n%s", got, tt.want)
+			}
+			if len(args) != tt.wantArgs {
+				t.Errorf("selectProducts() has %d args, want %d", len(args), tt.wantArgs)
+			}
+		})
+	}
+}
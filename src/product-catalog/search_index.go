@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/kljensen/snowball/english"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/product-catalog/genproto/oteldemo"
+)
+
+// Weights of name and description matches. They are the ts_rank defaults
+// for the A and B labels that the Postgres search_vector column uses.
+const (
+	nameWeight        = 1.0
+	descriptionWeight = 0.4
+)
+
+// Headline settings matching the ts_headline defaults.
+const (
+	snippetMaxWords = 35
+	snippetStartSel = "<b>"
+	snippetStopSel  = "</b>"
+)
+
+// searchIndex is an inverted index over product names and descriptions. It
+// analyzes text like the Postgres 'english' configuration (lower-casing,
+// Snowball stemming and stop words) and ranks matches like ts_rank with
+// normalization 1, so the JSON and database backends order results alike.
+// Multi-word queries average the rank of each word, where ts_rank also
+// rewards words that appear close together.
+type searchIndex struct {
+	postings map[string][]posting
+	lengths  []int
+}
+
+// posting records the occurrences of a term in a product.
+type posting struct {
+	doc     int
+	weights []float64
+}
+
+// searchMatch is a product matching a query, by index in the indexed slice.
+type searchMatch struct {
+	doc   int
+	score float32
+}
+
+// word is a word of a text, and its lexeme if it is not a stop word.
+type word struct {
+	start, end int
+	lexeme     string
+}
+
+func newSearchIndex(products []*pb.Product) *searchIndex {
+	ix := &searchIndex{
+		postings: make(map[string][]posting),
+		lengths:  make([]int, len(products)),
+	}
+	for doc, p := range products {
+		occurrences := make(map[string][]float64)
+		for _, field := range []struct {
+			text   string
+			weight float64
+		}{{p.Name, nameWeight}, {p.Description, descriptionWeight}} {
+			for _, w := range analyze(field.text) {
+				if w.lexeme != "" {
+					occurrences[w.lexeme] = append(occurrences[w.lexeme], field.weight)
+					ix.lengths[doc]++
+				}
+			}
+		}
+		for lexeme, weights := range occurrences {
+			ix.postings[lexeme] = append(ix.postings[lexeme], posting{doc: doc, weights: weights})
+		}
+	}
+	return ix
+}
+
+// search returns the products containing every term of query, best first.
+func (ix *searchIndex) search(query string) []searchMatch {
+	terms := queryTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	// Intersect postings, starting from the rarest term.
+	slices.SortFunc(terms, func(a, b string) int {
+		return cmp.Compare(len(ix.postings[a]), len(ix.postings[b]))
+	})
+	scores := make(map[int]float64)
+	for _, pst := range ix.postings[terms[0]] {
+		scores[pst.doc] = termRank(pst.weights)
+	}
+	for _, term := range terms[1:] {
+		next := make(map[int]float64, len(scores))
+		for _, pst := range ix.postings[term] {
+			if score, ok := scores[pst.doc]; ok {
+				next[pst.doc] = score + termRank(pst.weights)
+			}
+		}
+		scores = next
+	}
+
+	matches := make([]searchMatch, 0, len(scores))
+	for doc, score := range scores {
+		score /= float64(len(terms))
+		score /= math.Log2(float64(ix.lengths[doc] + 1))
+		matches = append(matches, searchMatch{doc: doc, score: float32(score)})
+	}
+	slices.SortFunc(matches, func(a, b searchMatch) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.doc, b.doc))
+	})
+	return matches
+}
+
+// termRank scores the occurrences of one query term the way ts_rank does:
+// the best occurrence counts fully and the others with diminishing weight.
+func termRank(weights []float64) float64 {
+	const zeta2 = 1.64493406685 // sum of 1/n^2
+	var sum, best float64
+	var bestAt int
+	for i, w := range weights {
+		sum += w / float64((i+1)*(i+1))
+		if w > best {
+			best, bestAt = w, i
+		}
+	}
+	return (best + sum - best/float64((bestAt+1)*(bestAt+1))) / zeta2
+}
+
+// snippet returns up to snippetMaxWords words of text around the first match
+// of query, with matched words highlighted like ts_headline.
+func snippet(text, query string) string {
+	words := analyze(text)
+	if len(words) == 0 {
+		return ""
+	}
+	terms := queryTerms(query)
+
+	first := slices.IndexFunc(words, func(w word) bool {
+		return w.lexeme != "" && slices.Contains(terms, w.lexeme)
+	})
+	start := 0
+	if first > 0 && len(words) > snippetMaxWords {
+		start = min(first, len(words)-snippetMaxWords)
+	}
+	end := min(start+snippetMaxWords, len(words))
+
+	var b strings.Builder
+	pos := words[start].start
+	for _, w := range words[start:end] {
+		b.WriteString(text[pos:w.start])
+		if w.lexeme != "" && slices.Contains(terms, w.lexeme) {
+			b.WriteString(snippetStartSel + text[w.start:w.end] + snippetStopSel)
+		} else {
+			b.WriteString(text[w.start:w.end])
+		}
+		pos = w.end
+	}
+	return b.String()
+}
+
+// queryTerms returns the distinct lexemes of query.
+func queryTerms(query string) []string {
+	var terms []string
+	for _, w := range analyze(query) {
+		if w.lexeme != "" && !slices.Contains(terms, w.lexeme) {
+			terms = append(terms, w.lexeme)
+		}
+	}
+	return terms
+}
+
+// analyze splits text into words of letters and digits and stems them.
+// Stop words have an empty lexeme.
+func analyze(text string) []word {
+	var words []word
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		w := word{start: start, end: end}
+		if token := strings.ToLower(text[start:end]); !english.IsStopWord(token) {
+			w.lexeme = english.Stem(token, false)
+		}
+		words = append(words, w)
+		start = -1
+	}
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+		} else {
+			flush(i)
+		}
+	}
+	flush(len(text))
+	return words
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+package main
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	pb "github.com/opentelemetry/opentelemetry-demo/src/product-catalog/genproto/oteldemo"
+)
+
+func TestQueryTerms(t *testing.T) {
+	got := queryTerms("The Telescopes, and a telescope's lenses!")
+	want := []string{"telescop", "lens"}
+	if !slices.Equal(got, want) {
+		t.Errorf("queryTerms() = %q, want %q", got, want)
+	}
+}
+
+func TestSearchIndexRank(t *testing.T) {
+	ix := newSearchIndex([]*pb.Product{
+		{Id: "A", Name: "Telescope"},
+		{Id: "B", Name: "Lens", Description: "For a telescope."},
+		{Id: "C", Name: "Binoculars"},
+	})
+
+	matches := ix.search("telescopes")
+	if len(matches) != 2 || matches[0].doc != 0 || matches[1].doc != 1 {
+		t.Fatalf("search() = %+v, want docs 0 then 1", matches)
+	}
+
+	// ts_rank(setweight(to_tsvector('english', 'Telescope'), 'A'),
+	//         plainto_tsquery('english', 'telescopes'), 1)
+	if want := float32(0.6079271); math.Abs(float64(matches[0].score-want)) > 1e-6 {
+		t.Errorf("search() score = %v, want %v", matches[0].score, want)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		name, text, query, want string
+	}{
+		{"highlight", "Small telescope. Telescopes are great.", "telescope", "Small <b>telescope</b>. <b>Telescopes</b> are great"},
+		{"no match", "Just a lens.", "telescope", "Just a lens"},
+		{"empty", "", "telescope", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snippet(tt.text, tt.query); got != tt.want {
+				t.Errorf("snippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
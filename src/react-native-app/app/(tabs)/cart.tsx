@@ -16,6 +16,7 @@ import { IFormData } from "@/components/CheckoutForm/CheckoutForm";
 import Toast from "react-native-toast-message";
 import SessionGateway from "@/gateways/Session.gateway";
 import { useThemeColor } from "@/hooks/useThemeColor";
+import { v4 } from "uuid";
 
 export default function Cart() {
   const tint = useThemeColor({}, "tint");
@@ -67,6 +68,8 @@ export default function Cart() {
           creditCardExpirationYear,
           creditCardNumber,
         },
+        idempotencyKey: v4(),
+        couponCodes: [],
       });
 
       Toast.show({
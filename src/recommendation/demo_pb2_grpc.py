@@ -218,7 +218,7 @@ class ProductCatalogServiceStub(object):
         """
         self.ListProducts = channel.unary_unary(
                 '/oteldemo.ProductCatalogService/ListProducts',
-                request_serializer=demo__pb2.Empty.SerializeToString,
+                request_serializer=demo__pb2.ListProductsRequest.SerializeToString,
                 response_deserializer=demo__pb2.ListProductsResponse.FromString,
                 )
         self.GetProduct = channel.unary_unary(
@@ -226,6 +226,11 @@ class ProductCatalogServiceStub(object):
                 request_serializer=demo__pb2.GetProductRequest.SerializeToString,
                 response_deserializer=demo__pb2.Product.FromString,
                 )
+        self.GetProducts = channel.unary_unary(
+                '/oteldemo.ProductCatalogService/GetProducts',
+                request_serializer=demo__pb2.GetProductsRequest.SerializeToString,
+                response_deserializer=demo__pb2.GetProductsResponse.FromString,
+                )
         self.SearchProducts = channel.unary_unary(
                 '/oteldemo.ProductCatalogService/SearchProducts',
                 request_serializer=demo__pb2.SearchProductsRequest.SerializeToString,
@@ -250,6 +255,12 @@ class ProductCatalogServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def GetProducts(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
     def SearchProducts(self, request, context):
         """Missing associated documentation comment in .proto file."""
         context.set_code(grpc.StatusCode.UNIMPLEMENTED)
@@ -261,7 +272,7 @@ def add_ProductCatalogServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
             'ListProducts': grpc.unary_unary_rpc_method_handler(
                     servicer.ListProducts,
-                    request_deserializer=demo__pb2.Empty.FromString,
+                    request_deserializer=demo__pb2.ListProductsRequest.FromString,
                     response_serializer=demo__pb2.ListProductsResponse.SerializeToString,
             ),
             'GetProduct': grpc.unary_unary_rpc_method_handler(
@@ -269,6 +280,11 @@ def add_ProductCatalogServiceServicer_to_server(servicer, server):
                     request_deserializer=demo__pb2.GetProductRequest.FromString,
                     response_serializer=demo__pb2.Product.SerializeToString,
             ),
+            'GetProducts': grpc.unary_unary_rpc_method_handler(
+                    servicer.GetProducts,
+                    request_deserializer=demo__pb2.GetProductsRequest.FromString,
+                    response_serializer=demo__pb2.GetProductsResponse.SerializeToString,
+            ),
             'SearchProducts': grpc.unary_unary_rpc_method_handler(
                     servicer.SearchProducts,
                     request_deserializer=demo__pb2.SearchProductsRequest.FromString,
@@ -298,7 +314,7 @@ class ProductCatalogService(object):
             timeout=None,
             metadata=None):
         return grpc.experimental.unary_unary(request, target, '/oteldemo.ProductCatalogService/ListProducts',
-            demo__pb2.Empty.SerializeToString,
+            demo__pb2.ListProductsRequest.SerializeToString,
             demo__pb2.ListProductsResponse.FromString,
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
@@ -320,6 +336,23 @@ class ProductCatalogService(object):
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
 
+    @staticmethod
+    def GetProducts(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/oteldemo.ProductCatalogService/GetProducts',
+            demo__pb2.GetProductsRequest.SerializeToString,
+            demo__pb2.GetProductsResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
     @staticmethod
     def SearchProducts(request,
             target,
@@ -554,6 +587,11 @@ class PaymentServiceStub(object):
                 request_serializer=demo__pb2.ChargeRequest.SerializeToString,
                 response_deserializer=demo__pb2.ChargeResponse.FromString,
                 )
+        self.Refund = channel.unary_unary(
+                '/oteldemo.PaymentService/Refund',
+                request_serializer=demo__pb2.RefundRequest.SerializeToString,
+                response_deserializer=demo__pb2.RefundResponse.FromString,
+                )
 
 
 class PaymentServiceServicer(object):
@@ -567,6 +605,12 @@ class PaymentServiceServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def Refund(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_PaymentServiceServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -575,6 +619,11 @@ def add_PaymentServiceServicer_to_server(servicer, server):
                     request_deserializer=demo__pb2.ChargeRequest.FromString,
                     response_serializer=demo__pb2.ChargeResponse.SerializeToString,
             ),
+            'Refund': grpc.unary_unary_rpc_method_handler(
+                    servicer.Refund,
+                    request_deserializer=demo__pb2.RefundRequest.FromString,
+                    response_serializer=demo__pb2.RefundResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'oteldemo.PaymentService', rpc_method_handlers)
@@ -604,6 +653,23 @@ class PaymentService(object):
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
 
+    @staticmethod
+    def Refund(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/oteldemo.PaymentService/Refund',
+            demo__pb2.RefundRequest.SerializeToString,
+            demo__pb2.RefundResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
 
 class EmailServiceStub(object):
     """-------------Email service-----------------